@@ -3,10 +3,20 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	apiflag "github.com/dictyBase/aphgrpc"
 	arangoflag "github.com/dictyBase/arangomanager/command/flag"
+	"github.com/dictyBase/modware-content/internal/app/command"
 	"github.com/dictyBase/modware-content/internal/app/server"
+	"github.com/dictyBase/modware-content/internal/asset"
+	"github.com/dictyBase/modware-content/internal/backup"
+	"github.com/dictyBase/modware-content/internal/blob"
+	"github.com/dictyBase/modware-content/internal/cache"
+	"github.com/dictyBase/modware-content/internal/collab"
+	"github.com/dictyBase/modware-content/internal/linkcheck"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/watch"
 	"github.com/urfave/cli"
 )
 
@@ -34,6 +44,120 @@ func main() {
 			Action: server.RunServer,
 			Flags:  getServerFlags(),
 		},
+		{
+			Name:        "namespace",
+			Usage:       "manages the registry of content namespaces",
+			Subcommands: getNamespaceCommands(),
+		},
+		{
+			Name:   "transfer",
+			Usage:  "copies or moves contents from one namespace to another",
+			Action: command.TransferContents,
+			Flags:  getTransferFlags(),
+		},
+		{
+			Name:   "export",
+			Usage:  "dumps contents to a ndjson file or a tar.gz archive",
+			Action: command.ExportContents,
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "output, o",
+					Usage: "file to write the dump, defaults to standard output",
+				},
+				cli.StringSliceFlag{
+					Name:  "namespace",
+					Usage: "namespace to export, could be repeated, defaults to all",
+				},
+				formatFlag(),
+			}, getStorageFlags()...),
+		},
+		{
+			Name:   "import",
+			Usage:  "loads contents from a dump created by the export command",
+			Action: command.ImportContents,
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:     "input, i",
+					Usage:    "file with the dump",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "mode",
+					Usage: "upsert to replace or skip-existing to keep existing contents",
+					Value: command.ImportUpsert,
+				},
+				cli.StringFlag{
+					Name:  "actor",
+					Usage: "user recorded in the audit log, defaults to updated_by of the content",
+				},
+				formatFlag(),
+			}, getStorageFlags()...),
+		},
+		{
+			Name:   "migrate-from-postgres",
+			Usage:  "migrates contents from the legacy dictycontent postgres database",
+			Action: command.MigrateFromPostgres,
+			Flags:  getMigrateFlags(),
+		},
+		{
+			Name:   "audit",
+			Usage:  "lists the audit log of content changes",
+			Action: command.ListAudit,
+			Flags:  getAuditFlags(),
+		},
+		{
+			Name:   "diff",
+			Usage:  "shows the changes between the stored or a given editor json and a proposed one",
+			Action: command.DiffContents,
+			Flags:  getDiffFlags(),
+		},
+		{
+			Name:   "duplicates",
+			Usage:  "reports the contents that have the same content",
+			Action: command.ReportDuplicates,
+			Flags: append([]cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "namespace",
+					Usage: "namespace to look into, could be repeated, defaults to all",
+				},
+			}, getStorageFlags()...),
+		},
+		{
+			Name:        "lock",
+			Usage:       "manages the edit leases of contents",
+			Subcommands: getLockCommands(),
+		},
+		{
+			Name:   "check-links",
+			Usage:  "reports the broken external links of the contents",
+			Action: command.CheckLinks,
+			Flags: append(append([]cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "namespace",
+					Usage: "namespace to look into, could be repeated, defaults to all",
+				},
+				cli.StringFlag{
+					Name:  "output",
+					Usage: "file to write the report, defaults to stdout",
+				},
+			}, getLinkCheckFlags()...), getStorageFlags()...),
+		},
+		{
+			Name:        "links",
+			Usage:       "reports the links between contents",
+			Subcommands: getLinkCommands(),
+		},
+		{
+			Name:        "asset",
+			Usage:       "manages the embedded images and files",
+			Subcommands: getAssetCommands(),
+		},
+		{
+			Name:   "compact",
+			Usage:  "compacts the database file of the bolt backend, the server should be stopped",
+			Action: command.CompactBolt,
+			Flags:  []cli.Flag{boltPathFlag()},
+		},
 	}
 	if err := app.Run(os.Args); err != nil {
 		log.Fatalf("error in running command %s", err)
@@ -48,9 +172,202 @@ func getServerFlags() []cli.Flag {
 			Value: "9560",
 		},
 		cli.StringFlag{
-			Name:  "content-collection",
-			Usage: "arangodb collection for storing editor data",
-			Value: "serialized_json",
+			Name:  "metrics-port",
+			Usage: "tcp port of the http server for the prometheus metrics and the watch events",
+			Value: "9561",
+		},
+		cli.DurationFlag{
+			Name:  "checkpoint-interval",
+			Usage: "period for saving the documents of collaborative editing sessions",
+			Value: collab.DefaultInterval,
+		},
+		cli.StringFlag{
+			Name:  "nats-queue",
+			Usage: "nats queue group for answering requests, shared by the replicas",
+			Value: "modware-content",
+		},
+		cli.IntFlag{
+			Name:  "cache-size",
+			Usage: "number of contents kept in the slug cache, zero disables it",
+			Value: cache.DefaultSize,
+		},
+		cli.DurationFlag{
+			Name:  "cache-ttl",
+			Usage: "how long a content is served from the slug cache",
+			Value: cache.DefaultTTL,
+		},
+		cli.IntFlag{
+			Name:  "watch-retain",
+			Usage: "number of the latest content events kept for resuming a watch",
+			Value: watch.DefaultRetain,
+		},
+	}
+	flg = append(flg, getStorageFlags()...)
+	flg = append(flg, getBlobFlags()...)
+	flg = append(flg, getLinkCheckFlags()...)
+	flg = append(flg,
+		cli.DurationFlag{
+			Name:  "link-check-interval",
+			Usage: "period for checking the external links, zero disables it",
+		},
+		cli.StringFlag{
+			Name:  "link-check-report",
+			Usage: "file where the periodic link check writes its report",
+			Value: "link-report.json",
+		},
+	)
+	flg = append(flg, getTracingFlags()...)
+
+	return append(flg, apiflag.NatsFlag()...)
+}
+
+// getStorageFlags returns the flags of all the storage backends, the server
+// and the commands use the one given by the backend flag.
+func getStorageFlags() []cli.Flag {
+	flg := []cli.Flag{
+		cli.StringFlag{
+			Name:  "backend",
+			Usage: "storage backend, either of arangodb, postgres, bolt or memory",
+			Value: "arangodb",
+		},
+		cli.StringSliceFlag{
+			Name:  "register-namespace",
+			Usage: "namespace to register upfront with memory backend, could be repeated",
+		},
+		contentCollectionFlag(),
+	}
+	flg = append(flg, optionalFlags(getArangoFlags())...)
+	flg = append(flg, getPostgresFlags()...)
+
+	return append(flg, boltPathFlag())
+}
+
+func getPostgresFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   "postgres-user",
+			EnvVar: "POSTGRES_USER",
+			Usage:  "postgres database user",
+		},
+		cli.StringFlag{
+			Name:   "postgres-pass",
+			EnvVar: "POSTGRES_PASS",
+			Usage:  "postgres database password",
+		},
+		cli.StringFlag{
+			Name:   "postgres-database",
+			EnvVar: "POSTGRES_DATABASE",
+			Usage:  "postgres database name",
+			Value:  "content",
+		},
+		cli.StringFlag{
+			Name:   "postgres-host",
+			EnvVar: "POSTGRES_HOST",
+			Usage:  "postgres database host",
+			Value:  "localhost",
+		},
+		cli.IntFlag{
+			Name:   "postgres-port",
+			EnvVar: "POSTGRES_PORT",
+			Usage:  "postgres database port",
+			Value:  5432,
+		},
+		cli.StringFlag{
+			Name:   "postgres-sslmode",
+			EnvVar: "POSTGRES_SSLMODE",
+			Usage:  "ssl mode of postgres connection",
+			Value:  "disable",
+		},
+		cli.StringFlag{
+			Name:   "postgres-schema",
+			EnvVar: "POSTGRES_SCHEMA",
+			Usage:  "postgres schema where the tables are created",
+			Value:  "modware_content",
+		},
+	}
+}
+
+func getTracingFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   "tracing-exporter",
+			EnvVar: "TRACING_EXPORTER",
+			Usage:  "exporter of the trace spans, either of none, otlp or stdout",
+			Value:  "none",
+		},
+		cli.StringFlag{
+			Name:   "otlp-endpoint",
+			EnvVar: "OTEL_EXPORTER_OTLP_ENDPOINT",
+			Usage:  "host:port of the otlp collector",
+			Value:  "localhost:4317",
+		},
+		cli.BoolFlag{
+			Name:   "otlp-insecure",
+			EnvVar: "OTEL_EXPORTER_OTLP_INSECURE",
+			Usage:  "connect to the otlp collector without tls",
+		},
+		cli.StringFlag{
+			Name:  "tracing-file",
+			Usage: "file where the stdout exporter writes, defaults to stdout",
+		},
+		cli.Float64Flag{
+			Name:  "tracing-sample-ratio",
+			Usage: "fraction of the traces that are recorded",
+			Value: 1,
+		},
+	}
+}
+
+func boltPathFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:   "bolt-path",
+		EnvVar: "BOLT_PATH",
+		Usage:  "database file of the bolt backend, created if missing",
+		Value:  "modware-content.db",
+	}
+}
+
+// optionalFlags turns off the required check for the flags that are only
+// needed by some of the storage backends.
+func optionalFlags(flags []cli.Flag) []cli.Flag {
+	oflg := make([]cli.Flag, 0, len(flags))
+	for _, flag := range flags {
+		if sflag, ok := flag.(cli.StringFlag); ok {
+			sflag.Required = false
+			flag = sflag
+		}
+		oflg = append(oflg, flag)
+	}
+
+	return oflg
+}
+
+func getArangoFlags() []cli.Flag {
+	flg := []cli.Flag{
+		cli.StringFlag{
+			Name:  "namespace-collection",
+			Usage: "arangodb collection for storing namespaces",
+			Value: "namespace",
+		},
+		cli.StringFlag{
+			Name:  "audit-collection",
+			Usage: "arangodb collection for the audit log of content changes",
+			Value: "content_audit",
+		},
+		cli.StringFlag{
+			Name:  "lock-collection",
+			Usage: "arangodb collection for the edit leases of contents",
+			Value: "content_lock",
+		},
+		cli.StringFlag{
+			Name:  "link-collection",
+			Usage: "arangodb edge collection for the links between contents",
+			Value: "content_link",
+		},
+		cli.StringFlag{
+			Name:  "asset-collection",
+			Usage: "arangodb collection for the metadata of uploaded assets",
+			Value: "content_asset",
 		},
 		cli.StringFlag{
 			Name:   "arangodb-database, db",
@@ -59,7 +376,488 @@ func getServerFlags() []cli.Flag {
 			Value:  "content",
 		},
 	}
-	flg = append(flg, arangoflag.ArangoFlags()...)
+
+	return append(flg, arangoflag.ArangoFlags()...)
+}
+
+func getNamespaceCommands() []cli.Command {
+	nameFlag := cli.StringFlag{
+		Name:     "name",
+		Usage:    "name of the namespace",
+		Required: true,
+	}
+
+	return []cli.Command{
+		{
+			Name:   "create",
+			Usage:  "registers a new namespace",
+			Action: command.CreateNamespace,
+			Flags: append([]cli.Flag{
+				nameFlag,
+				cli.StringFlag{
+					Name:     "display-name",
+					Usage:    "human readable name of the namespace",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "editors-group",
+					Usage: "group of users that are allowed to edit the content",
+				},
+				cli.StringFlag{
+					Name:  "content-schema",
+					Usage: "json schema that the contents of the namespace have to match",
+				},
+				cli.StringFlag{
+					Name:     "created-by",
+					Usage:    "email of the user creating the namespace",
+					Required: true,
+				},
+			}, getStorageFlags()...),
+		},
+		{
+			Name:   "list",
+			Usage:  "lists all registered namespaces",
+			Action: command.ListNamespaces,
+			Flags:  getStorageFlags(),
+		},
+		{
+			Name:   "describe",
+			Usage:  "shows the settings of a namespace",
+			Action: command.DescribeNamespace,
+			Flags:  append([]cli.Flag{nameFlag}, getStorageFlags()...),
+		},
+		{
+			Name:   "archive",
+			Usage:  "archives a namespace, no new content could be added to it",
+			Action: command.ArchiveNamespace,
+			Flags:  append([]cli.Flag{nameFlag}, getStorageFlags()...),
+		},
+		{
+			Name:   "seed",
+			Usage:  "registers the namespaces of the stored contents that are not registered yet",
+			Action: command.SeedNamespaces,
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:     "created-by",
+					Usage:    "email of the user registering the namespaces",
+					Required: true,
+				},
+			}, getStorageFlags()...),
+		},
+	}
+}
+
+func getLockCommands() []cli.Command {
+	flg := []cli.Flag{
+		cli.Int64Flag{
+			Name:     "id",
+			Usage:    "id of the content",
+			Required: true,
+		},
+	}
+	flg = append(flg, getStorageFlags()...)
+	flg = append(flg, apiflag.NatsFlag()...)
+	holderFlag := cli.StringFlag{
+		Name:     "holder",
+		Usage:    "email of the user holding the lease",
+		Required: true,
+	}
+	ttlFlag := cli.DurationFlag{
+		Name:  "ttl",
+		Usage: "duration of the lease, at most an hour",
+		Value: 5 * time.Minute,
+	}
+
+	return []cli.Command{
+		{
+			Name:   model.LockAcquire,
+			Usage:  "takes the lease of a content or extends the own lease",
+			Action: command.ManageLock,
+			Flags:  append([]cli.Flag{holderFlag, ttlFlag}, flg...),
+		},
+		{
+			Name:   model.LockRenew,
+			Usage:  "extends the live lease of the holder",
+			Action: command.ManageLock,
+			Flags:  append([]cli.Flag{holderFlag, ttlFlag}, flg...),
+		},
+		{
+			Name:   model.LockRelease,
+			Usage:  "gives up the lease of the holder",
+			Action: command.ManageLock,
+			Flags:  append([]cli.Flag{holderFlag}, flg...),
+		},
+		{
+			Name:   model.LockBreak,
+			Usage:  "removes the lease of a content irrespective of its holder",
+			Action: command.ManageLock,
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:     "actor",
+					Usage:    "email of the user breaking the lease",
+					Required: true,
+				},
+			}, flg...),
+		},
+	}
+}
+
+func getLinkCommands() []cli.Command {
+	flg := getStorageFlags()
+	slugFlag := cli.StringFlag{
+		Name:     "slug",
+		Usage:    "slug of the content",
+		Required: true,
+	}
+
+	return []cli.Command{
+		{
+			Name:   "outgoing",
+			Usage:  "lists the links from a content",
+			Action: command.ReportLinks,
+			Flags:  append([]cli.Flag{slugFlag}, flg...),
+		},
+		{
+			Name:   "backlinks",
+			Usage:  "lists the links of other contents to a slug",
+			Action: command.ReportLinks,
+			Flags:  append([]cli.Flag{slugFlag}, flg...),
+		},
+		{
+			Name:   "broken",
+			Usage:  "lists the links to missing contents",
+			Action: command.ReportLinks,
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "namespace",
+					Usage: "namespace of the linking contents, defaults to all",
+				},
+			}, flg...),
+		},
+		{
+			Name:   "rebuild",
+			Usage:  "replaces the links of the contents from their stored json",
+			Action: command.RebuildLinks,
+			Flags: append([]cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "namespace",
+					Usage: "namespace to rebuild, could be repeated, defaults to all",
+				},
+			}, flg...),
+		},
+	}
+}
+
+func getAssetCommands() []cli.Command {
+	flg := append(getBlobFlags(), getStorageFlags()...)
+	idFlag := cli.Int64Flag{
+		Name:     "id",
+		Usage:    "id of the asset",
+		Required: true,
+	}
+
+	return []cli.Command{
+		{
+			Name:   "upload",
+			Usage:  "stores a file as a new asset",
+			Action: command.UploadAsset,
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:     "file",
+					Usage:    "file to upload",
+					Required: true,
+				},
+				cli.StringFlag{
+					Name:  "name",
+					Usage: "name of the asset, defaults to the name of the file",
+				},
+				cli.StringFlag{
+					Name:     "created-by",
+					Usage:    "email of the user uploading the asset",
+					Required: true,
+				},
+			}, flg...),
+		},
+		{
+			Name:   "download",
+			Usage:  "writes the file of an asset",
+			Action: command.DownloadAsset,
+			Flags: append([]cli.Flag{
+				idFlag,
+				cli.StringFlag{
+					Name:  "output",
+					Usage: "file to write, defaults to stdout",
+				},
+			}, flg...),
+		},
+		{
+			Name:   "delete",
+			Usage:  "removes an asset along with its file",
+			Action: command.DeleteAsset,
+			Flags: append([]cli.Flag{
+				idFlag,
+				cli.BoolFlag{
+					Name:  "force",
+					Usage: "removes the asset even when contents still embed it",
+				},
+			}, flg...),
+		},
+	}
+}
+
+func getLinkCheckFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.IntFlag{
+			Name:  "link-concurrency",
+			Usage: "number of external links checked at the same time",
+			Value: linkcheck.DefaultConcurrency,
+		},
+		cli.DurationFlag{
+			Name:  "link-host-interval",
+			Usage: "least time between two requests to the same host",
+			Value: linkcheck.DefaultHostInterval,
+		},
+		cli.DurationFlag{
+			Name:  "link-timeout",
+			Usage: "time limit for checking a single link",
+			Value: linkcheck.DefaultTimeout,
+		},
+		cli.DurationFlag{
+			Name:  "link-cache-ttl",
+			Usage: "how long the outcome of a link is reused",
+			Value: linkcheck.DefaultCacheTTL,
+		},
+		cli.StringFlag{
+			Name:  "link-cache",
+			Usage: "file that keeps the outcomes of the links between runs",
+		},
+	}
+}
+
+func getBlobFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   "blob-store",
+			EnvVar: "BLOB_STORE",
+			Usage:  "store of the asset files, either of none, local or s3",
+			Value:  "none",
+		},
+		cli.StringFlag{
+			Name:   "blob-path",
+			EnvVar: "BLOB_PATH",
+			Usage:  "directory of the local blob store, created if missing",
+			Value:  "modware-content-assets",
+		},
+		cli.Int64Flag{
+			Name:  "asset-max-size",
+			Usage: "largest asset in bytes that could be uploaded",
+			Value: asset.DefaultMaxSize,
+		},
+		cli.StringFlag{
+			Name:   "s3-endpoint",
+			EnvVar: "S3_ENDPOINT",
+			Usage:  "base url of the s3 compatible storage",
+		},
+		cli.StringFlag{
+			Name:   "s3-region",
+			EnvVar: "S3_REGION",
+			Usage:  "region of the s3 bucket",
+			Value:  "us-east-1",
+		},
+		cli.StringFlag{
+			Name:   "s3-bucket",
+			EnvVar: "S3_BUCKET",
+			Usage:  "bucket of the asset files",
+		},
+		cli.StringFlag{
+			Name:   "s3-access-key",
+			EnvVar: "S3_ACCESS_KEY",
+			Usage:  "access key of the s3 storage",
+		},
+		cli.StringFlag{
+			Name:   "s3-secret-key",
+			EnvVar: "S3_SECRET_KEY",
+			Usage:  "secret key of the s3 storage",
+		},
+		cli.DurationFlag{
+			Name:  "s3-timeout",
+			Usage: "longest wait for the s3 storage to answer a request",
+			Value: blob.DefaultS3Timeout,
+		},
+	}
+}
+
+func contentCollectionFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "content-collection",
+		Usage: "arangodb collection for storing editor data",
+		Value: "serialized_json",
+	}
+}
+
+func formatFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "format",
+		Usage: "format of the dump, either of ndjson or tar.gz",
+		Value: backup.FormatNDJSON,
+	}
+}
+
+func getAuditFlags() []cli.Flag {
+	flg := []cli.Flag{
+		cli.StringFlag{
+			Name:  "actor",
+			Usage: "only the changes made by this user",
+		},
+		cli.StringFlag{
+			Name:  "namespace",
+			Usage: "only the changes of contents in this namespace",
+		},
+		cli.StringFlag{
+			Name:  "from",
+			Usage: "only the changes at or after this time, in RFC3339 format",
+		},
+		cli.StringFlag{
+			Name:  "to",
+			Usage: "only the changes at or before this time, in RFC3339 format",
+		},
+		cli.IntFlag{
+			Name:  "limit",
+			Usage: "maximum number of entries, 0 lists all of them",
+			Value: 100,
+		},
+	}
+
+	return append(flg, getStorageFlags()...)
+}
+
+func getDiffFlags() []cli.Flag {
+	flg := []cli.Flag{
+		cli.Int64Flag{
+			Name:  "id",
+			Usage: "id of the stored content to compare, replaces --before",
+		},
+		cli.StringFlag{
+			Name:  "before",
+			Usage: "file with the editor json of the earlier version",
+		},
+		cli.StringFlag{
+			Name:     "after",
+			Usage:    "file with the editor json of the proposed version",
+			Required: true,
+		},
+		cli.BoolFlag{
+			Name:  "unified",
+			Usage: "include a unified diff of the plain text",
+		},
+		cli.IntFlag{
+			Name:  "context",
+			Usage: "lines of context around the changes of the unified diff",
+			Value: 3,
+		},
+	}
+
+	return append(flg, getStorageFlags()...)
+}
+
+func getTransferFlags() []cli.Flag {
+	flg := []cli.Flag{
+		cli.StringFlag{
+			Name:     "from",
+			Usage:    "namespace from which the contents are transferred",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "to",
+			Usage:    "namespace to which the contents are transferred",
+			Required: true,
+		},
+		cli.StringSliceFlag{
+			Name:  "slug",
+			Usage: "slug of content to transfer, could be repeated, defaults to all",
+		},
+		cli.StringFlag{
+			Name:  "slug-pattern",
+			Usage: "regular expression to match in the slugs of transferred contents, required for a copy",
+		},
+		cli.StringFlag{
+			Name:  "slug-replacement",
+			Usage: "replacement for the matches of slug pattern",
+		},
+		cli.BoolFlag{
+			Name:  "move",
+			Usage: "moves instead of copying the contents",
+		},
+		cli.StringFlag{
+			Name:     "updated-by",
+			Usage:    "email of the user performing the transfer",
+			Required: true,
+		},
+	}
+	flg = append(flg, getStorageFlags()...)
 
 	return append(flg, apiflag.NatsFlag()...)
 }
+
+func getMigrateFlags() []cli.Flag {
+	flg := []cli.Flag{
+		cli.StringFlag{
+			Name:     "dictycontent-pass",
+			EnvVar:   "DICTYCONTENT_PASS",
+			Usage:    "dictycontent database password",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "dictycontent-db",
+			EnvVar:   "DICTYCONTENT_DB",
+			Usage:    "dictycontent database name",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "dictycontent-user",
+			EnvVar:   "DICTYCONTENT_USER",
+			Usage:    "dictycontent database user",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:   "dictycontent-host",
+			EnvVar: "DICTYCONTENT_BACKEND_SERVICE_HOST",
+			Usage:  "dictycontent database host",
+			Value:  "dictycontent-backend",
+		},
+		cli.IntFlag{
+			Name:   "dictycontent-port",
+			EnvVar: "DICTYCONTENT_BACKEND_SERVICE_PORT",
+			Usage:  "dictycontent database port",
+			Value:  5432,
+		},
+		cli.StringFlag{
+			Name:  "dictycontent-sslmode",
+			Usage: "ssl mode of the dictycontent database connection",
+			Value: "disable",
+		},
+		cli.StringFlag{
+			Name:  "table",
+			Usage: "legacy table with the contents",
+			Value: "content",
+		},
+		cli.StringFlag{
+			Name:  "user-map",
+			Usage: "csv file mapping legacy user ids to their emails",
+		},
+		cli.StringFlag{
+			Name:  "default-email",
+			Usage: "email for the legacy user ids missing from the user map",
+		},
+		cli.BoolFlag{
+			Name:  "overwrite",
+			Usage: "replaces contents that are already migrated",
+		},
+		cli.StringFlag{
+			Name:  "report",
+			Usage: "file to write the reconciliation report, defaults to standard output",
+		},
+	}
+
+	return append(flg, getStorageFlags()...)
+}
@@ -0,0 +1,147 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dictyBase/modware-content/internal/asset"
+	"github.com/dictyBase/modware-content/internal/blob"
+	"github.com/urfave/cli"
+)
+
+// BlobStore creates the store of the asset files given in the command line
+// flags, it is nil when the assets are turned off.
+func BlobStore(clt *cli.Context) (blob.Store, error) {
+	switch clt.String("blob-store") {
+	case "none":
+		return nil, nil
+	case "local":
+		return blob.NewLocalStore(clt.String("blob-path"))
+	case "s3":
+		return blob.NewS3Store(&blob.S3Params{
+			Endpoint:  clt.String("s3-endpoint"),
+			Region:    clt.String("s3-region"),
+			Bucket:    clt.String("s3-bucket"),
+			AccessKey: clt.String("s3-access-key"),
+			SecretKey: clt.String("s3-secret-key"),
+			Timeout:   clt.Duration("s3-timeout"),
+		})
+	}
+
+	return nil, fmt.Errorf("unsupported blob store %s", clt.String("blob-store"))
+}
+
+// UploadAsset stores a local file as a new asset and prints its metadata.
+func UploadAsset(clt *cli.Context) error {
+	mgr, cleanup, err := assetManager(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	handle, err := os.Open(clt.String("file"))
+	if err != nil {
+		return cli.NewExitError(
+			fmt.Sprintf("error in opening file %s", err),
+			ExitError,
+		)
+	}
+	defer handle.Close()
+	name := clt.String("name")
+	if len(name) == 0 {
+		name = filepath.Base(clt.String("file"))
+	}
+	ast, err := mgr.Upload(
+		context.Background(),
+		name,
+		clt.String("created-by"),
+		handle,
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(ast)
+}
+
+// DownloadAsset writes the file of an asset to the output, stdout when it
+// is not given.
+func DownloadAsset(clt *cli.Context) error {
+	mgr, cleanup, err := assetManager(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	_, rdc, err := mgr.Download(context.Background(), clt.Int64("id"))
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer rdc.Close()
+	out := os.Stdout
+	if len(clt.String("output")) > 0 {
+		out, err = os.Create(clt.String("output"))
+		if err != nil {
+			return cli.NewExitError(
+				fmt.Sprintf("error in creating file %s", err),
+				ExitError,
+			)
+		}
+		defer out.Close()
+	}
+	if _, err := io.Copy(out, rdc); err != nil {
+		return cli.NewExitError(
+			fmt.Sprintf("error in writing asset %s", err),
+			ExitError,
+		)
+	}
+
+	return nil
+}
+
+// DeleteAsset removes an asset, one that is still embedded in a content is
+// only removed with the force flag.
+func DeleteAsset(clt *cli.Context) error {
+	mgr, cleanup, err := assetManager(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	slugs, err := mgr.Delete(
+		context.Background(),
+		clt.Int64("id"),
+		clt.Bool("force"),
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	for _, slug := range slugs {
+		fmt.Printf("dangling reference in %s\n", slug)
+	}
+
+	return nil
+}
+
+// assetManager creates the manager on the blob store and the repositories
+// of the command line, the repositories are released by the cleanup.
+func assetManager(clt *cli.Context) (*asset.Manager, func(), error) {
+	blobs, err := BlobStore(clt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blobs == nil {
+		return nil, nil, fmt.Errorf("blob store is required for assets")
+	}
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return asset.NewManager(&asset.Params{
+		Repo:     rps.Assets,
+		Blobs:    blobs,
+		Contents: rps.Content,
+		MaxSize:  clt.Int64("asset-max-size"),
+	}), rps.Close, nil
+}
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/urfave/cli"
+)
+
+// ListAudit prints the audit entries filtered by actor, namespace and time
+// range, oldest first.
+func ListAudit(clt *cli.Context) error {
+	flt := &model.AuditFilter{
+		Actor:     clt.String("actor"),
+		Namespace: clt.String("namespace"),
+		Limit:     clt.Int("limit"),
+	}
+	for name, val := range map[string]*time.Time{
+		"from": &flt.From,
+		"to":   &flt.To,
+	} {
+		if len(clt.String(name)) == 0 {
+			continue
+		}
+		tms, err := time.Parse(time.RFC3339, clt.String(name))
+		if err != nil {
+			return cli.NewExitError(
+				fmt.Sprintf("error in parsing %s %s", name, err),
+				ExitError,
+			)
+		}
+		*val = tms
+	}
+	srv, cleanup, err := storageService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	ents, err := srv.ListAudit(context.Background(), flt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(ents)
+}
+
+// auditRestore records the restore of a content from a dump, the before is
+// the content that is replaced, if any.
+func auditRestore(
+	ctx context.Context,
+	aud repository.AuditRepository,
+	actor, input string,
+	before, after *model.ContentDoc,
+) error {
+	if len(actor) == 0 {
+		actor = after.UpdatedBy
+	}
+	cid, _ := strconv.ParseInt(after.Key, 10, 64)
+	_, err := aud.AddEntry(ctx, &model.AuditEntry{
+		Actor:      actor,
+		Operation:  model.AuditRestore,
+		ContentID:  cid,
+		Slug:       after.Slug,
+		Namespace:  after.Namespace,
+		BeforeHash: model.ContentHash(before),
+		AfterHash:  model.ContentHash(after),
+		Client:     map[string]string{"command": "import", "input": input},
+	})
+
+	return err
+}
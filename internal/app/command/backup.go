@@ -0,0 +1,123 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/backup"
+	"github.com/urfave/cli"
+)
+
+const (
+	ImportUpsert = "upsert"
+	ImportSkip   = "skip-existing"
+)
+
+// ExportContents dumps the contents of the collection, or of the selected
+// namespaces, to a file.
+func ExportContents(clt *cli.Context) error {
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer rps.Close()
+	cnts, err := rps.Content.ListContents(
+		context.Background(),
+		clt.StringSlice("namespace"),
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	var out io.Writer = os.Stdout
+	if len(clt.String("output")) > 0 {
+		fh, err := os.Create(clt.String("output"))
+		if err != nil {
+			return cli.NewExitError(
+				fmt.Sprintf("error in creating output file %s", err),
+				ExitError,
+			)
+		}
+		defer fh.Close()
+		out = fh
+	}
+	err = backup.Write(out, clt.String("format"), &backup.Metadata{
+		Collection: clt.String("content-collection"),
+		Namespaces: clt.StringSlice("namespace"),
+		ExportedOn: time.Now(),
+	}, cnts)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	log.Printf("exported %d contents", len(cnts))
+
+	return nil
+}
+
+// ImportContents loads the contents from a dump created by the export
+// command, every written content is audited as a restore.
+func ImportContents(clt *cli.Context) error {
+	ctx := context.Background()
+	mode := clt.String("mode")
+	if mode != ImportUpsert && mode != ImportSkip {
+		return cli.NewExitError(
+			fmt.Sprintf("unsupported import mode %s", mode),
+			ExitError,
+		)
+	}
+	fh, err := os.Open(clt.String("input"))
+	if err != nil {
+		return cli.NewExitError(
+			fmt.Sprintf("error in opening input file %s", err),
+			ExitError,
+		)
+	}
+	defer fh.Close()
+	_, cnts, err := backup.Read(fh, clt.String("format"))
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer rps.Close()
+	repo, aud := rps.Content, rps.Audit
+	var written, skipped int
+	for _, cnt := range cnts {
+		cid, _ := strconv.ParseInt(cnt.Key, 10, 64)
+		before, err := repo.GetContent(ctx, cid)
+		if err != nil {
+			return cli.NewExitError(err.Error(), ExitError)
+		}
+		after, ok, err := repo.ImportContent(ctx, cnt, mode == ImportUpsert)
+		if err != nil {
+			return cli.NewExitError(
+				fmt.Sprintf("error in importing %s %s", cnt.Slug, err),
+				ExitError,
+			)
+		}
+		if !ok {
+			skipped++
+
+			continue
+		}
+		written++
+		err = auditRestore(
+			ctx, aud, clt.String("actor"), clt.String("input"), before, after,
+		)
+		if err != nil {
+			return cli.NewExitError(
+				fmt.Sprintf("error in auditing %s %s", cnt.Slug, err),
+				ExitError,
+			)
+		}
+	}
+	log.Printf("imported %d contents, skipped %d existing", written, skipped)
+
+	return nil
+}
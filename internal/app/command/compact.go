@@ -0,0 +1,29 @@
+package command
+
+import (
+	"log"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/repository/boltdb"
+	"github.com/urfave/cli"
+)
+
+// CompactBolt reclaims the space left behind in the database file of the
+// bolt backend.
+func CompactBolt(clt *cli.Context) error {
+	stats, err := boltdb.Compact(
+		clt.String("bolt-path"),
+		Timeout*time.Second,
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	log.Printf(
+		"compacted %s from %d to %d bytes",
+		clt.String("bolt-path"),
+		stats.Before,
+		stats.After,
+	)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dictyBase/modware-content/internal/diff"
+	"github.com/urfave/cli"
+)
+
+// DiffContents prints the structural diff either between the stored content
+// and a proposed file or between two files of editor json.
+func DiffContents(clt *cli.Context) error {
+	after, err := os.ReadFile(clt.String("after"))
+	if err != nil {
+		return cli.NewExitError(
+			fmt.Sprintf("error in reading after version %s", err),
+			ExitError,
+		)
+	}
+	before, err := beforeVersion(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	res, err := diff.Compare(before, string(after), &diff.Options{
+		Unified: clt.Bool("unified"),
+		Context: clt.Int("context"),
+	})
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(res)
+}
+
+// beforeVersion reads the content given by id from the repository or else
+// the before file.
+func beforeVersion(clt *cli.Context) (string, error) {
+	if clt.Int64("id") == 0 {
+		if len(clt.String("before")) == 0 {
+			return "", fmt.Errorf("either of id or before is required")
+		}
+		data, err := os.ReadFile(clt.String("before"))
+		if err != nil {
+			return "", fmt.Errorf("error in reading before version %s", err)
+		}
+
+		return string(data), nil
+	}
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return "", err
+	}
+	defer rps.Close()
+	repo := rps.Content
+	mcont, err := repo.GetContent(context.Background(), clt.Int64("id"))
+	if err != nil {
+		return "", err
+	}
+	if mcont.NotFound {
+		return "", fmt.Errorf("content %d not found", clt.Int64("id"))
+	}
+
+	return mcont.Content, nil
+}
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"context"
+
+	"github.com/urfave/cli"
+)
+
+// ReportDuplicates prints the groups of contents that have the same content
+// going by their hash.
+func ReportDuplicates(clt *cli.Context) error {
+	srv, cleanup, err := storageService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	dups, err := srv.DuplicateContents(
+		context.Background(),
+		clt.StringSlice("namespace"),
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(dups)
+}
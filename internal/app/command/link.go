@@ -0,0 +1,117 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/urfave/cli"
+)
+
+// ReportLinks prints the outgoing links or the backlinks of a slug or the
+// broken links of a namespace, the report is the name of the subcommand.
+func ReportLinks(clt *cli.Context) error {
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer rps.Close()
+	lnks, err := linkReport(context.Background(), clt, rps.Content, rps.Links)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(lnks)
+}
+
+// RebuildLinks replaces the links of every content of the namespaces from
+// its stored json, it tracks the links of the imported or restored contents.
+func RebuildLinks(clt *cli.Context) error {
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer rps.Close()
+	count, err := rebuildLinks(
+		context.Background(),
+		rps.Content,
+		rps.Links,
+		clt.StringSlice("namespace"),
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	fmt.Printf("rebuilt the links of %d contents\n", count)
+
+	return nil
+}
+
+func rebuildLinks(
+	ctx context.Context,
+	repo repository.ContentRepository,
+	lrepo repository.LinkRepository,
+	namespaces []string,
+) (int, error) {
+	cnts, err := repo.ListContents(ctx, namespaces)
+	if err != nil {
+		return 0, fmt.Errorf("error in listing contents %s", err)
+	}
+	for _, mcont := range cnts {
+		cid, err := strconv.ParseInt(mcont.Key, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"error in parsing the id of %s %s",
+				mcont.Slug,
+				err,
+			)
+		}
+		err = lrepo.SetLinks(
+			ctx,
+			&model.LinkDoc{
+				ContentID: cid,
+				Slug:      mcont.Slug,
+				Namespace: mcont.Namespace,
+			},
+			links.LinkRefs(mcont.Content),
+		)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"error in setting the links of %s %s",
+				mcont.Slug,
+				err,
+			)
+		}
+	}
+
+	return len(cnts), nil
+}
+
+func linkReport(
+	ctx context.Context,
+	clt *cli.Context,
+	repo repository.ContentRepository,
+	lrepo repository.LinkRepository,
+) ([]*model.LinkDoc, error) {
+	switch clt.Command.Name {
+	case "outgoing":
+		mcont, err := repo.GetContentBySlug(ctx, clt.String("slug"))
+		if err != nil {
+			return nil, err
+		}
+		if mcont.NotFound {
+			return nil, fmt.Errorf("slug %s not found", clt.String("slug"))
+		}
+		cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+
+		return lrepo.OutgoingLinks(ctx, cid)
+	case "backlinks":
+		return lrepo.Backlinks(ctx, clt.String("slug"))
+	case "broken":
+		return links.FindBroken(ctx, repo, lrepo, clt.String("namespace"))
+	}
+
+	return nil, fmt.Errorf("unknown link report %s", clt.Command.Name)
+}
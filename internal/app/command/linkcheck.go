@@ -0,0 +1,168 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/linkcheck"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/urfave/cli"
+)
+
+// LinkChecker creates the checker of external links from the command line
+// flags.
+func LinkChecker(clt *cli.Context) *linkcheck.Checker {
+	return linkcheck.NewChecker(&linkcheck.Params{
+		Concurrency:  clt.Int("link-concurrency"),
+		HostInterval: clt.Duration("link-host-interval"),
+		Timeout:      clt.Duration("link-timeout"),
+		CacheTTL:     clt.Duration("link-cache-ttl"),
+	})
+}
+
+// CheckLinks checks the external links of the contents and writes the
+// report of the broken ones to the output, stdout when it is not given.
+func CheckLinks(clt *cli.Context) error {
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer rps.Close()
+	chk := LinkChecker(clt)
+	cache := clt.String("link-cache")
+	if err := LoadLinkCache(chk, cache); err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	report, err := CheckNamespaces(
+		context.Background(),
+		rps.Content,
+		chk,
+		clt.StringSlice("namespace"),
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	if err := SaveLinkCache(chk, cache); err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	if len(clt.String("output")) == 0 {
+		return printJSON(report)
+	}
+	if err := WriteLinkReport(clt.String("output"), report); err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return nil
+}
+
+// CheckNamespaces checks the links of one namespace at a time, so only the
+// contents of a single namespace are held in memory, and joins the reports.
+// No namespaces checks all of them. A url shared by the namespaces is
+// requested once and counted in every one of them.
+func CheckNamespaces(
+	ctx context.Context,
+	repo repository.ContentRepository,
+	chk *linkcheck.Checker,
+	namespaces []string,
+) (*linkcheck.Report, error) {
+	if len(namespaces) == 0 {
+		counts, err := repo.CountByNamespace(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error in counting contents %s", err)
+		}
+		for nsp := range counts {
+			namespaces = append(namespaces, nsp)
+		}
+	}
+	sort.Strings(namespaces)
+	report := &linkcheck.Report{
+		Contents:  make([]*linkcheck.ContentReport, 0),
+		CreatedOn: time.Now().UTC(),
+	}
+	for _, nsp := range namespaces {
+		cnts, err := repo.ListContents(ctx, []string{nsp})
+		if err != nil {
+			return nil, fmt.Errorf("error in listing contents of %s %s", nsp, err)
+		}
+		nrp, err := chk.CheckContents(ctx, cnts)
+		if err != nil {
+			return nil, err
+		}
+		report.Contents = append(report.Contents, nrp.Contents...)
+		report.Checked += nrp.Checked
+		report.Broken += nrp.Broken
+	}
+
+	return report, nil
+}
+
+// LoadLinkCache fills the cache of the checker from the file, no path or a
+// missing file leaves it empty.
+func LoadLinkCache(chk *linkcheck.Checker, path string) error {
+	if len(path) == 0 {
+		return nil
+	}
+	fh, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error in opening link cache %s", err)
+	}
+	defer fh.Close()
+
+	return chk.ReadCache(fh)
+}
+
+// SaveLinkCache replaces the file with the cache of the checker, it does
+// nothing without a path.
+func SaveLinkCache(chk *linkcheck.Checker, path string) error {
+	if len(path) == 0 {
+		return nil
+	}
+
+	return replaceFile(path, chk.WriteCache)
+}
+
+// WriteLinkReport replaces the file with the report, a reader never sees a
+// partly written report.
+func WriteLinkReport(path string, report *linkcheck.Report) error {
+	return replaceFile(path, func(wrt io.Writer) error {
+		enc := json.NewEncoder(wrt)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("error in writing report %s", err)
+		}
+
+		return nil
+	})
+}
+
+// replaceFile writes to a temporary file that is then renamed to the path.
+func replaceFile(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".link-*")
+	if err != nil {
+		return fmt.Errorf("error in creating file %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error in writing file %s", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error in saving file %s", err)
+	}
+
+	return nil
+}
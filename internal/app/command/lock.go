@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/urfave/cli"
+)
+
+// ManageLock acquires, renews, releases or breaks the edit lease of a
+// content, the action is the name of the subcommand.
+func ManageLock(clt *cli.Context) error {
+	srv, cleanup, err := contentService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	lck, err := lockAction(context.Background(), clt, srv)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(lck)
+}
+
+func lockAction(
+	ctx context.Context,
+	clt *cli.Context,
+	srv *service.ContentService,
+) (*model.LockDoc, error) {
+	cid, holder := clt.Int64("id"), clt.String("holder")
+	switch clt.Command.Name {
+	case model.LockAcquire:
+		return srv.AcquireLock(ctx, cid, holder, clt.Duration("ttl"))
+	case model.LockRenew:
+		return srv.RenewLock(ctx, cid, holder, clt.Duration("ttl"))
+	case model.LockRelease:
+		if err := srv.ReleaseLock(ctx, cid, holder); err != nil {
+			return nil, err
+		}
+
+		return &model.LockDoc{ContentID: cid, Holder: holder}, nil
+	case model.LockBreak:
+		return srv.BreakLock(ctx, cid, clt.String("actor"))
+	}
+
+	return nil, fmt.Errorf("unknown lock action %s", clt.Command.Name)
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dictyBase/modware-content/internal/migrate"
+	"github.com/dictyBase/modware-content/internal/repository/postgres"
+	"github.com/urfave/cli"
+)
+
+// MigrateFromPostgres copies the contents of the legacy dictycontent
+// postgres database to arangodb and writes a reconciliation report.
+func MigrateFromPostgres(clt *cli.Context) error {
+	ctx := context.Background()
+	usr, err := userEmails(clt.String("user-map"), clt.String("default-email"))
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	cnp := &postgres.ConnectParams{
+		Host:     clt.String("dictycontent-host"),
+		Port:     clt.Int("dictycontent-port"),
+		User:     clt.String("dictycontent-user"),
+		Pass:     clt.String("dictycontent-pass"),
+		Database: clt.String("dictycontent-db"),
+		SSLMode:  clt.String("dictycontent-sslmode"),
+	}
+	dbh, err := sql.Open("postgres", cnp.DSN())
+	if err != nil {
+		return cli.NewExitError(
+			fmt.Sprintf("error in opening postgres connection %s", err),
+			ExitError,
+		)
+	}
+	defer dbh.Close()
+	lcnts, err := migrate.ReadLegacy(
+		ctx,
+		dbh,
+		clt.String("table"),
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer rps.Close()
+	repo := rps.Content
+	rpt := migrate.Migrate(ctx, repo, lcnts, usr, clt.Bool("overwrite"))
+	if err := writeReport(clt.String("report"), rpt); err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	log.Printf(
+		"migrated %d of %d contents, skipped %d, failed %d, mismatched %d",
+		rpt.Migrated, rpt.Source, rpt.Skipped, rpt.Failed, rpt.Mismatch,
+	)
+	if rpt.Failed > 0 || rpt.Mismatch > 0 {
+		return cli.NewExitError("migration is incomplete", ExitError)
+	}
+
+	return nil
+}
+
+// userEmails reads the mapping of legacy user ids to emails from a csv
+// file with id and email columns.
+func userEmails(file, fallback string) (*migrate.UserEmails, error) {
+	usr := &migrate.UserEmails{
+		Emails:  make(map[int64]string),
+		Default: fallback,
+	}
+	if len(file) == 0 {
+		return usr, nil
+	}
+	fh, err := os.Open(file)
+	if err != nil {
+		return usr, fmt.Errorf("error in opening user map file %s", err)
+	}
+	defer fh.Close()
+	rdr := csv.NewReader(fh)
+	rdr.FieldsPerRecord = 2
+	for {
+		rec, err := rdr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return usr, fmt.Errorf("error in reading user map file %s", err)
+		}
+		uid, err := strconv.ParseInt(strings.TrimSpace(rec[0]), 10, 64)
+		if err != nil {
+			return usr, fmt.Errorf("invalid user id %s", rec[0])
+		}
+		usr.Emails[uid] = strings.TrimSpace(rec[1])
+	}
+
+	return usr, nil
+}
+
+func writeReport(file string, rpt *migrate.Report) error {
+	out := os.Stdout
+	if len(file) > 0 {
+		fh, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("error in creating report file %s", err)
+		}
+		defer fh.Close()
+		out = fh
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rpt); err != nil {
+		return fmt.Errorf("error in writing report %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/urfave/cli"
+)
+
+// CreateNamespace registers a new namespace along with its settings.
+func CreateNamespace(clt *cli.Context) error {
+	srv, cleanup, err := storageService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	nsp, err := srv.CreateNamespace(context.Background(), &model.NamespaceDoc{
+		Name:          clt.String("name"),
+		DisplayName:   clt.String("display-name"),
+		EditorsGroup:  clt.String("editors-group"),
+		ContentSchema: clt.String("content-schema"),
+		CreatedBy:     clt.String("created-by"),
+	})
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(nsp)
+}
+
+// ListNamespaces prints all the registered namespaces.
+func ListNamespaces(clt *cli.Context) error {
+	srv, cleanup, err := storageService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	nsps, err := srv.ListNamespaces(context.Background())
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(nsps)
+}
+
+// DescribeNamespace prints the settings of a single namespace.
+func DescribeNamespace(clt *cli.Context) error {
+	srv, cleanup, err := storageService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	nsp, err := srv.GetNamespace(context.Background(), clt.String("name"))
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(nsp)
+}
+
+// ArchiveNamespace marks a namespace as archived, no new content could be
+// stored in it afterwards.
+func ArchiveNamespace(clt *cli.Context) error {
+	srv, cleanup, err := storageService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	nsp, err := srv.ArchiveNamespace(context.Background(), clt.String("name"))
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(nsp)
+}
+
+// SeedNamespaces registers the namespaces of the stored contents that are
+// not registered yet and prints them.
+func SeedNamespaces(clt *cli.Context) error {
+	srv, cleanup, err := storageService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	nsps, err := srv.SeedNamespaces(
+		context.Background(),
+		clt.String("created-by"),
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+
+	return printJSON(nsps)
+}
+
+func printJSON(data interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return cli.NewExitError(
+			fmt.Sprintf("error in encoding output %s", err),
+			ExitError,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/aphgrpc"
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/message/nats"
+	gnats "github.com/nats-io/nats.go"
+	"github.com/urfave/cli"
+)
+
+const (
+	ExitError = 2
+	Timeout   = 10
+)
+
+// ArangoParams builds the arangodb connection parameters from the command
+// line flags.
+func ArangoParams(
+	clt *cli.Context,
+) *manager.ConnectParams {
+	arPort, _ := strconv.Atoi(clt.String("arangodb-port"))
+
+	return &manager.ConnectParams{
+		User:     clt.String("arangodb-user"),
+		Pass:     clt.String("arangodb-pass"),
+		Database: clt.String("arangodb-database"),
+		Host:     clt.String("arangodb-host"),
+		Port:     arPort,
+		Istls:    clt.Bool("is-secure"),
+	}
+}
+
+// GrpcOptions returns the options for configuring the content service.
+func GrpcOptions() []aphgrpc.Option {
+	return []aphgrpc.Option{aphgrpc.TopicsOption(Topics())}
+}
+
+// Topics maps the content events and requests to their nats subjects.
+func Topics() map[string]string {
+	return map[string]string{
+		"contentCreate":    "ContentService.Create",
+		"contentDelete":    "ContentService.Delete",
+		"contentUpdate":    "ContentService.Update",
+		"contentLock":      "ContentService.Lock",
+		"contentGet":       "ContentService.Get",
+		"contentGetBySlug": "ContentService.GetBySlug",
+	}
+}
+
+// NatsPublisher connects to the nats messaging server given in the command
+// line flags.
+func NatsPublisher(clt *cli.Context) (message.Publisher, error) {
+	msp, err := nats.NewPublisher(
+		clt.String("nats-host"), clt.String("nats-port"),
+		gnats.MaxReconnects(-1), gnats.ReconnectWait(Timeout*time.Second),
+	)
+	if err != nil {
+		return msp, fmt.Errorf("cannot connect to messaging server %s", err)
+	}
+
+	return msp, nil
+}
@@ -0,0 +1,249 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/repository/arangodb"
+	"github.com/dictyBase/modware-content/internal/repository/boltdb"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/repository/postgres"
+	"github.com/urfave/cli"
+)
+
+// Repositories are the repositories of the storage backend given in the
+// command line, the server and every command use the same backend.
+type Repositories struct {
+	Content    repository.ContentRepository
+	Namespaces repository.NamespaceRepository
+	Audit      repository.AuditRepository
+	Locks      repository.LockRepository
+	Assets     repository.AssetRepository
+	Links      repository.LinkRepository
+}
+
+// Lifecycles returns the repositories for health checks and closing.
+func (rps *Repositories) Lifecycles() []repository.Lifecycle {
+	return []repository.Lifecycle{
+		rps.Content, rps.Namespaces, rps.Audit,
+		rps.Locks, rps.Assets, rps.Links,
+	}
+}
+
+// Close releases the storage of all the repositories, the failures are
+// only logged.
+func (rps *Repositories) Close() {
+	for _, lcl := range rps.Lifecycles() {
+		if err := lcl.Close(); err != nil {
+			log.Printf("error in closing repository %s", err)
+		}
+	}
+}
+
+// OpenRepositories creates the repositories of the backend flag.
+func OpenRepositories(clt *cli.Context) (*Repositories, error) {
+	switch clt.String("backend") {
+	case "arangodb":
+		return arangoRepositories(clt)
+	case "postgres":
+		return postgresRepositories(clt)
+	case "bolt":
+		return boltRepositories(clt)
+	case "memory":
+		return memoryRepositories(clt)
+	}
+
+	return &Repositories{}, fmt.Errorf(
+		"unsupported backend %s",
+		clt.String("backend"),
+	)
+}
+
+func arangoRepositories(clt *cli.Context) (*Repositories, error) {
+	for _, name := range []string{"arangodb-user", "arangodb-pass"} {
+		if len(clt.String(name)) == 0 {
+			return &Repositories{}, fmt.Errorf(
+				"%s is required for arangodb backend",
+				name,
+			)
+		}
+	}
+	anrepo, err := arangodb.NewContentRepo(
+		ArangoParams(clt),
+		clt.String("content-collection"),
+	)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf(
+				"cannot connect to arangodb annotation repository %s",
+				err,
+			)
+	}
+	nsrepo, err := arangodb.NewNamespaceRepo(
+		ArangoParams(clt),
+		clt.String("namespace-collection"),
+	)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf(
+				"cannot connect to arangodb namespace repository %s",
+				err,
+			)
+	}
+	audrepo, err := arangodb.NewAuditRepo(
+		ArangoParams(clt),
+		clt.String("audit-collection"),
+	)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot connect to arangodb audit repository %s", err)
+	}
+	lckrepo, err := arangodb.NewLockRepo(
+		ArangoParams(clt),
+		clt.String("lock-collection"),
+	)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot connect to arangodb lock repository %s", err)
+	}
+	astrepo, err := arangodb.NewAssetRepo(
+		ArangoParams(clt),
+		clt.String("asset-collection"),
+	)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot connect to arangodb asset repository %s", err)
+	}
+	lnkrepo, err := arangodb.NewLinkRepo(
+		ArangoParams(clt),
+		clt.String("link-collection"),
+		clt.String("content-collection"),
+	)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot connect to arangodb link repository %s", err)
+	}
+
+	return &Repositories{
+		Content:    anrepo,
+		Namespaces: nsrepo,
+		Audit:      audrepo,
+		Locks:      lckrepo,
+		Assets:     astrepo,
+		Links:      lnkrepo,
+	}, nil
+}
+
+// postgresRepositories creates repositories that share a single pool of
+// connections to the database.
+func postgresRepositories(clt *cli.Context) (*Repositories, error) {
+	for _, name := range []string{"postgres-user", "postgres-pass"} {
+		if len(clt.String(name)) == 0 {
+			return &Repositories{}, fmt.Errorf(
+				"%s is required for postgres backend",
+				name,
+			)
+		}
+	}
+	pdb, err := postgres.Open(&postgres.ConnectParams{
+		User:     clt.String("postgres-user"),
+		Pass:     clt.String("postgres-pass"),
+		Database: clt.String("postgres-database"),
+		Host:     clt.String("postgres-host"),
+		Port:     clt.Int("postgres-port"),
+		SSLMode:  clt.String("postgres-sslmode"),
+		Schema:   clt.String("postgres-schema"),
+	})
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot connect to postgres database %s", err)
+	}
+
+	return &Repositories{
+		Content:    postgres.NewContentRepo(pdb),
+		Namespaces: postgres.NewNamespaceRepo(pdb),
+		Audit:      postgres.NewAuditRepo(pdb),
+		Locks:      postgres.NewLockRepo(pdb),
+		Assets:     postgres.NewAssetRepo(pdb),
+		Links:      postgres.NewLinkRepo(pdb),
+	}, nil
+}
+
+// boltRepositories creates repositories that share a single database file.
+func boltRepositories(clt *cli.Context) (*Repositories, error) {
+	dbh, err := boltdb.Open(clt.String("bolt-path"), Timeout*time.Second)
+	if err != nil {
+		return &Repositories{}, err
+	}
+	brepo, err := boltdb.NewContentRepo(dbh)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot create bolt content repository %s", err)
+	}
+	nsrepo, err := boltdb.NewNamespaceRepo(dbh)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot create bolt namespace repository %s", err)
+	}
+	audrepo, err := boltdb.NewAuditRepo(dbh)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot create bolt audit repository %s", err)
+	}
+	lckrepo, err := boltdb.NewLockRepo(dbh)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot create bolt lock repository %s", err)
+	}
+	astrepo, err := boltdb.NewAssetRepo(dbh)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot create bolt asset repository %s", err)
+	}
+	lnkrepo, err := boltdb.NewLinkRepo(dbh)
+	if err != nil {
+		return &Repositories{},
+			fmt.Errorf("cannot create bolt link repository %s", err)
+	}
+
+	return &Repositories{
+		Content:    brepo,
+		Namespaces: nsrepo,
+		Audit:      audrepo,
+		Locks:      lckrepo,
+		Assets:     astrepo,
+		Links:      lnkrepo,
+	}, nil
+}
+
+// memoryRepositories creates repositories that keep everything in memory,
+// the namespaces given in the command line are registered upfront.
+func memoryRepositories(clt *cli.Context) (*Repositories, error) {
+	nsrepo := memory.NewNamespaceRepo()
+	for _, name := range clt.StringSlice("register-namespace") {
+		_, err := nsrepo.AddNamespace(context.Background(), &model.NamespaceDoc{
+			Name:        name,
+			DisplayName: name,
+			CreatedBy:   "modware-content@dictybase.org",
+		})
+		if err != nil {
+			return &Repositories{}, fmt.Errorf(
+				"error in registering namespace %s",
+				err,
+			)
+		}
+	}
+
+	return &Repositories{
+		Content:    memory.NewContentRepo(),
+		Namespaces: nsrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Assets:     memory.NewAssetRepo(),
+		Links:      memory.NewLinkRepo(),
+	}, nil
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"context"
+	"log"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/urfave/cli"
+)
+
+// TransferContents copies or moves the contents of a namespace to another
+// namespace.
+func TransferContents(clt *cli.Context) error {
+	srv, cleanup, err := contentService(clt)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer cleanup()
+	ctnts, err := srv.TransferContents(
+		context.Background(),
+		&model.ContentTransfer{
+			From:            clt.String("from"),
+			To:              clt.String("to"),
+			Slugs:           clt.StringSlice("slug"),
+			SlugPattern:     clt.String("slug-pattern"),
+			SlugReplacement: clt.String("slug-replacement"),
+			Move:            clt.Bool("move"),
+			UpdatedBy:       clt.String("updated-by"),
+		},
+	)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	for _, ctnt := range ctnts {
+		log.Printf(
+			"transferred content %d to %s/%s",
+			ctnt.Data.Id,
+			ctnt.Data.Attributes.Namespace,
+			ctnt.Data.Attributes.Slug,
+		)
+	}
+
+	return nil
+}
+
+// contentService creates the service on the repositories and the nats
+// publisher of the command line, the cleanup releases both of them.
+func contentService(
+	clt *cli.Context,
+) (*service.ContentService, func(), error) {
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return nil, nil, err
+	}
+	msp, err := NatsPublisher(clt)
+	if err != nil {
+		rps.Close()
+
+		return nil, nil, err
+	}
+	cleanup := func() {
+		msp.Close()
+		rps.Close()
+	}
+	srv, err := service.NewContentService(&service.Params{
+		Repository: rps.Content,
+		Namespaces: rps.Namespaces,
+		Audit:      rps.Audit,
+		Locks:      rps.Locks,
+		Links:      rps.Links,
+		Publisher:  msp,
+		Group:      "groups",
+		Options:    GrpcOptions(),
+	})
+	if err != nil {
+		cleanup()
+
+		return nil, nil, err
+	}
+
+	return srv, cleanup, nil
+}
+
+// storageService creates the service on the repositories of the command
+// line alone, for the commands that do not publish any content.
+func storageService(
+	clt *cli.Context,
+) (*service.ContentService, func(), error) {
+	rps, err := OpenRepositories(clt)
+	if err != nil {
+		return nil, nil, err
+	}
+	srv, err := service.NewContentService(&service.Params{
+		Repository: rps.Content,
+		Namespaces: rps.Namespaces,
+		Audit:      rps.Audit,
+		Locks:      rps.Locks,
+		Links:      rps.Links,
+		Publisher:  discardPublisher{},
+		Group:      "groups",
+		Options:    GrpcOptions(),
+	})
+	if err != nil {
+		rps.Close()
+
+		return nil, nil, err
+	}
+
+	return srv, rps.Close, nil
+}
+
+// discardPublisher drops the published contents.
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(
+	context.Context,
+	string,
+	*content.Content,
+) error {
+	return nil
+}
+
+func (discardPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/repository"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// interval between the checks of the repository storage.
+const healthInterval = 15 * time.Second
+
+// watchHealth pings the storage of the repositories at regular interval
+// and reflects the outcome in the serving status of the health service.
+func watchHealth(
+	ctx context.Context,
+	hsrv *health.Server,
+	lcs ...repository.Lifecycle,
+) {
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+	for {
+		status := grpc_health_v1.HealthCheckResponse_SERVING
+		for _, lcl := range lcs {
+			if err := lcl.Ping(ctx); err != nil {
+				log.Printf("error in health check %s", err)
+				status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+
+				break
+			}
+		}
+		hsrv.SetServingStatus("", status)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serveHTTP exposes the handlers over http, keyed by their path, until the
+// context is done. The requests are cancelled along with the context, so
+// the open watch streams do not hold up the shutdown.
+func serveHTTP(
+	ctx context.Context,
+	port string,
+	handlers map[string]http.Handler,
+) {
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.Handle(path, handler)
+	}
+	hsrv := &http.Server{
+		Addr:              fmt.Sprintf(":%s", port),
+		Handler:           mux,
+		ReadHeaderTimeout: healthInterval,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		//nolint:errcheck
+		hsrv.Shutdown(context.Background())
+	}()
+	log.Printf("starting http server on %s", hsrv.Addr)
+	if err := hsrv.ListenAndServe(); err != nil &&
+		!errors.Is(err, http.ErrServerClosed) {
+		log.Printf("error in running http server %s", err)
+	}
+}
+
+// closeAll releases the repositories and the message publisher once the
+// server has stopped.
+func closeAll(spn *serverParams) {
+	lcs := []repository.Lifecycle{
+		spn.repo, spn.nsp, spn.aud, spn.lck, spn.ast, spn.lnk,
+	}
+	for _, lcl := range lcs {
+		if err := lcl.Close(); err != nil {
+			log.Printf("error in closing repository %s", err)
+		}
+	}
+	if err := spn.sub.Close(); err != nil {
+		log.Printf("error in closing message subscriber %s", err)
+	}
+	if err := spn.rpl.Close(); err != nil {
+		log.Printf("error in closing message replier %s", err)
+	}
+	if err := spn.msg.Close(); err != nil {
+		log.Printf("error in closing message publisher %s", err)
+	}
+}
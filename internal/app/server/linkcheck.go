@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/app/command"
+	"github.com/dictyBase/modware-content/internal/linkcheck"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+// checkLinks checks the external links of all the contents right away and
+// then at every interval, the report replaces the file at the path and the
+// cache is saved to its file after every run. It stops when the context is
+// done.
+func checkLinks(
+	ctx context.Context,
+	repo repository.ContentRepository,
+	chk *linkcheck.Checker,
+	interval time.Duration,
+	path, cache string,
+) {
+	if err := command.LoadLinkCache(chk, cache); err != nil {
+		log.Printf("error in loading link cache %s", err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := writeLinkReport(ctx, repo, chk, path); err != nil {
+			log.Printf("error in checking links %s", err)
+		}
+		if err := command.SaveLinkCache(chk, cache); err != nil {
+			log.Printf("error in saving link cache %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func writeLinkReport(
+	ctx context.Context,
+	repo repository.ContentRepository,
+	chk *linkcheck.Checker,
+	path string,
+) error {
+	report, err := command.CheckNamespaces(ctx, repo, chk, nil)
+	if err != nil {
+		return err
+	}
+	if err := command.WriteLinkReport(path, report); err != nil {
+		return err
+	}
+	log.Printf(
+		"checked %d links, %d broken, report in %s",
+		report.Checked,
+		report.Broken,
+		path,
+	)
+
+	return nil
+}
@@ -1,36 +1,50 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
-	"strconv"
-	"time"
+	"os/signal"
+	"syscall"
 
-	"github.com/dictyBase/aphgrpc"
-	manager "github.com/dictyBase/arangomanager"
 	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/app/command"
 	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/dictyBase/modware-content/internal/asset"
+	"github.com/dictyBase/modware-content/internal/cache"
+	"github.com/dictyBase/modware-content/internal/extension"
+	"github.com/dictyBase/modware-content/internal/gateway"
 	"github.com/dictyBase/modware-content/internal/message"
 	"github.com/dictyBase/modware-content/internal/message/nats"
+	"github.com/dictyBase/modware-content/internal/metrics"
 	"github.com/dictyBase/modware-content/internal/repository"
-	"github.com/dictyBase/modware-content/internal/repository/arangodb"
+	"github.com/dictyBase/modware-content/internal/tracing"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
-	gnats "github.com/nats-io/nats.go"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 const ExitError = 2
-const Timeout = 10
 
 type serverParams struct {
 	repo repository.ContentRepository
+	nsp  repository.NamespaceRepository
+	aud  repository.AuditRepository
+	lck  repository.LockRepository
+	ast  repository.AssetRepository
+	lnk  repository.LinkRepository
 	msg  message.Publisher
+	rpl  message.Replier
+	sub  message.Subscriber
 }
 
 func RunServer(clt *cli.Context) error {
@@ -38,23 +52,78 @@ func RunServer(clt *cli.Context) error {
 	if err != nil {
 		return cli.NewExitError(err.Error(), ExitError)
 	}
+	defer closeAll(spn)
+	shutdown, err := tracing.Setup(context.Background(), &tracing.Params{
+		Exporter:    clt.String("tracing-exporter"),
+		Endpoint:    clt.String("otlp-endpoint"),
+		Insecure:    clt.Bool("otlp-insecure"),
+		File:        clt.String("tracing-file"),
+		SampleRatio: clt.Float64("tracing-sample-ratio"),
+		ServiceName: "modware-content",
+	})
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	defer func() {
+		if err := shutdown(context.Background()); err != nil {
+			log.Printf("error in stopping tracing %s", err)
+		}
+	}()
+	mtr := metrics.New()
+	instrumentRepositories(mtr, spn, clt.String("backend"))
+	spn.msg = mtr.InstrumentPublisher(spn.msg)
+	if err := mtr.Register(metrics.NewNamespaceCollector(spn.repo)); err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	events := make(map[string][]message.EventHandler)
+	if err := cacheContents(clt, spn, mtr, events); err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	assets, err := assetManager(clt, spn)
+	if err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
 	grpcS := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.ChainUnaryInterceptor(
+			mtr.UnaryServerInterceptor(),
 			grpc_ctxtags.UnaryServerInterceptor(),
 			grpc_logrus.UnaryServerInterceptor(getLogger(clt)),
 		),
 	)
 	srv, err := service.NewContentService(
 		&service.Params{
-			Repository: spn.repo,
-			Publisher:  spn.msg,
-			Group:      "groups",
-			Options:    getGrpcOpt(),
+			Repository:         spn.repo,
+			Namespaces:         spn.nsp,
+			Audit:              spn.aud,
+			Locks:              spn.lck,
+			Publisher:          spn.msg,
+			Group:              "groups",
+			Options:            command.GrpcOptions(),
+			CheckpointInterval: clt.Duration("checkpoint-interval"),
+			WatchRetain:        clt.Int("watch-retain"),
+			Assets:             assets,
+			Links:              spn.lnk,
 		})
 	if err != nil {
 		return cli.NewExitError(err.Error(), ExitError)
 	}
 	content.RegisterContentServiceServer(grpcS, srv)
+	extension.Register(grpcS, srv)
+	for subj, hdl := range srv.EventHandlers() {
+		events[subj] = append(events[subj], hdl)
+	}
+	if err := subscribeEvents(spn.sub, events); err != nil {
+		return cli.NewExitError(err.Error(), ExitError)
+	}
+	queue := clt.String("nats-queue")
+	for subj, hdl := range srv.RequestHandlers() {
+		if err := spn.rpl.Reply(subj, queue, hdl); err != nil {
+			return cli.NewExitError(err.Error(), ExitError)
+		}
+	}
+	hsrv := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcS, hsrv)
 	reflection.Register(grpcS)
 	// create listener
 	endP := fmt.Sprintf(":%s", clt.String("port"))
@@ -64,6 +133,32 @@ func RunServer(clt *cli.Context) error {
 			fmt.Sprintf("failed to listen %s", err), ExitError,
 		)
 	}
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		syscall.SIGINT,
+		syscall.SIGTERM,
+	)
+	defer stop()
+	go watchHealth(
+		ctx, hsrv,
+		spn.repo, spn.nsp, spn.aud, spn.lck, spn.ast, spn.lnk,
+	)
+	go serveHTTP(ctx, clt.String("metrics-port"), map[string]http.Handler{
+		"/metrics": mtr.Handler(),
+		"/watch":   gateway.WatchHandler(srv),
+	})
+	if interval := clt.Duration("link-check-interval"); interval > 0 {
+		go checkLinks(
+			ctx, spn.repo, command.LinkChecker(clt),
+			interval, clt.String("link-check-report"), clt.String("link-cache"),
+		)
+	}
+	go func() {
+		<-ctx.Done()
+		log.Print("shutting down grpc server")
+		hsrv.Shutdown()
+		grpcS.GracefulStop()
+	}()
 	log.Printf("starting grpc server on %s", endP)
 	if err := grpcS.Serve(lis); err != nil {
 		return cli.NewExitError(err.Error(), ExitError)
@@ -102,54 +197,120 @@ func getLogger(cltx *cli.Context) *logrus.Entry {
 	return logrus.NewEntry(log)
 }
 
-func allParams(
+func repoAndNatsConn(clt *cli.Context) (*serverParams, error) {
+	spn, err := repositories(clt)
+	if err != nil {
+		return spn, err
+	}
+	msp, err := command.NatsPublisher(clt)
+	if err != nil {
+		return &serverParams{}, err
+	}
+	rpl, err := nats.NewReplier(msp)
+	if err != nil {
+		return &serverParams{}, err
+	}
+	sub, err := nats.NewSubscriber(msp)
+	if err != nil {
+		return &serverParams{}, err
+	}
+	spn.msg, spn.rpl, spn.sub = msp, rpl, sub
+
+	return spn, nil
+}
+
+// instrumentRepositories records the metrics of the operations of every
+// repository.
+func instrumentRepositories(
+	mtr *metrics.Metrics,
+	spn *serverParams,
+	backend string,
+) {
+	spn.repo = mtr.InstrumentRepository(spn.repo, backend)
+	spn.nsp = mtr.InstrumentNamespaceRepository(spn.nsp, backend)
+	spn.aud = mtr.InstrumentAuditRepository(spn.aud, backend)
+	spn.lck = mtr.InstrumentLockRepository(spn.lck, backend)
+	spn.ast = mtr.InstrumentAssetRepository(spn.ast, backend)
+	spn.lnk = mtr.InstrumentLinkRepository(spn.lnk, backend)
+}
+
+// cacheContents puts the read-through cache in front of the content
+// repository, the cached contents are dropped on the update and delete
+// events of any replica. A size of zero leaves the cache out.
+func cacheContents(
 	clt *cli.Context,
-) *manager.ConnectParams {
-	arPort, _ := strconv.Atoi(clt.String("arangodb-port"))
+	spn *serverParams,
+	mtr *metrics.Metrics,
+	events map[string][]message.EventHandler,
+) error {
+	if clt.Int("cache-size") == 0 {
+		return nil
+	}
+	crepo := cache.NewRepository(spn.repo, &cache.Params{
+		Size: clt.Int("cache-size"),
+		TTL:  clt.Duration("cache-ttl"),
+	})
+	topics := command.Topics()
+	for _, topic := range []string{"contentUpdate", "contentDelete"} {
+		events[topics[topic]] = append(events[topics[topic]], crepo.HandleEvent)
+	}
+	spn.repo = crepo
 
-	return &manager.ConnectParams{
-		User:     clt.String("arangodb-user"),
-		Pass:     clt.String("arangodb-pass"),
-		Database: clt.String("arangodb-database"),
-		Host:     clt.String("arangodb-host"),
-		Port:     arPort,
-		Istls:    clt.Bool("is-secure"),
+	return mtr.Register(metrics.NewCacheCollector(crepo.CacheStats))
+}
+
+// subscribeEvents makes a single subscription for every subject, its events
+// are passed to all the handlers of the subject in order.
+func subscribeEvents(
+	sub message.Subscriber,
+	events map[string][]message.EventHandler,
+) error {
+	for subj, hdls := range events {
+		hdls := hdls
+		err := sub.Subscribe(subj, func(ctx context.Context, cont *content.Content) {
+			for _, hdl := range hdls {
+				hdl(ctx, cont)
+			}
+		})
+		if err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
-func getGrpcOpt() []aphgrpc.Option {
-	return []aphgrpc.Option{
-		aphgrpc.TopicsOption(map[string]string{
-			"contentCreate": "ContentService.Create",
-			"contentDelete": "ContentService.Delete",
-			"contentUpdate": "ContentService.Update",
-		}),
+// assetManager keeps the asset files in the blob store of the command line,
+// the assets are turned off without one.
+func assetManager(
+	clt *cli.Context,
+	spn *serverParams,
+) (*asset.Manager, error) {
+	blobs, err := command.BlobStore(clt)
+	if err != nil || blobs == nil {
+		return nil, err
 	}
+
+	return asset.NewManager(&asset.Params{
+		Repo:     spn.ast,
+		Blobs:    blobs,
+		Contents: spn.repo,
+		MaxSize:  clt.Int64("asset-max-size"),
+	}), nil
 }
 
-func repoAndNatsConn(clt *cli.Context) (*serverParams, error) {
-	anrepo, err := arangodb.NewContentRepo(
-		allParams(clt),
-		clt.String("content-collection"),
-	)
-	if err != nil {
-		return &serverParams{},
-			fmt.Errorf(
-				"cannot connect to arangodb annotation repository %s",
-				err,
-			)
-	}
-	msp, err := nats.NewPublisher(
-		clt.String("nats-host"), clt.String("nats-port"),
-		gnats.MaxReconnects(-1), gnats.ReconnectWait(Timeout*time.Second),
-	)
+func repositories(clt *cli.Context) (*serverParams, error) {
+	rps, err := command.OpenRepositories(clt)
 	if err != nil {
-		return &serverParams{},
-			fmt.Errorf("cannot connect to messaging server %s", err)
+		return &serverParams{}, err
 	}
 
 	return &serverParams{
-		repo: anrepo,
-		msg:  msp,
+		repo: rps.Content,
+		nsp:  rps.Namespaces,
+		aud:  rps.Audit,
+		lck:  rps.Locks,
+		ast:  rps.Assets,
+		lnk:  rps.Links,
 	}, nil
 }
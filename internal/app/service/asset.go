@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/asset"
+	"github.com/dictyBase/modware-content/internal/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errNoAssets is returned by the asset methods of a service without an
+// asset manager.
+var errNoAssets = status.Error(codes.Unimplemented, "assets are not enabled")
+
+// UploadAsset stores the file read from the reader as a new asset.
+func (srv *ContentService) UploadAsset(
+	ctx context.Context,
+	name, createdBy string,
+	rdr io.Reader,
+) (*model.AssetDoc, error) {
+	if srv.assets == nil {
+		return &model.AssetDoc{}, errNoAssets
+	}
+	ast, err := srv.assets.Upload(ctx, name, createdBy, rdr)
+	if err != nil {
+		return ast, assetError(ctx, err)
+	}
+
+	return ast, nil
+}
+
+// DownloadAsset opens the file of the asset, the caller should close it.
+func (srv *ContentService) DownloadAsset(
+	ctx context.Context,
+	aid int64,
+) (*model.AssetDoc, io.ReadCloser, error) {
+	if srv.assets == nil {
+		return &model.AssetDoc{}, nil, errNoAssets
+	}
+	ast, rdc, err := srv.assets.Download(ctx, aid)
+	if err != nil {
+		return ast, nil, assetError(ctx, err)
+	}
+
+	return ast, rdc, nil
+}
+
+// DeleteAsset removes the asset, one that is still embedded in a content is
+// only removed when forced. The slugs of the contents that are left with a
+// dangling reference to the removed asset are returned.
+func (srv *ContentService) DeleteAsset(
+	ctx context.Context,
+	aid int64,
+	force bool,
+) ([]string, error) {
+	if srv.assets == nil {
+		return nil, errNoAssets
+	}
+	slugs, err := srv.assets.Delete(ctx, aid, force)
+	if err != nil {
+		return nil, assetError(ctx, err)
+	}
+
+	return slugs, nil
+}
+
+// trackAssets records the assets embedded in the content, an empty body
+// clears them. Like auditing, a failure is only logged.
+func (srv *ContentService) trackAssets(
+	ctx context.Context,
+	cid int64,
+	body string,
+) {
+	if srv.assets == nil {
+		return
+	}
+	if err := srv.assets.SetReferences(ctx, cid, body); err != nil {
+		log.Printf("error in tracking assets of content %d %s", cid, err)
+	}
+}
+
+func assetError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, asset.ErrNotFound):
+		return aphgrpc.HandleNotFoundError(ctx, err)
+	case errors.Is(err, asset.ErrTooLarge):
+		return aphgrpc.HandleInvalidParamError(ctx, err)
+	case errors.Is(err, asset.ErrReferenced):
+		return handleFailedPreconditionError(ctx, err)
+	}
+
+	return aphgrpc.HandleGenericError(ctx, err)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/asset"
+	"github.com/dictyBase/modware-content/internal/blob"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContentAssets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	nrepo := memory.NewNamespaceRepo()
+	_, err := nrepo.AddNamespace(ctx, &model.NamespaceDoc{
+		Name:        "dsc",
+		DisplayName: "Dicty Stock Center",
+		CreatedBy:   "content@content.org",
+	})
+	assert.NoError(err, "expect no error from registering namespace")
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	assert.NoErrorf(err, "expect no error from local store %s", err)
+	repo := memory.NewContentRepo()
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: nrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options:    []aphgrpc.Option{aphgrpc.TopicsOption(map[string]string{})},
+		Assets: asset.NewManager(&asset.Params{
+			Repo:     memory.NewAssetRepo(),
+			Blobs:    blobs,
+			Contents: repo,
+		}),
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	ast, err := srv.UploadAsset(
+		ctx, "notes.txt", "art@vandelay.com", bytes.NewBufferString("notes"),
+	)
+	assert.NoErrorf(err, "expect no error from upload %s", err)
+	aid, _ := strconv.ParseInt(ast.Key, 10, 64)
+	attr := testutils.NewStoreContent("catalog", "dsc")
+	attr.Content = `{"href": "/assets/` + ast.Key + `"}`
+	nct, err := srv.StoreContent(ctx, &content.StoreContentRequest{
+		Data: &content.StoreContentRequest_Data{Attributes: attr},
+	})
+	assert.NoErrorf(err, "expect no error from storing content %s", err)
+	_, err = srv.DeleteAsset(ctx, aid, false)
+	assert.Equal(
+		codes.FailedPrecondition,
+		status.Code(err),
+		"should refuse to delete an embedded asset",
+	)
+	_, err = srv.UpdateContent(
+		ctx,
+		updateRequest(nct.Data.Id, "art@vandelay.com"),
+	)
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	doc, rdc, err := srv.DownloadAsset(ctx, aid)
+	assert.NoErrorf(err, "expect no error from download %s", err)
+	data, err := io.ReadAll(rdc)
+	assert.NoErrorf(err, "expect no error from reading asset %s", err)
+	rdc.Close()
+	assert.Equal("notes", string(data), "should match the uploaded file")
+	assert.Empty(doc.References, "should drop the reference of the update")
+	refs, err := srv.DeleteAsset(ctx, aid, false)
+	assert.NoErrorf(err, "expect no error from deleting asset %s", err)
+	assert.Empty(refs, "expect no dangling reference")
+	_, _, err = srv.DownloadAsset(ctx, aid)
+	assert.Equal(codes.NotFound, status.Code(err), "should not find the asset")
+	ast, err = srv.UploadAsset(
+		ctx, "order.txt", "art@vandelay.com", bytes.NewBufferString("order"),
+	)
+	assert.NoErrorf(err, "expect no error from upload %s", err)
+	aid, _ = strconv.ParseInt(ast.Key, 10, 64)
+	attr = testutils.NewStoreContent("order", "dsc")
+	attr.Content = `{"href": "/assets/` + ast.Key + `"}`
+	_, err = srv.StoreContent(ctx, &content.StoreContentRequest{
+		Data: &content.StoreContentRequest_Data{Attributes: attr},
+	})
+	assert.NoErrorf(err, "expect no error from storing content %s", err)
+	refs, err = srv.DeleteAsset(ctx, aid, true)
+	assert.NoErrorf(err, "expect no error from forced delete %s", err)
+	assert.Equal(
+		refs,
+		[]string{attr.Slug},
+		"should report the content left with a dangling reference",
+	)
+}
+
+func TestAssetsDisabled(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: memory.NewNamespaceRepo(),
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options:    []aphgrpc.Option{aphgrpc.TopicsOption(map[string]string{})},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	_, err = srv.UploadAsset(
+		context.Background(), "notes.txt", "art@vandelay.com",
+		bytes.NewBufferString("notes"),
+	)
+	assert.Equal(codes.Unimplemented, status.Code(err), "should be disabled")
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/model"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ActorKey is the grpc metadata key that names the user behind a request
+// which carries no user of its own, such as a delete.
+const ActorKey = "x-actor"
+
+// recorded for an operation whose actor is not known.
+const unknownActor = "unknown"
+
+// metadata keys of the client that are kept in the audit entries.
+var clientKeys = []string{
+	"user-agent",
+	"x-forwarded-for",
+	"x-request-id",
+	ActorKey,
+}
+
+// audit appends an entry for a mutation of the content, the before or the
+// after is nil for a content that does not exist on that side. The slug and
+// namespace of the before are kept when the content moved. Like publishing,
+// a failure is only logged as the change is already stored.
+func (srv *ContentService) audit(
+	ctx context.Context,
+	operation, actor string,
+	before, after *model.ContentDoc,
+) {
+	cnt := after
+	if cnt == nil {
+		cnt = before
+	}
+	if len(actor) == 0 {
+		actor = contextActor(ctx)
+	}
+	cid, _ := strconv.ParseInt(cnt.Key, 10, 64)
+	ent := &model.AuditEntry{
+		Actor:      actor,
+		Operation:  operation,
+		ContentID:  cid,
+		Slug:       cnt.Slug,
+		Namespace:  cnt.Namespace,
+		BeforeHash: model.ContentHash(before),
+		AfterHash:  model.ContentHash(after),
+		Client:     clientMetadata(ctx),
+	}
+	if before != nil && after != nil &&
+		(before.Slug != after.Slug || before.Namespace != after.Namespace) {
+		ent.FromSlug = before.Slug
+		ent.FromNamespace = before.Namespace
+	}
+	_, err := srv.auditor.AddEntry(ctx, ent)
+	if err != nil {
+		log.Printf(
+			"error in auditing %s of content %d %s",
+			operation,
+			cid,
+			err,
+		)
+	}
+}
+
+// ListAudit returns the audit entries selected by the filter, oldest first.
+func (srv *ContentService) ListAudit(
+	ctx context.Context,
+	flt *model.AuditFilter,
+) ([]*model.AuditEntry, error) {
+	if flt.Limit < 0 {
+		return nil, aphgrpc.HandleInvalidParamError(
+			ctx,
+			fmt.Errorf("limit should not be negative"),
+		)
+	}
+	if !flt.From.IsZero() && !flt.To.IsZero() && flt.To.Before(flt.From) {
+		return nil, aphgrpc.HandleInvalidParamError(
+			ctx,
+			fmt.Errorf("to should not be before from"),
+		)
+	}
+	ents, err := srv.auditor.ListEntries(ctx, flt)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+
+	return ents, nil
+}
+
+// contextActor reads the actor from the metadata of the incoming request.
+func contextActor(ctx context.Context) string {
+	if vals := metadata.ValueFromIncomingContext(ctx, ActorKey); len(vals) > 0 {
+		return vals[0]
+	}
+
+	return unknownActor
+}
+
+// clientMetadata collects the address of the peer along with the selected
+// metadata of the incoming request.
+func clientMetadata(ctx context.Context) map[string]string {
+	client := make(map[string]string)
+	if pee, ok := peer.FromContext(ctx); ok && pee.Addr != nil {
+		client["peer"] = pee.Addr.String()
+	}
+	for _, key := range clientKeys {
+		if vals := metadata.ValueFromIncomingContext(ctx, key); len(vals) > 0 {
+			client[key] = vals[0]
+		}
+	}
+
+	return client
+}
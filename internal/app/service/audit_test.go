@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAuditContent(t *testing.T) {
+	t.Parallel()
+	client, assert, aud := setupWithAudit(t)
+	ctx := metadata.AppendToOutgoingContext(
+		context.Background(),
+		ActorKey, "curator@content.org",
+		"x-request-id", "req-42",
+	)
+	nct, err := client.StoreContent(
+		ctx,
+		&content.StoreContentRequest{
+			Data: &content.StoreContentRequest_Data{
+				Attributes: testutils.NewStoreContent("catalog", "dsc"),
+			},
+		},
+	)
+	assert.NoError(err, "expect no error from storing content")
+	_, err = client.UpdateContent(
+		ctx,
+		&content.UpdateContentRequest{
+			Id: nct.Data.Id,
+			Data: &content.UpdateContentRequest_Data{
+				Attributes: &content.ExistingContentAttributes{
+					UpdatedBy: "packer@packer.com",
+					Content:   `{"paragraph": "clompous"}`,
+				},
+			},
+		},
+	)
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	_, err = client.DeleteContent(
+		ctx,
+		&content.ContentIdRequest{Id: nct.Data.Id},
+	)
+	assert.NoError(err, "expect no error from deleting content")
+	ents, err := aud.ListEntries(context.Background(), &model.AuditFilter{})
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 3, "expect an entry for every mutation")
+	assert.Equal(ents[0].Operation, model.AuditStore, "should audit the store")
+	assert.Equal(ents[0].Actor, "content@content.org", "should match created_by")
+	assert.Empty(ents[0].BeforeHash, "expect no before hash for a new content")
+	assert.Equal(ents[1].Operation, model.AuditUpdate, "should audit the update")
+	assert.Equal(ents[1].Actor, "packer@packer.com", "should match updated_by")
+	assert.Equal(
+		ents[1].BeforeHash,
+		ents[0].AfterHash,
+		"should chain the hashes of the content",
+	)
+	assert.NotEqual(
+		ents[1].BeforeHash,
+		ents[1].AfterHash,
+		"should change the hash of the content",
+	)
+	assert.Equal(ents[2].Operation, model.AuditDelete, "should audit the delete")
+	assert.Equal(ents[2].Actor, "curator@content.org", "should match metadata")
+	assert.Equal(ents[2].BeforeHash, ents[1].AfterHash, "should chain hashes")
+	assert.Empty(ents[2].AfterHash, "expect no after hash for a deleted content")
+	for _, ent := range ents {
+		assert.Equal(ent.ContentID, nct.Data.Id, "content id should match")
+		assert.Equal(ent.Slug, "catalog-dsc", "slug should match")
+		assert.Equal(ent.Namespace, "dsc", "namespace should match")
+		assert.Equal(ent.Client["x-request-id"], "req-42", "should keep metadata")
+		assert.Contains(ent.Client, "peer", "should keep the peer address")
+	}
+}
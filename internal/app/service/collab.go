@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/collab"
+)
+
+// CollaborateContent joins the participant to the collaborative editing
+// session of a content. The merged document is saved periodically and when
+// the last participant leaves, each save goes through the same lock check,
+// audit and update event as UpdateContent.
+func (srv *ContentService) CollaborateContent(
+	ctx context.Context,
+	cid int64,
+	participant string,
+) (*collab.Participant, error) {
+	if len(participant) == 0 {
+		return nil, aphgrpc.HandleInvalidParamError(
+			ctx,
+			fmt.Errorf("participant is required"),
+		)
+	}
+	mcont, err := srv.repo.GetContent(ctx, cid)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+	if mcont.NotFound {
+		return nil, aphgrpc.HandleNotFoundError(
+			ctx,
+			fmt.Errorf("id %d not found", cid),
+		)
+	}
+	ptc, err := srv.collab.Join(ctx, cid, participant)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+
+	return ptc, nil
+}
+
+// checkSession refuses a direct update of a content that has an editing
+// session, the next checkpoint of the session would overwrite it.
+func (srv *ContentService) checkSession(ctx context.Context, cid int64) error {
+	if !srv.collab.Active(cid) {
+		return nil
+	}
+
+	return handleFailedPreconditionError(
+		ctx,
+		fmt.Errorf("content %d is being edited in a session", cid),
+	)
+}
+
+func (srv *ContentService) loadDocument(
+	ctx context.Context,
+	cid int64,
+) (string, error) {
+	mcont, err := srv.repo.GetContent(ctx, cid)
+	if err != nil {
+		return "", err
+	}
+	if mcont.NotFound {
+		return "", fmt.Errorf("id %d not found", cid)
+	}
+
+	return mcont.Content, nil
+}
+
+func (srv *ContentService) checkpointDocument(
+	ctx context.Context,
+	cid int64,
+	doc, editor string,
+) error {
+	_, err := srv.editContent(ctx, cid, &content.ExistingContentAttributes{
+		UpdatedBy: editor,
+		Content:   doc,
+	})
+
+	return err
+}
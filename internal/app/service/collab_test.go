@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/collab"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCollaborateContent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	locks := memory.NewLockRepo()
+	srv, err := NewContentService(&Params{
+		Repository:         repo,
+		Namespaces:         memory.NewNamespaceRepo(),
+		Audit:              memory.NewAuditRepo(),
+		Locks:              locks,
+		Publisher:          &MockMessage{},
+		Group:              "groups",
+		Options:            []aphgrpc.Option{},
+		CheckpointInterval: time.Hour,
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	mcont, err := repo.AddContent(
+		ctx,
+		testutils.NewStoreContent("catalog", "dsc"),
+	)
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	_, err = srv.CollaborateContent(ctx, cid+100, "curator@content.org")
+	assert.Equal(
+		status.Code(err),
+		codes.NotFound,
+		"expect not found for missing content",
+	)
+	curator, err := srv.CollaborateContent(ctx, cid, "curator@content.org")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	editor, err := srv.CollaborateContent(ctx, cid, "editor@content.org")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	assert.Equal(curator.Document, mcont.Content, "should start from stored")
+	size := len([]rune(curator.Document))
+	_, err = curator.Submit(
+		0,
+		(&collab.Operation{}).Retain(size-1).Insert(`, "bold": true`).Retain(1),
+	)
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	upd := <-editor.Updates()
+	doc, err := upd.Operation.Apply(editor.Document)
+	assert.NoErrorf(err, "expect no error from applying update %s", err)
+	assert.NoError(curator.Leave(), "expect no error from leaving")
+	_, err = locks.AcquireLock(ctx, cid, "admin@content.org", time.Minute)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	err = editor.Leave()
+	assert.Error(err, "expect checkpoint to respect the lock")
+	uct, err := repo.GetContent(ctx, cid)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.Equal(uct.Content, mcont.Content, "should not store locked content")
+	err = locks.ReleaseLock(ctx, cid, "admin@content.org")
+	assert.NoErrorf(err, "expect no error from releasing lock %s", err)
+	editor, err = srv.CollaborateContent(ctx, cid, "editor@content.org")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	_, err = srv.UpdateContent(ctx, updateRequest(cid, "admin@content.org"))
+	assert.Equal(
+		status.Code(err),
+		codes.FailedPrecondition,
+		"expect update to be refused during a session",
+	)
+	_, err = editor.Submit(0, (&collab.Operation{}).Delete(size).Insert(doc))
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	assert.NoError(editor.Leave(), "expect no error from leaving")
+	uct, err = repo.GetContent(ctx, cid)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.Equal(uct.Content, doc, "should store the merged document")
+	assert.Equal(uct.UpdatedBy, "editor@content.org", "should match editor")
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/diff"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDiffContent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: memory.NewNamespaceRepo(),
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options:    []aphgrpc.Option{},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	mcont, err := repo.AddContent(
+		ctx,
+		testutils.NewStoreContent("catalog", "dsc"),
+	)
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	res, err := srv.DiffContent(
+		ctx,
+		cid,
+		`{"paragraph": "paragraph", "text": "new text"}`,
+		&diff.Options{Unified: true, Context: 3},
+	)
+	assert.NoErrorf(err, "expect no error from diffing content %s", err)
+	assert.Len(res.Changes, 1, "expect a single change")
+	assert.Equal(res.Changes[0].Path, "/text", "should point to the text")
+	assert.Equal(res.Changes[0].Kind, diff.Changed, "should be changed")
+	assert.Equal(res.Changes[0].Before, "text", "should match stored text")
+	_, err = srv.DiffContent(ctx, cid, `{"paragraph":`, nil)
+	assert.Equal(
+		status.Code(err),
+		codes.InvalidArgument,
+		"expect invalid argument for malformed json",
+	)
+	_, err = srv.DiffContent(ctx, cid+100, `{}`, nil)
+	assert.Equal(
+		status.Code(err),
+		codes.NotFound,
+		"expect not found for missing content",
+	)
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/model"
+)
+
+// DuplicateContents groups the contents of the namespaces, all of them when
+// empty, that have the same content going by their hash.
+func (srv *ContentService) DuplicateContents(
+	ctx context.Context,
+	namespaces []string,
+) ([]*model.Duplicate, error) {
+	cnts, err := srv.repo.ListContents(ctx, namespaces)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+
+	return model.FindDuplicates(cnts), nil
+}
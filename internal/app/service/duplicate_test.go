@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// publishRecorder keeps the subjects of the published contents.
+type publishRecorder struct {
+	MockMessage
+	mutex    sync.Mutex
+	subjects []string
+}
+
+func (prc *publishRecorder) Publish(
+	_ context.Context,
+	subject string,
+	_ *content.Content,
+) error {
+	prc.mutex.Lock()
+	defer prc.mutex.Unlock()
+	prc.subjects = append(prc.subjects, subject)
+
+	return nil
+}
+
+func (prc *publishRecorder) published() []string {
+	prc.mutex.Lock()
+	defer prc.mutex.Unlock()
+
+	return append([]string{}, prc.subjects...)
+}
+
+func TestNoopUpdate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	aud := memory.NewAuditRepo()
+	prc := &publishRecorder{}
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: memory.NewNamespaceRepo(),
+		Audit:      aud,
+		Locks:      memory.NewLockRepo(),
+		Publisher:  prc,
+		Group:      "groups",
+		Options: []aphgrpc.Option{
+			aphgrpc.TopicsOption(map[string]string{
+				"contentUpdate": "ContentService.Update",
+			}),
+		},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	mcont, err := repo.AddContent(
+		ctx,
+		testutils.NewStoreContent("catalog", "dsc"),
+	)
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	// the same json with the keys reordered and reformatted
+	req := updateRequest(cid, "packer@packer.com")
+	req.Data.Attributes.Content = "{\n  \"text\": \"text\",\n" +
+		"  \"paragraph\": \"paragraph\"\n}"
+	ctnt, err := srv.UpdateContent(ctx, req)
+	assert.NoErrorf(err, "expect no error from no-op update %s", err)
+	assert.Equal(
+		ctnt.Data.Attributes.UpdatedBy,
+		mcont.UpdatedBy,
+		"should return the stored content",
+	)
+	assert.Empty(prc.published(), "expect no event for a no-op update")
+	stored, err := repo.GetContent(ctx, cid)
+	assert.NoErrorf(err, "expect no error from fetching content %s", err)
+	assert.True(
+		stored.UpdatedOn.Equal(mcont.UpdatedOn),
+		"should not write a no-op update",
+	)
+	_, err = srv.UpdateContent(ctx, updateRequest(cid, "packer@packer.com"))
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	assert.Equal(
+		prc.published(),
+		[]string{"ContentService.Update"},
+		"expect an event for a real update",
+	)
+	entries, err := aud.ListEntries(ctx, &model.AuditFilter{})
+	assert.NoErrorf(err, "expect no error from listing audit %s", err)
+	assert.Len(entries, 1, "expect only the real update to be audited")
+	stored, err = repo.GetContent(ctx, cid)
+	assert.NoErrorf(err, "expect no error from fetching content %s", err)
+	results, err := srv.BatchContents(ctx, []*model.BatchOperation{
+		{
+			Action: model.BatchUpdate,
+			ID:     cid,
+			Update: updateRequest(cid, "packer@packer.com").Data.Attributes,
+		},
+		{Action: model.BatchUpdate, ID: cid, Update: req.Data.Attributes},
+	}, true)
+	assert.NoErrorf(err, "expect no error from batch update %s", err)
+	assert.Len(results, 2, "expect a result for every operation")
+	assert.Equal(
+		results[0].Content.Data.Attributes.Content,
+		stored.Content,
+		"should return the stored content for a no-op batch update",
+	)
+	assert.Len(prc.published(), 2, "expect a single event for the batch")
+	entries, err = aud.ListEntries(ctx, &model.AuditFilter{})
+	assert.NoErrorf(err, "expect no error from listing audit %s", err)
+	assert.Len(entries, 2, "expect only the changing batch update audited")
+	// the second update restores the body changed by the first
+	_, err = srv.BatchContents(ctx, []*model.BatchOperation{
+		{
+			Action: model.BatchUpdate,
+			ID:     cid,
+			Update: updateRequest(cid, "packer@packer.com").Data.Attributes,
+		},
+		{Action: model.BatchUpdate, ID: cid, Update: req.Data.Attributes},
+	}, true)
+	assert.NoErrorf(err, "expect no error from batch update %s", err)
+	assert.Len(prc.published(), 4, "expect an event for the restoring update")
+	// a failed update leaves no hash for the next update to be skipped on
+	missing := updateRequest(cid+100, "packer@packer.com").Data.Attributes
+	results, err = srv.BatchContents(ctx, []*model.BatchOperation{
+		{Action: model.BatchUpdate, ID: cid + 100, Update: missing},
+		{Action: model.BatchUpdate, ID: cid + 100, Update: missing},
+	}, false)
+	assert.NoErrorf(err, "expect no error from best effort batch %s", err)
+	for _, res := range results {
+		assert.Error(res.Err, "expect every update of a missing content to fail")
+	}
+	assert.Len(prc.published(), 4, "expect no event for failed updates")
+}
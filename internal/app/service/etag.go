@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ETagKey is the response metadata with the entity tag of a content.
+	ETagKey = "etag"
+	// IfNoneMatchKey is the request metadata with the entity tags that the
+	// client already has.
+	IfNoneMatchKey = "if-none-match"
+	// NotModifiedReason is the reason in the error info of the status that
+	// answers a request whose if-none-match has the tag of the content.
+	NotModifiedReason = "NOT_MODIFIED"
+	// ErrorDomain is the domain in the error info of the status errors.
+	ErrorDomain = "modware-content"
+	// the http gateway forwards the standard http headers with this prefix
+	gatewayPrefix = "grpcgateway-"
+)
+
+// conditionalContent sets the entity tag of the stored content in the
+// response metadata. A content whose tag is in the if-none-match metadata
+// of the request is not sent, a FailedPrecondition status with the
+// NotModifiedReason is returned instead.
+func conditionalContent(
+	ctx context.Context,
+	mcont *model.ContentDoc,
+	ctnt *content.Content,
+) (*content.Content, error) {
+	etag := contentETag(mcont)
+	// fails only outside of a grpc request, e.g. for a nats request
+	_ = grpc.SetHeader(ctx, metadata.Pairs(ETagKey, etag))
+	if !etagMatch(ctx, etag) {
+		return ctnt, nil
+	}
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(aphgrpc.MetaKey, "Not modified"))
+	sts, err := status.New(
+		codes.FailedPrecondition,
+		"content is not modified",
+	).WithDetails(&errdetails.ErrorInfo{
+		Reason: NotModifiedReason,
+		Domain: ErrorDomain,
+	})
+	if err != nil {
+		return &content.Content{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return &content.Content{}, sts.Err()
+}
+
+// IsNotModified reports whether the error answers a request whose
+// if-none-match has the tag of the content.
+func IsNotModified(err error) bool {
+	var serr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &serr) {
+		return false
+	}
+	for _, dtl := range serr.GRPCStatus().Details() {
+		info, ok := dtl.(*errdetails.ErrorInfo)
+		if ok && info.Reason == NotModifiedReason && info.Domain == ErrorDomain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contentETag is the quoted hash of the whole representation of the stored
+// content, it changes with a move or a rename as well as with the body and
+// is the same across the backends and replicas.
+func contentETag(mcont *model.ContentDoc) string {
+	sum := sha256.New()
+	for _, field := range []string{
+		mcont.Key,
+		mcont.Name,
+		mcont.Slug,
+		mcont.Namespace,
+		mcont.CreatedBy,
+		mcont.UpdatedBy,
+		mcont.CreatedOn.UTC().Format(time.RFC3339Nano),
+		mcont.UpdatedOn.UTC().Format(time.RFC3339Nano),
+		model.ContentHash(mcont),
+	} {
+		// the separator keeps adjacent fields from running into each other
+		sum.Write([]byte(field))
+		sum.Write([]byte{0})
+	}
+
+	return strconv.Quote(hex.EncodeToString(sum.Sum(nil)))
+}
+
+// etagMatch reports whether the tag is in the if-none-match metadata, the
+// comparison is weak as the http spec has for if-none-match.
+func etagMatch(ctx context.Context, etag string) bool {
+	keys := []string{IfNoneMatchKey, gatewayPrefix + IfNoneMatchKey}
+	for _, key := range keys {
+		for _, val := range metadata.ValueFromIncomingContext(ctx, key) {
+			for _, tag := range strings.Split(val, ",") {
+				tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+				if tag == "*" || tag == etag {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestConditionalGet(t *testing.T) {
+	t.Parallel()
+	client, assert := setup(t)
+	ctx := context.Background()
+	nct, err := client.StoreContent(ctx, &content.StoreContentRequest{
+		Data: &content.StoreContentRequest_Data{
+			Attributes: testutils.NewStoreContent("catalog", "dsc"),
+		},
+	})
+	assert.NoErrorf(err, "expect no error from storing content %s", err)
+	var header metadata.MD
+	sct, err := client.GetContent(
+		ctx,
+		&content.ContentIdRequest{Id: nct.Data.Id},
+		grpc.Header(&header),
+	)
+	assert.NoErrorf(err, "expect no error from fetching content %s", err)
+	testContentProperties(assert, sct, nct)
+	etags := header.Get(ETagKey)
+	assert.Len(etags, 1, "expect an etag")
+	var slugHeader metadata.MD
+	_, err = client.GetContentBySlug(
+		ctx,
+		&content.ContentRequest{Slug: nct.Data.Attributes.Slug},
+		grpc.Header(&slugHeader),
+	)
+	assert.NoErrorf(err, "expect no error from fetching content %s", err)
+	assert.Equal(slugHeader.Get(ETagKey), etags, "expect the same etag")
+	for _, val := range []string{
+		etags[0],
+		`"stale", ` + etags[0],
+		"W/" + etags[0],
+		"*",
+	} {
+		var cheader metadata.MD
+		cctx := metadata.AppendToOutgoingContext(ctx, IfNoneMatchKey, val)
+		_, err := client.GetContent(
+			cctx,
+			&content.ContentIdRequest{Id: nct.Data.Id},
+			grpc.Header(&cheader),
+		)
+		assert.Equal(
+			status.Code(err),
+			codes.FailedPrecondition,
+			"expect failed precondition for a matching etag",
+		)
+		assert.True(IsNotModified(err), "expect not modified reason")
+		assert.Equal(cheader.Get(ETagKey), etags, "expect the etag")
+	}
+	cctx := metadata.AppendToOutgoingContext(
+		ctx,
+		gatewayPrefix+IfNoneMatchKey,
+		etags[0],
+	)
+	_, err = client.GetContentBySlug(
+		cctx,
+		&content.ContentRequest{Slug: nct.Data.Attributes.Slug},
+	)
+	assert.True(IsNotModified(err), "expect gateway header to be honored")
+	_, err = client.UpdateContent(
+		ctx,
+		updateRequest(nct.Data.Id, "packer@packer.com"),
+	)
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	var uheader metadata.MD
+	cctx = metadata.AppendToOutgoingContext(ctx, IfNoneMatchKey, etags[0])
+	sct, err = client.GetContent(
+		cctx,
+		&content.ContentIdRequest{Id: nct.Data.Id},
+		grpc.Header(&uheader),
+	)
+	assert.NoErrorf(err, "expect no error from conditional get %s", err)
+	assert.NotNil(sct.Data, "expect content after an update")
+	assert.NotEqual(uheader.Get(ETagKey), etags, "expect a new etag")
+	// an update to the same body is not stored and keeps the tag
+	_, err = client.UpdateContent(
+		ctx,
+		updateRequest(nct.Data.Id, "curator@content.org"),
+	)
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	cctx = metadata.AppendToOutgoingContext(
+		ctx,
+		IfNoneMatchKey,
+		uheader.Get(ETagKey)[0],
+	)
+	_, err = client.GetContent(cctx, &content.ContentIdRequest{Id: nct.Data.Id})
+	assert.True(IsNotModified(err), "expect the tag to follow the body")
+}
+
+func TestMoveETag(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	nrepo := memory.NewNamespaceRepo()
+	_, err := nrepo.AddNamespace(ctx, &model.NamespaceDoc{
+		Name:        "dictybase",
+		DisplayName: "dictyBase",
+		CreatedBy:   "content@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from adding namespace %s", err)
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: nrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options:    []aphgrpc.Option{aphgrpc.TopicsOption(nil)},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	mcont, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	etag := contentETag(mcont)
+	cctx := metadata.NewIncomingContext(ctx, metadata.Pairs(IfNoneMatchKey, etag))
+	_, err = srv.GetContent(cctx, &content.ContentIdRequest{Id: cid})
+	assert.True(IsNotModified(err), "expect the tag of the stored content")
+	_, err = srv.TransferContents(ctx, &model.ContentTransfer{
+		From:      "dsc",
+		To:        "dictybase",
+		Slugs:     []string{mcont.Slug},
+		Move:      true,
+		UpdatedBy: "content@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from moving content %s", err)
+	mvd, err := repo.GetContent(ctx, cid)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.Equal(
+		model.ContentHash(mvd),
+		model.ContentHash(mcont),
+		"expect a move to keep the body",
+	)
+	assert.NotEqual(contentETag(mvd), etag, "expect a move to change the tag")
+	sct, err := srv.GetContent(cctx, &content.ContentIdRequest{Id: cid})
+	assert.NoErrorf(err, "expect no error from conditional get %s", err)
+	assert.Equal(
+		sct.Data.Attributes.Namespace,
+		"dictybase",
+		"expect the moved content for the stale tag",
+	)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errNoLinks is returned by the link methods of a service without a link
+// repository.
+var errNoLinks = status.Error(codes.Unimplemented, "links are not enabled")
+
+// OutgoingLinks returns the links from the content of the slug.
+func (srv *ContentService) OutgoingLinks(
+	ctx context.Context,
+	slug string,
+) ([]*model.LinkDoc, error) {
+	if srv.links == nil {
+		return nil, errNoLinks
+	}
+	mcont, err := srv.repo.GetContentBySlug(ctx, slug)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+	if mcont.NotFound {
+		return nil, aphgrpc.HandleNotFoundError(
+			ctx,
+			fmt.Errorf("slug %s not found", slug),
+		)
+	}
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	lnks, err := srv.links.OutgoingLinks(ctx, cid)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+
+	return lnks, nil
+}
+
+// Backlinks returns the links of the other contents to the slug, the
+// content of the slug need not exist.
+func (srv *ContentService) Backlinks(
+	ctx context.Context,
+	slug string,
+) ([]*model.LinkDoc, error) {
+	if srv.links == nil {
+		return nil, errNoLinks
+	}
+	lnks, err := srv.links.Backlinks(ctx, slug)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+
+	return lnks, nil
+}
+
+// BrokenLinks returns the links of the contents in the namespace, all of
+// them when empty, whose target content does not exist.
+func (srv *ContentService) BrokenLinks(
+	ctx context.Context,
+	namespace string,
+) ([]*model.LinkDoc, error) {
+	if srv.links == nil {
+		return nil, errNoLinks
+	}
+	broken, err := links.FindBroken(ctx, srv.repo, srv.links, namespace)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+
+	return broken, nil
+}
+
+// track records the assets and the links of the stored content.
+func (srv *ContentService) track(
+	ctx context.Context,
+	cid int64,
+	mcont *model.ContentDoc,
+) {
+	srv.trackAssets(ctx, cid, mcont.Content)
+	srv.trackLinks(ctx, cid, mcont, links.LinkRefs(mcont.Content))
+}
+
+// untrack clears the assets and the links of the deleted content.
+func (srv *ContentService) untrack(
+	ctx context.Context,
+	cid int64,
+	mcont *model.ContentDoc,
+) {
+	srv.trackAssets(ctx, cid, "")
+	srv.trackLinks(ctx, cid, mcont, nil)
+}
+
+// trackLinks replaces the links of the content with the targets, like
+// auditing a failure is only logged.
+func (srv *ContentService) trackLinks(
+	ctx context.Context,
+	cid int64,
+	mcont *model.ContentDoc,
+	targets []string,
+) {
+	if srv.links == nil {
+		return
+	}
+	err := srv.links.SetLinks(ctx, &model.LinkDoc{
+		ContentID: cid,
+		Slug:      mcont.Slug,
+		Namespace: mcont.Namespace,
+	}, targets)
+	if err != nil {
+		log.Printf("error in tracking links of content %d %s", cid, err)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func linkTargets(lnks []*model.LinkDoc) []string {
+	targets := make([]string, 0, len(lnks))
+	for _, lnk := range lnks {
+		targets = append(targets, lnk.Target)
+	}
+
+	return targets
+}
+
+func storeLinking(
+	ctx context.Context,
+	assert *require.Assertions,
+	srv *ContentService,
+	name string,
+	targets ...string,
+) *content.Content {
+	attr := testutils.NewStoreContent(name, "dsc")
+	attr.Content = `{"children": [`
+	for idx, target := range targets {
+		if idx > 0 {
+			attr.Content += ","
+		}
+		attr.Content += `{"type": "link", "url": "/dsc/` + target + `"}`
+	}
+	attr.Content += `]}`
+	nct, err := srv.StoreContent(ctx, &content.StoreContentRequest{
+		Data: &content.StoreContentRequest_Data{Attributes: attr},
+	})
+	assert.NoErrorf(err, "expect no error from storing content %s", err)
+
+	return nct
+}
+
+func TestContentLinks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	nrepo := memory.NewNamespaceRepo()
+	_, err := nrepo.AddNamespace(ctx, &model.NamespaceDoc{
+		Name:        "dsc",
+		DisplayName: "Dicty Stock Center",
+		CreatedBy:   "content@content.org",
+	})
+	assert.NoError(err, "expect no error from registering namespace")
+	srv, err := NewContentService(&Params{
+		Repository: memory.NewContentRepo(),
+		Namespaces: nrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options:    []aphgrpc.Option{aphgrpc.TopicsOption(map[string]string{})},
+		Links:      memory.NewLinkRepo(),
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	home := storeLinking(ctx, assert, srv, "home", "about-dsc", "faq-dsc")
+	about := storeLinking(ctx, assert, srv, "about", "home-dsc")
+	lnks, err := srv.OutgoingLinks(ctx, home.Data.Attributes.Slug)
+	assert.NoErrorf(err, "expect no error from outgoing links %s", err)
+	assert.Equal(
+		[]string{"about-dsc", "faq-dsc"},
+		linkTargets(lnks),
+		"should match the links of the content",
+	)
+	lnks, err = srv.Backlinks(ctx, "home-dsc")
+	assert.NoErrorf(err, "expect no error from backlinks %s", err)
+	assert.Len(lnks, 1, "expect a single backlink")
+	assert.Equal(about.Data.Id, lnks[0].ContentID, "should link from about")
+	lnks, err = srv.BrokenLinks(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from broken links %s", err)
+	assert.Equal([]string{"faq-dsc"}, linkTargets(lnks), "faq is missing")
+	_, err = srv.DeleteContent(
+		ctx,
+		&content.ContentIdRequest{Id: about.Data.Id},
+	)
+	assert.NoErrorf(err, "expect no error from deleting content %s", err)
+	lnks, err = srv.Backlinks(ctx, "home-dsc")
+	assert.NoErrorf(err, "expect no error from backlinks %s", err)
+	assert.Empty(lnks, "expect the links of the deleted content to go")
+	lnks, err = srv.BrokenLinks(ctx, "")
+	assert.NoErrorf(err, "expect no error from broken links %s", err)
+	assert.Equal(
+		[]string{"about-dsc", "faq-dsc"},
+		linkTargets(lnks),
+		"should report the link to the deleted content",
+	)
+	_, err = srv.OutgoingLinks(ctx, "about-dsc")
+	assert.Equal(codes.NotFound, status.Code(err), "expect missing content")
+}
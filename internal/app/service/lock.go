@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MaxLockTTL is the longest lease that could be taken at a time, a longer
+// edit has to renew the lease.
+const MaxLockTTL = time.Hour
+
+// AcquireLock takes the edit lease of a content for the holder, acquiring a
+// lease that is already held by the same holder extends it.
+func (srv *ContentService) AcquireLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	if err := validateLease(holder, ttl); err != nil {
+		return nil, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	mcont, err := srv.repo.GetContent(ctx, cid)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+	if mcont.NotFound {
+		return nil, aphgrpc.HandleNotFoundError(
+			ctx,
+			fmt.Errorf("id %d not found", cid),
+		)
+	}
+	lck, err := srv.locks.AcquireLock(ctx, cid, holder, ttl)
+	if err != nil {
+		return nil, srv.handleLockError(ctx, cid, err)
+	}
+	srv.publishLock(ctx, model.LockAcquire, holder, lck)
+
+	return lck, nil
+}
+
+// RenewLock extends the live lease of the holder.
+func (srv *ContentService) RenewLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	if err := validateLease(holder, ttl); err != nil {
+		return nil, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	lck, err := srv.locks.RenewLock(ctx, cid, holder, ttl)
+	if err != nil {
+		return nil, srv.handleLockError(ctx, cid, err)
+	}
+	srv.publishLock(ctx, model.LockRenew, holder, lck)
+
+	return lck, nil
+}
+
+// ReleaseLock gives up the lease of the holder.
+func (srv *ContentService) ReleaseLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+) error {
+	if len(holder) == 0 {
+		return aphgrpc.HandleInvalidParamError(
+			ctx,
+			fmt.Errorf("holder of the lock is required"),
+		)
+	}
+	if err := srv.locks.ReleaseLock(ctx, cid, holder); err != nil {
+		return srv.handleLockError(ctx, cid, err)
+	}
+	srv.publishLock(ctx, model.LockRelease, holder, &model.LockDoc{
+		ContentID: cid,
+		Holder:    holder,
+	})
+
+	return nil
+}
+
+// BreakLock removes the lease of a content irrespective of its holder, the
+// actor is read from the request metadata when it is empty.
+func (srv *ContentService) BreakLock(
+	ctx context.Context,
+	cid int64,
+	actor string,
+) (*model.LockDoc, error) {
+	lck, err := srv.locks.BreakLock(ctx, cid)
+	if err != nil {
+		return nil, aphgrpc.HandleUpdateError(ctx, err)
+	}
+	if lck.NotFound {
+		return nil, aphgrpc.HandleNotFoundError(
+			ctx,
+			fmt.Errorf("no lock for id %d", cid),
+		)
+	}
+	if len(actor) == 0 {
+		actor = contextActor(ctx)
+	}
+	srv.publishLock(ctx, model.LockBreak, actor, lck)
+
+	return lck, nil
+}
+
+// checkLock refuses an update of a content whose live lease is held by
+// someone other than the editor, a content without a lease is open to all.
+// The lock is advisory, the editor is the updater given in the request and
+// a lease taken between the check and the write is not noticed.
+func (srv *ContentService) checkLock(
+	ctx context.Context,
+	cid int64,
+	editor string,
+) error {
+	lck, err := srv.locks.GetLock(ctx, cid)
+	if err != nil {
+		return aphgrpc.HandleGetError(ctx, err)
+	}
+	if lck.NotFound || lck.Holder == editor {
+		return nil
+	}
+
+	return handleFailedPreconditionError(ctx, fmt.Errorf(
+		"content %d is locked by %s until %s",
+		cid,
+		lck.Holder,
+		lck.ExpiresOn.Format(time.RFC3339),
+	))
+}
+
+func (srv *ContentService) handleLockError(
+	ctx context.Context,
+	cid int64,
+	err error,
+) error {
+	if errors.Is(err, repository.ErrLockHeld) {
+		lck, gerr := srv.locks.GetLock(ctx, cid)
+		if gerr == nil && !lck.NotFound {
+			err = fmt.Errorf("content %d is locked by %s", cid, lck.Holder)
+		}
+
+		return handleFailedPreconditionError(ctx, err)
+	}
+	if errors.Is(err, repository.ErrLockNotHeld) {
+		return handleFailedPreconditionError(ctx, err)
+	}
+
+	return aphgrpc.HandleUpdateError(ctx, err)
+}
+
+// publishLock broadcasts the change of a lease through the content
+// publisher, like the content events a failure is only logged.
+func (srv *ContentService) publishLock(
+	ctx context.Context,
+	action, actor string,
+	lck *model.LockDoc,
+) {
+	evt := &model.LockEvent{
+		Action:    action,
+		ContentID: lck.ContentID,
+		Holder:    lck.Holder,
+		Actor:     actor,
+		ExpiresOn: lck.ExpiresOn,
+	}
+	cont, err := evt.Content()
+	if err == nil {
+		err = srv.publisher.Publish(ctx, srv.Topics["contentLock"], cont)
+	}
+	if err != nil {
+		log.Printf(
+			"error in publishing %s lock of content %d %s",
+			action,
+			lck.ContentID,
+			err,
+		)
+	}
+}
+
+func validateLease(holder string, ttl time.Duration) error {
+	if len(holder) == 0 {
+		return fmt.Errorf("holder of the lock is required")
+	}
+	if ttl <= 0 || ttl > MaxLockTTL {
+		return fmt.Errorf("ttl of the lock should be within %s", MaxLockTTL)
+	}
+
+	return nil
+}
+
+// handleFailedPreconditionError is the missing counterpart of the aphgrpc
+// error handlers for a request that conflicts with a lock.
+func handleFailedPreconditionError(ctx context.Context, err error) error {
+	_ = grpc.SetTrailer(
+		ctx,
+		metadata.Pairs(aphgrpc.MetaKey, "Failed precondition"),
+	)
+
+	return status.Error(codes.FailedPrecondition, err.Error())
+}
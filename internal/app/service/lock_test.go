@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const lockSubject = "ContentService.Lock"
+
+// lockRecorder keeps the lock events published to the lock subject.
+type lockRecorder struct {
+	MockMessage
+	mutex  sync.Mutex
+	events []*model.LockEvent
+}
+
+func (lrc *lockRecorder) Publish(
+	ctx context.Context,
+	subject string,
+	cont *content.Content,
+) error {
+	if subject != lockSubject {
+		return nil
+	}
+	evt, err := model.LockEventFromContent(cont)
+	if err != nil {
+		return err
+	}
+	lrc.mutex.Lock()
+	defer lrc.mutex.Unlock()
+	lrc.events = append(lrc.events, evt)
+
+	return nil
+}
+
+func (lrc *lockRecorder) actions() []string {
+	lrc.mutex.Lock()
+	defer lrc.mutex.Unlock()
+	actions := make([]string, 0, len(lrc.events))
+	for _, evt := range lrc.events {
+		actions = append(actions, evt.Action)
+	}
+
+	return actions
+}
+
+func updateRequest(cid int64, editor string) *content.UpdateContentRequest {
+	return &content.UpdateContentRequest{
+		Id: cid,
+		Data: &content.UpdateContentRequest_Data{
+			Attributes: &content.ExistingContentAttributes{
+				UpdatedBy: editor,
+				Content:   `{"paragraph": "paragraph", "text": "locked"}`,
+			},
+		},
+	}
+}
+
+func TestContentLock(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	pub := &lockRecorder{}
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: memory.NewNamespaceRepo(),
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  pub,
+		Group:      "groups",
+		Options: []aphgrpc.Option{
+			aphgrpc.TopicsOption(map[string]string{"contentLock": lockSubject}),
+		},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	mcont, err := repo.AddContent(
+		ctx,
+		testutils.NewStoreContent("catalog", "dsc"),
+	)
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	_, err = srv.AcquireLock(ctx, cid, "curator@content.org", 2*MaxLockTTL)
+	assert.Equal(
+		status.Code(err),
+		codes.InvalidArgument,
+		"expect invalid argument for ttl beyond the limit",
+	)
+	_, err = srv.AcquireLock(ctx, cid+100, "curator@content.org", time.Minute)
+	assert.Equal(
+		status.Code(err),
+		codes.NotFound,
+		"expect not found for missing content",
+	)
+	lck, err := srv.AcquireLock(ctx, cid, "curator@content.org", time.Minute)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	assert.Equal(lck.Holder, "curator@content.org", "holder should match")
+	_, err = srv.AcquireLock(ctx, cid, "editor@content.org", time.Minute)
+	assert.Equal(
+		status.Code(err),
+		codes.FailedPrecondition,
+		"expect failed precondition for a held lock",
+	)
+	_, err = srv.UpdateContent(ctx, updateRequest(cid, "editor@content.org"))
+	assert.Equal(
+		status.Code(err),
+		codes.FailedPrecondition,
+		"expect failed precondition for update by another user",
+	)
+	_, err = srv.BatchContents(ctx, []*model.BatchOperation{{
+		Action: model.BatchUpdate,
+		ID:     cid,
+		Update: updateRequest(cid, "editor@content.org").Data.Attributes,
+	}}, true)
+	assert.Equal(
+		status.Code(err),
+		codes.FailedPrecondition,
+		"expect failed precondition for batch update by another user",
+	)
+	uct, err := srv.UpdateContent(ctx, updateRequest(cid, "curator@content.org"))
+	assert.NoErrorf(err, "expect no error from update by holder %s", err)
+	assert.Equal(
+		uct.Data.Attributes.UpdatedBy,
+		"curator@content.org",
+		"should match updated by",
+	)
+	_, err = srv.RenewLock(ctx, cid, "curator@content.org", time.Minute)
+	assert.NoErrorf(err, "expect no error from renewing lock %s", err)
+	err = srv.ReleaseLock(ctx, cid, "editor@content.org")
+	assert.Equal(
+		status.Code(err),
+		codes.FailedPrecondition,
+		"expect failed precondition for release by another user",
+	)
+	err = srv.ReleaseLock(ctx, cid, "curator@content.org")
+	assert.NoErrorf(err, "expect no error from releasing lock %s", err)
+	_, err = srv.UpdateContent(ctx, updateRequest(cid, "editor@content.org"))
+	assert.NoErrorf(err, "expect no error from update of unlocked content %s", err)
+	_, err = srv.AcquireLock(ctx, cid, "editor@content.org", time.Minute)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	blck, err := srv.BreakLock(ctx, cid, "admin@content.org")
+	assert.NoErrorf(err, "expect no error from breaking lock %s", err)
+	assert.Equal(blck.Holder, "editor@content.org", "should return broken lock")
+	_, err = srv.BreakLock(ctx, cid, "admin@content.org")
+	assert.Equal(
+		status.Code(err),
+		codes.NotFound,
+		"expect not found for breaking missing lock",
+	)
+	assert.Equal(
+		pub.actions(),
+		[]string{
+			model.LockAcquire,
+			model.LockRenew,
+			model.LockRelease,
+			model.LockAcquire,
+			model.LockBreak,
+		},
+		"should publish every change of the lock",
+	)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// CreateNamespace registers a new namespace along with its settings, the
+// content schema, when given, has to be a valid json schema.
+func (srv *ContentService) CreateNamespace(
+	ctx context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	if err := validator.New().Struct(nsp); err != nil {
+		return nil, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	if len(nsp.ContentSchema) > 0 {
+		if _, err := compileSchema(nsp.Name, nsp.ContentSchema); err != nil {
+			return nil, aphgrpc.HandleInvalidParamError(ctx, err)
+		}
+	}
+	exist, err := srv.namespaces.GetNamespace(ctx, nsp.Name)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+	if !exist.NotFound {
+		return nil, aphgrpc.HandleExistError(
+			ctx,
+			fmt.Errorf("namespace %s already exists", nsp.Name),
+		)
+	}
+	nnsp, err := srv.namespaces.AddNamespace(ctx, nsp)
+	if err != nil {
+		return nil, aphgrpc.HandleInsertError(ctx, err)
+	}
+
+	return nnsp, nil
+}
+
+// GetNamespace returns the settings of a registered namespace.
+func (srv *ContentService) GetNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nsp, err := srv.namespaces.GetNamespace(ctx, name)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+	if nsp.NotFound {
+		return nil, aphgrpc.HandleNotFoundError(
+			ctx,
+			fmt.Errorf("namespace %s not found", name),
+		)
+	}
+
+	return nsp, nil
+}
+
+// ListNamespaces returns all the registered namespaces.
+func (srv *ContentService) ListNamespaces(
+	ctx context.Context,
+) ([]*model.NamespaceDoc, error) {
+	nsps, err := srv.namespaces.ListNamespaces(ctx)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+
+	return nsps, nil
+}
+
+// ArchiveNamespace marks a namespace as archived, no new content could be
+// stored in it afterwards.
+func (srv *ContentService) ArchiveNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nsp, err := srv.namespaces.ArchiveNamespace(ctx, name)
+	if err != nil {
+		return nil, aphgrpc.HandleUpdateError(ctx, err)
+	}
+	if nsp.NotFound {
+		return nil, aphgrpc.HandleNotFoundError(
+			ctx,
+			fmt.Errorf("namespace %s not found", name),
+		)
+	}
+
+	return nsp, nil
+}
+
+// SeedNamespaces registers every namespace of the stored contents that is
+// not registered yet, with its name as the display name. It backfills the
+// registry of a deployment that stored contents before namespaces had to be
+// registered, the newly registered namespaces are returned.
+func (srv *ContentService) SeedNamespaces(
+	ctx context.Context,
+	createdBy string,
+) ([]*model.NamespaceDoc, error) {
+	if err := validator.New().Var(createdBy, "required,email"); err != nil {
+		return nil, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	counts, err := srv.repo.CountByNamespace(ctx)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seeded := make([]*model.NamespaceDoc, 0)
+	for _, name := range names {
+		exist, err := srv.namespaces.GetNamespace(ctx, name)
+		if err != nil {
+			return seeded, aphgrpc.HandleGetError(ctx, err)
+		}
+		if !exist.NotFound {
+			continue
+		}
+		nsp, err := srv.namespaces.AddNamespace(ctx, &model.NamespaceDoc{
+			Name:        name,
+			DisplayName: name,
+			CreatedBy:   createdBy,
+		})
+		if err != nil {
+			return seeded, aphgrpc.HandleInsertError(ctx, err)
+		}
+		seeded = append(seeded, nsp)
+	}
+
+	return seeded, nil
+}
+
+// checkContentSchema validates the body of a content against the content
+// schema of its namespace, a namespace without a schema takes any body.
+func checkContentSchema(nsp *model.NamespaceDoc, body string) error {
+	if nsp.NotFound || len(nsp.ContentSchema) == 0 {
+		return nil
+	}
+	sch, err := compileSchema(nsp.Name, nsp.ContentSchema)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return fmt.Errorf(
+			"content of namespace %s is not json %s",
+			nsp.Name,
+			err,
+		)
+	}
+	if err := sch.Validate(doc); err != nil {
+		return fmt.Errorf(
+			"content does not match the schema of namespace %s %s",
+			nsp.Name,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func compileSchema(name, schema string) (*jsonschema.Schema, error) {
+	cmp := jsonschema.NewCompiler()
+	url := fmt.Sprintf("namespace/%s.json", name)
+	if err := cmp.AddResource(url, strings.NewReader(schema)); err != nil {
+		return nil, fmt.Errorf("error in reading content schema %s", err)
+	}
+	sch, err := cmp.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("error in compiling content schema %s", err)
+	}
+
+	return sch, nil
+}
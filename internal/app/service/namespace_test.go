@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContentSchema(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: memory.NewNamespaceRepo(),
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options:    []aphgrpc.Option{aphgrpc.TopicsOption(nil)},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	// a content stored before the namespace is registered
+	mcont, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	_, err = srv.CreateNamespace(ctx, &model.NamespaceDoc{
+		Name:          "dsc",
+		DisplayName:   "Dicty Stock Center",
+		ContentSchema: `{"type": "object", "required": ["text"]}`,
+		CreatedBy:     "content@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	seeded, err := srv.SeedNamespaces(ctx, "content@content.org")
+	assert.NoErrorf(err, "expect no error from seeding namespaces %s", err)
+	assert.Empty(seeded, "expect a registered namespace to be left as is")
+	for _, body := range []string{`{"paragraph": "no text"}`, "plain text"} {
+		_, err = srv.UpdateContent(ctx, updateBody(cid, body))
+		assert.Equal(
+			codes.InvalidArgument,
+			status.Code(err),
+			"expect an update that misses the schema to be refused",
+		)
+	}
+	_, err = srv.UpdateContent(ctx, updateBody(cid, `{"text": "jack"}`))
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	res, err := srv.BatchContents(ctx, []*model.BatchOperation{
+		{
+			Action: model.BatchUpdate,
+			ID:     cid,
+			Update: &content.ExistingContentAttributes{
+				UpdatedBy: "content@content.org",
+				Content:   `{"paragraph": "no text"}`,
+			},
+		},
+	}, true)
+	assert.Equal(
+		codes.InvalidArgument,
+		status.Code(err),
+		"expect a batch that misses the schema to be refused",
+	)
+	assert.Empty(res, "expect no results for a refused batch")
+}
+
+func updateBody(cid int64, body string) *content.UpdateContentRequest {
+	return &content.UpdateContentRequest{
+		Id: cid,
+		Data: &content.UpdateContentRequest_Data{
+			Attributes: &content.ExistingContentAttributes{
+				UpdatedBy: "packer@packer.com",
+				Content:   body,
+			},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/message"
+	"google.golang.org/protobuf/proto"
+)
+
+// RequestHandlers answers the messaging requests with the same handlers as
+// the grpc methods, keyed by their subject. The requests are the protocol
+// buffer encoded ContentIdRequest and ContentRequest and the reply is the
+// encoded content.
+func (srv *ContentService) RequestHandlers() map[string]message.RequestHandler {
+	return map[string]message.RequestHandler{
+		srv.Topics["contentGet"]: func(
+			ctx context.Context,
+			data []byte,
+		) ([]byte, error) {
+			req := &content.ContentIdRequest{}
+			if err := proto.Unmarshal(data, req); err != nil {
+				return nil, requestError(ctx, err)
+			}
+
+			return replyContent(srv.GetContent(ctx, req))
+		},
+		srv.Topics["contentGetBySlug"]: func(
+			ctx context.Context,
+			data []byte,
+		) ([]byte, error) {
+			req := &content.ContentRequest{}
+			if err := proto.Unmarshal(data, req); err != nil {
+				return nil, requestError(ctx, err)
+			}
+
+			return replyContent(srv.GetContentBySlug(ctx, req))
+		},
+	}
+}
+
+func requestError(ctx context.Context, err error) error {
+	return aphgrpc.HandleInvalidParamError(
+		ctx,
+		fmt.Errorf("error in decoding request %s", err),
+	)
+}
+
+func replyContent(ctnt *content.Content, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(ctnt)
+	if err != nil {
+		return nil, fmt.Errorf("error in encoding content %s", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRequestHandlers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: memory.NewNamespaceRepo(),
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options: []aphgrpc.Option{
+			aphgrpc.TopicsOption(map[string]string{
+				"contentGet":       "ContentService.Get",
+				"contentGetBySlug": "ContentService.GetBySlug",
+			}),
+		},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	mcont, err := repo.AddContent(
+		ctx,
+		testutils.NewStoreContent("catalog", "dsc"),
+	)
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	handlers := srv.RequestHandlers()
+	for subj, req := range map[string]proto.Message{
+		"ContentService.Get":       &content.ContentIdRequest{Id: cid},
+		"ContentService.GetBySlug": &content.ContentRequest{Slug: mcont.Slug},
+	} {
+		data, err := proto.Marshal(req)
+		assert.NoErrorf(err, "expect no error from encoding request %s", err)
+		reply, err := handlers[subj](ctx, data)
+		assert.NoErrorf(err, "expect no error from %s %s", subj, err)
+		ctnt := &content.Content{}
+		err = proto.Unmarshal(reply, ctnt)
+		assert.NoErrorf(err, "expect no error from decoding reply %s", err)
+		assert.Equal(ctnt.Data.Id, cid, "should match the content id")
+		assert.Equal(ctnt.Data.Attributes.Slug, mcont.Slug, "should match slug")
+	}
+	data, err := proto.Marshal(&content.ContentIdRequest{Id: cid + 100})
+	assert.NoErrorf(err, "expect no error from encoding request %s", err)
+	_, err = handlers["ContentService.Get"](ctx, data)
+	assert.Equal(
+		status.Code(err),
+		codes.NotFound,
+		"expect not found for missing content",
+	)
+	_, err = handlers["ContentService.GetBySlug"](ctx, []byte("garbage"))
+	assert.Equal(
+		status.Code(err),
+		codes.InvalidArgument,
+		"expect invalid argument for undecodable request",
+	)
+}
@@ -3,14 +3,20 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
+	"time"
 
 	"github.com/dictyBase/aphgrpc"
 	"github.com/dictyBase/go-genproto/dictybaseapis/api/jsonapi"
 	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/asset"
+	"github.com/dictyBase/modware-content/internal/collab"
+	"github.com/dictyBase/modware-content/internal/diff"
 	"github.com/dictyBase/modware-content/internal/message"
 	"github.com/dictyBase/modware-content/internal/model"
 	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/watch"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang/protobuf/ptypes/empty"
 )
@@ -23,18 +29,40 @@ func defaultOptions() *aphgrpc.ServiceOptions {
 
 type ContentService struct {
 	*aphgrpc.Service
-	repo      repository.ContentRepository
-	publisher message.Publisher
-	group     string
+	repo       repository.ContentRepository
+	namespaces repository.NamespaceRepository
+	auditor    repository.AuditRepository
+	locks      repository.LockRepository
+	links      repository.LinkRepository
+	assets     *asset.Manager
+	publisher  message.Publisher
+	broker     *watch.Broker
+	collab     *collab.Hub
+	group      string
 	content.UnimplementedContentServiceServer
 }
 
 // ServiceParams are the attributes that are required for creating new ContentService.
 type Params struct {
-	Repository repository.ContentRepository `validate:"required"`
-	Publisher  message.Publisher            `validate:"required"`
-	Options    []aphgrpc.Option             `validate:"required"`
-	Group      string                       `validate:"required"`
+	Repository repository.ContentRepository   `validate:"required"`
+	Namespaces repository.NamespaceRepository `validate:"required"`
+	Audit      repository.AuditRepository     `validate:"required"`
+	Locks      repository.LockRepository      `validate:"required"`
+	Publisher  message.Publisher              `validate:"required"`
+	Options    []aphgrpc.Option               `validate:"required"`
+	Group      string                         `validate:"required"`
+	// CheckpointInterval is the period for saving the documents of the
+	// collaborative editing sessions, defaults to collab.DefaultInterval
+	CheckpointInterval time.Duration
+	// WatchRetain is the number of the latest events kept for resuming a
+	// watch, defaults to watch.DefaultRetain
+	WatchRetain int
+	// Assets manages the embedded files, the asset methods are unavailable
+	// without it
+	Assets *asset.Manager
+	// Links keeps the links between the contents, the link methods are
+	// unavailable without it
+	Links repository.LinkRepository
 }
 
 func NewContentService(srvP *Params) (*ContentService, error) {
@@ -51,12 +79,36 @@ func NewContentService(srvP *Params) (*ContentService, error) {
 	srv := &aphgrpc.Service{}
 	aphgrpc.AssignFieldsToStructs(so, srv)
 
-	return &ContentService{
-		Service:   srv,
-		repo:      srvP.Repository,
-		publisher: srvP.Publisher,
-		group:     srvP.Group,
-	}, nil
+	actions := make(map[string]string)
+	for topic, action := range map[string]string{
+		"contentCreate": watch.ActionCreate,
+		"contentUpdate": watch.ActionUpdate,
+		"contentDelete": watch.ActionDelete,
+	} {
+		if subject, ok := srv.Topics[topic]; ok {
+			actions[subject] = action
+		}
+	}
+	broker := watch.NewBroker(actions, srvP.WatchRetain)
+	csrv := &ContentService{
+		Service:    srv,
+		repo:       srvP.Repository,
+		namespaces: srvP.Namespaces,
+		auditor:    srvP.Audit,
+		locks:      srvP.Locks,
+		links:      srvP.Links,
+		assets:     srvP.Assets,
+		publisher:  srvP.Publisher,
+		broker:     broker,
+		group:      srvP.Group,
+	}
+	csrv.collab = collab.NewHub(&collab.HubParams{
+		Load:       csrv.loadDocument,
+		Checkpoint: csrv.checkpointDocument,
+		Interval:   srvP.CheckpointInterval,
+	})
+
+	return csrv, nil
 }
 
 func (srv *ContentService) Healthz(
@@ -74,7 +126,7 @@ func (srv *ContentService) GetContentBySlug(
 	if err := rdr.Validate(); err != nil {
 		return ctnt, aphgrpc.HandleInvalidParamError(ctx, err)
 	}
-	mcont, err := srv.repo.GetContentBySlug(rdr.Slug)
+	mcont, err := srv.repo.GetContentBySlug(ctx, rdr.Slug)
 	if err != nil {
 		return ctnt, aphgrpc.HandleGetError(ctx, err)
 	}
@@ -86,7 +138,7 @@ func (srv *ContentService) GetContentBySlug(
 	}
 	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
 
-	return srv.buildContent(cid, mcont), nil
+	return conditionalContent(ctx, mcont, srv.buildContent(cid, mcont))
 }
 
 func (srv *ContentService) GetContent(
@@ -97,7 +149,7 @@ func (srv *ContentService) GetContent(
 	if err := rdr.Validate(); err != nil {
 		return ctnt, aphgrpc.HandleInvalidParamError(ctx, err)
 	}
-	mcont, err := srv.repo.GetContent(rdr.Id)
+	mcont, err := srv.repo.GetContent(ctx, rdr.Id)
 	if err != nil {
 		return ctnt, aphgrpc.HandleGetError(ctx, err)
 	}
@@ -109,7 +161,7 @@ func (srv *ContentService) GetContent(
 	}
 	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
 
-	return srv.buildContent(cid, mcont), nil
+	return conditionalContent(ctx, mcont, srv.buildContent(cid, mcont))
 }
 
 func (srv *ContentService) buildContent(
@@ -141,14 +193,25 @@ func (srv *ContentService) StoreContent(
 	if err := req.Validate(); err != nil {
 		return ctnt, aphgrpc.HandleInvalidParamError(ctx, err)
 	}
-	mcont, err := srv.repo.AddContent(req.Data.Attributes)
+	nsp, err := srv.namespaces.GetNamespace(ctx, req.Data.Attributes.Namespace)
+	if err != nil {
+		return ctnt, aphgrpc.HandleGetError(ctx, err)
+	}
+	if err := checkNamespace(nsp, req.Data.Attributes.Namespace); err != nil {
+		return ctnt, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	if err := checkContentSchema(nsp, req.Data.Attributes.Content); err != nil {
+		return ctnt, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	mcont, err := srv.repo.AddContent(ctx, req.Data.Attributes)
 	if err != nil {
 		return ctnt, aphgrpc.HandleGetError(ctx, err)
 	}
 	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
 	ctnt = srv.buildContent(cid, mcont)
-	//nolint:errcheck
-	srv.publisher.Publish(srv.Topics["contentCreate"], ctnt)
+	srv.audit(ctx, model.AuditStore, mcont.CreatedBy, nil, mcont)
+	srv.track(ctx, cid, mcont)
+	srv.publish(ctx, srv.Topics["contentCreate"], ctnt)
 
 	return ctnt, nil
 }
@@ -161,14 +224,47 @@ func (srv *ContentService) UpdateContent(
 	if err := req.Validate(); err != nil {
 		return ctnt, aphgrpc.HandleInvalidParamError(ctx, err)
 	}
-	mcont, err := srv.repo.EditContent(req.Id, req.Data.Attributes)
+	if err := srv.checkSession(ctx, req.Id); err != nil {
+		return ctnt, err
+	}
+
+	return srv.editContent(ctx, req.Id, req.Data.Attributes)
+}
+
+// editContent stores the update of a content unless it is locked by someone
+// other than the editor. An update that leaves the content as is, going by
+// its hash, is neither stored nor published and the stored content is
+// returned.
+func (srv *ContentService) editContent(
+	ctx context.Context,
+	cid int64,
+	attr *content.ExistingContentAttributes,
+) (*content.Content, error) {
+	ctnt := &content.Content{}
+	if err := srv.checkLock(ctx, cid, attr.UpdatedBy); err != nil {
+		return ctnt, err
+	}
+	before, err := srv.repo.GetContent(ctx, cid)
+	if err != nil {
+		return ctnt, aphgrpc.HandleGetError(ctx, err)
+	}
+	if !before.NotFound &&
+		model.ContentHash(before) == model.HashContent(attr.Content) {
+		return srv.buildContent(cid, before), nil
+	}
+	if !before.NotFound {
+		if err := srv.checkSchema(ctx, before.Namespace, attr.Content); err != nil {
+			return ctnt, err
+		}
+	}
+	mcont, err := srv.repo.EditContent(ctx, cid, attr)
 	if err != nil {
 		return ctnt, aphgrpc.HandleGetError(ctx, err)
 	}
-	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
 	ctnt = srv.buildContent(cid, mcont)
-	//nolint:errcheck
-	srv.publisher.Publish(srv.Topics["contentUpdate"], ctnt)
+	srv.audit(ctx, model.AuditUpdate, mcont.UpdatedBy, before, mcont)
+	srv.track(ctx, cid, mcont)
+	srv.publish(ctx, srv.Topics["contentUpdate"], ctnt)
 
 	return ctnt, nil
 }
@@ -180,10 +276,484 @@ func (srv *ContentService) DeleteContent(
 	if err := req.Validate(); err != nil {
 		return &empty.Empty{}, aphgrpc.HandleInvalidParamError(ctx, err)
 	}
-	err := srv.repo.DeleteContent(req.Id)
+	before, err := srv.repo.GetContent(ctx, req.Id)
 	if err != nil {
 		return &empty.Empty{}, aphgrpc.HandleGetError(ctx, err)
 	}
+	if err := srv.repo.DeleteContent(ctx, req.Id); err != nil {
+		return &empty.Empty{}, aphgrpc.HandleGetError(ctx, err)
+	}
+	srv.audit(ctx, model.AuditDelete, "", before, nil)
+	srv.untrack(ctx, req.Id, before)
+	srv.publish(
+		ctx,
+		srv.Topics["contentDelete"],
+		srv.buildContent(req.Id, before),
+	)
 
 	return &empty.Empty{}, nil
 }
+
+// DiffContent compares the stored content with a proposed editor json, the
+// changes lead from the stored to the proposed version.
+func (srv *ContentService) DiffContent(
+	ctx context.Context,
+	cid int64,
+	proposed string,
+	opt *diff.Options,
+) (*diff.Result, error) {
+	mcont, err := srv.repo.GetContent(ctx, cid)
+	if err != nil {
+		return nil, aphgrpc.HandleGetError(ctx, err)
+	}
+	if mcont.NotFound {
+		return nil, aphgrpc.HandleNotFoundError(
+			ctx,
+			fmt.Errorf("id %d not found", cid),
+		)
+	}
+	res, err := diff.Compare(mcont.Content, proposed, opt)
+	if err != nil {
+		return nil, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+
+	return res, nil
+}
+
+// publish sends the content to the subject, a failure does not fail the
+// request as the content is already stored but it is logged.
+func (srv *ContentService) publish(
+	ctx context.Context,
+	subject string,
+	ctnt *content.Content,
+) {
+	if err := srv.publisher.Publish(ctx, subject, ctnt); err != nil {
+		log.Printf(
+			"error in publishing content %d to %s %s",
+			ctnt.Data.Id,
+			subject,
+			err,
+		)
+	}
+}
+
+// checkSchema validates the body against the content schema of the
+// namespace.
+func (srv *ContentService) checkSchema(
+	ctx context.Context,
+	namespace, body string,
+) error {
+	nsp, err := srv.namespaces.GetNamespace(ctx, namespace)
+	if err != nil {
+		return aphgrpc.HandleGetError(ctx, err)
+	}
+	if err := checkContentSchema(nsp, body); err != nil {
+		return aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+
+	return nil
+}
+
+func checkNamespace(nsp *model.NamespaceDoc, name string) error {
+	if nsp.NotFound {
+		return fmt.Errorf("namespace %s is not registered", name)
+	}
+	if nsp.Archived {
+		return fmt.Errorf("namespace %s is archived", name)
+	}
+
+	return nil
+}
+
+// TransferContents copies or moves contents between namespaces and publishes
+// a create(copy) or update(move) event for every affected content. A move is
+// audited as a rename from the slug and namespace the content had before, as
+// the body of the content is left as is.
+func (srv *ContentService) TransferContents(
+	ctx context.Context,
+	trn *model.ContentTransfer,
+) ([]*content.Content, error) {
+	ctnts := make([]*content.Content, 0)
+	if err := validator.New().Struct(trn); err != nil {
+		return ctnts, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	nsp, err := srv.namespaces.GetNamespace(ctx, trn.To)
+	if err != nil {
+		return ctnts, aphgrpc.HandleGetError(ctx, err)
+	}
+	if err := checkNamespace(nsp, trn.To); err != nil {
+		return ctnts, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	if !trn.Move && trn.SlugPattern == "" {
+		return ctnts, aphgrpc.HandleInvalidParamError(
+			ctx,
+			fmt.Errorf("copy needs a slug pattern as slugs are unique"),
+		)
+	}
+	sources, err := srv.transferSources(ctx, trn)
+	if err != nil {
+		return ctnts, err
+	}
+	for _, mcont := range sources {
+		if err := checkContentSchema(nsp, mcont.Content); err != nil {
+			return ctnts, aphgrpc.HandleInvalidParamError(ctx, err)
+		}
+	}
+	if trn.Move {
+		if err := srv.checkMove(ctx, sources, trn.UpdatedBy); err != nil {
+			return ctnts, err
+		}
+	}
+	befores := make(map[string]*model.ContentDoc, len(sources))
+	for _, mcont := range sources {
+		befores[mcont.Key] = mcont
+	}
+	mconts, err := srv.repo.TransferContents(ctx, trn)
+	if err != nil {
+		return ctnts, aphgrpc.HandleUpdateError(ctx, err)
+	}
+	topic := srv.Topics["contentCreate"]
+	if trn.Move {
+		topic = srv.Topics["contentUpdate"]
+	}
+	for _, mcont := range mconts {
+		cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+		ctnt := srv.buildContent(cid, mcont)
+		if trn.Move {
+			before, ok := befores[mcont.Key]
+			if !ok {
+				before = mcont
+			}
+			srv.audit(ctx, model.AuditRename, trn.UpdatedBy, before, mcont)
+		} else {
+			srv.audit(ctx, model.AuditStore, trn.UpdatedBy, nil, mcont)
+		}
+		srv.track(ctx, cid, mcont)
+		srv.publish(ctx, topic, ctnt)
+		ctnts = append(ctnts, ctnt)
+	}
+
+	return ctnts, nil
+}
+
+// transferSources returns the contents the transfer takes from the source
+// namespace, every requested slug has to belong to it so that none of them
+// are skipped silently.
+func (srv *ContentService) transferSources(
+	ctx context.Context,
+	trn *model.ContentTransfer,
+) ([]*model.ContentDoc, error) {
+	if len(trn.Slugs) == 0 {
+		sources, err := srv.repo.ListContents(ctx, []string{trn.From})
+		if err != nil {
+			return nil, aphgrpc.HandleGetError(ctx, err)
+		}
+
+		return sources, nil
+	}
+	sources := make([]*model.ContentDoc, 0, len(trn.Slugs))
+	for _, slug := range trn.Slugs {
+		mcont, err := srv.repo.GetContentBySlug(ctx, slug)
+		if err != nil {
+			return nil, aphgrpc.HandleGetError(ctx, err)
+		}
+		if mcont.NotFound || mcont.Namespace != trn.From {
+			return nil, aphgrpc.HandleNotFoundError(
+				ctx,
+				fmt.Errorf("slug %s not found in namespace %s", slug, trn.From),
+			)
+		}
+		sources = append(sources, mcont)
+	}
+
+	return sources, nil
+}
+
+// checkMove refuses a move of contents that are leased to another user or
+// edited in a session, like an update of any of them would be.
+func (srv *ContentService) checkMove(
+	ctx context.Context,
+	sources []*model.ContentDoc,
+	editor string,
+) error {
+	for _, mcont := range sources {
+		cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+		if err := srv.checkSession(ctx, cid); err != nil {
+			return err
+		}
+		if err := srv.checkLock(ctx, cid, editor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BatchResult is the outcome of a single operation of a batch.
+type BatchResult struct {
+	Action  string
+	Content *content.Content
+	Err     error
+}
+
+// BatchContents runs a list of create, update and delete operations either
+// atomically or in a best effort mode with per operation results. Events for
+// the affected contents are published only after the batch is committed. An
+// update of a content locked by someone else or being edited in a session
+// refuses the whole batch, an update that leaves the content as is is
+// neither written, audited nor published.
+func (srv *ContentService) BatchContents(
+	ctx context.Context,
+	ops []*model.BatchOperation,
+	atomic bool,
+) ([]*BatchResult, error) {
+	results := make([]*BatchResult, 0)
+	for idx, bop := range ops {
+		if err := srv.validateOperation(ctx, bop); err != nil {
+			return results, aphgrpc.HandleInvalidParamError(
+				ctx,
+				fmt.Errorf("invalid batch operation %d %s", idx, err),
+			)
+		}
+	}
+	for _, bop := range ops {
+		if bop.Action != model.BatchUpdate {
+			continue
+		}
+		if err := srv.checkSession(ctx, bop.ID); err != nil {
+			return results, err
+		}
+		if err := srv.checkLock(ctx, bop.ID, bop.Update.UpdatedBy); err != nil {
+			return results, err
+		}
+	}
+	befores, err := srv.priorContents(ctx, ops)
+	if err != nil {
+		return results, aphgrpc.HandleGetError(ctx, err)
+	}
+	mresults, priors, err := srv.writeBatch(ctx, ops, befores, atomic)
+	if err != nil {
+		return results, aphgrpc.HandleUpdateError(ctx, err)
+	}
+	topics := map[string]string{
+		model.BatchCreate: srv.Topics["contentCreate"],
+		model.BatchUpdate: srv.Topics["contentUpdate"],
+		model.BatchDelete: srv.Topics["contentDelete"],
+	}
+	for idx, mres := range mresults {
+		if mres.Err != nil {
+			results = append(results, &BatchResult{
+				Action: mres.Action,
+				Err:    mres.Err,
+			})
+
+			continue
+		}
+		cid, _ := strconv.ParseInt(mres.Content.Key, 10, 64)
+		ctnt := srv.buildContent(cid, mres.Content)
+		if mres.Action != model.BatchUpdate ||
+			model.ContentHash(mres.Content) != priors[idx] {
+			srv.auditOperation(ctx, ops[idx], befores[idx], mres.Content)
+			srv.publish(ctx, topics[mres.Action], ctnt)
+		}
+		results = append(results, &BatchResult{
+			Action:  mres.Action,
+			Content: ctnt,
+		})
+	}
+
+	return results, nil
+}
+
+// writeBatch runs the operations of the batch that change the contents. An
+// update that leaves the content as is, going by its hash, is not written
+// and its result is the content as left by the earlier operations, the
+// results keep the order of the operations. Along with the results it
+// returns the hash every update saw before it. In the best effort mode the
+// operations are written one at a time, as the hash a failed operation would
+// have left is never stored.
+func (srv *ContentService) writeBatch(
+	ctx context.Context,
+	ops []*model.BatchOperation,
+	befores map[int]*model.ContentDoc,
+	atomic bool,
+) ([]*model.BatchResult, []string, error) {
+	mresults := make([]*model.BatchResult, len(ops))
+	priors := make([]string, len(ops))
+	writes := make([]*model.BatchOperation, 0, len(ops))
+	positions := make([]int, 0, len(ops))
+	// the hashes of the contents as left by the earlier operations along
+	// with the position of the operation that left them
+	hashes := make(map[int64]string)
+	lasts := make(map[int64]int)
+	skipped := make(map[int]int)
+	for idx, bop := range ops {
+		if bop.Action == model.BatchUpdate {
+			hash, ok := hashes[bop.ID]
+			if !ok {
+				hash = model.ContentHash(befores[idx])
+			}
+			priors[idx] = hash
+			if len(hash) > 0 && hash == model.HashContent(bop.Update.Content) {
+				if last, ok := lasts[bop.ID]; ok {
+					skipped[idx] = last
+				} else {
+					mresults[idx] = &model.BatchResult{
+						Action:  model.BatchUpdate,
+						Content: befores[idx],
+					}
+				}
+
+				continue
+			}
+		}
+		if atomic {
+			// all or none of the operations are written
+			hash := model.HashContent(bop.Update.GetContent())
+			recordHash(hashes, lasts, idx, bop, hash)
+			writes = append(writes, bop)
+			positions = append(positions, idx)
+
+			continue
+		}
+		written, err := srv.repo.BatchContents(
+			ctx,
+			[]*model.BatchOperation{bop},
+			false,
+		)
+		if err != nil {
+			return mresults, priors, err
+		}
+		mresults[idx] = written[0]
+		if written[0].Err == nil {
+			hash := model.ContentHash(written[0].Content)
+			recordHash(hashes, lasts, idx, bop, hash)
+		}
+	}
+	if len(writes) > 0 {
+		written, err := srv.repo.BatchContents(ctx, writes, atomic)
+		if err != nil {
+			return mresults, priors, err
+		}
+		for pos, mres := range written {
+			mresults[positions[pos]] = mres
+		}
+	}
+	for idx, last := range skipped {
+		mresults[idx] = &model.BatchResult{
+			Action:  model.BatchUpdate,
+			Content: mresults[last].Content,
+			Err:     mresults[last].Err,
+		}
+	}
+
+	return mresults, priors, nil
+}
+
+// recordHash keeps the hash of the content that an update at the position
+// leaves, a deleted content has none.
+func recordHash(
+	hashes map[int64]string,
+	lasts map[int64]int,
+	idx int,
+	bop *model.BatchOperation,
+	hash string,
+) {
+	switch bop.Action {
+	case model.BatchUpdate:
+		hashes[bop.ID] = hash
+		lasts[bop.ID] = idx
+	case model.BatchDelete:
+		hashes[bop.ID] = ""
+		delete(lasts, bop.ID)
+	}
+}
+
+// priorContents reads the contents that are about to be updated by the
+// batch, keyed by the index of the operation.
+func (srv *ContentService) priorContents(
+	ctx context.Context,
+	ops []*model.BatchOperation,
+) (map[int]*model.ContentDoc, error) {
+	befores := make(map[int]*model.ContentDoc)
+	for idx, bop := range ops {
+		if bop.Action != model.BatchUpdate {
+			continue
+		}
+		mcont, err := srv.repo.GetContent(ctx, bop.ID)
+		if err != nil {
+			return befores, err
+		}
+		befores[idx] = mcont
+	}
+
+	return befores, nil
+}
+
+// auditOperation audits and tracks a single batch operation, for a delete
+// the content is the removed document.
+func (srv *ContentService) auditOperation(
+	ctx context.Context,
+	bop *model.BatchOperation,
+	before, mcont *model.ContentDoc,
+) {
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	switch bop.Action {
+	case model.BatchCreate:
+		srv.audit(ctx, model.AuditStore, mcont.CreatedBy, nil, mcont)
+		srv.track(ctx, cid, mcont)
+	case model.BatchUpdate:
+		srv.audit(ctx, model.AuditUpdate, mcont.UpdatedBy, before, mcont)
+		srv.track(ctx, cid, mcont)
+	case model.BatchDelete:
+		srv.audit(ctx, model.AuditDelete, "", mcont, nil)
+		srv.untrack(ctx, cid, mcont)
+	}
+}
+
+func (srv *ContentService) validateOperation(
+	ctx context.Context,
+	bop *model.BatchOperation,
+) error {
+	if err := validator.New().Struct(bop); err != nil {
+		return err
+	}
+	switch bop.Action {
+	case model.BatchCreate:
+		if bop.Create == nil {
+			return fmt.Errorf("missing attributes for creating content")
+		}
+		if err := bop.Create.Validate(); err != nil {
+			return err
+		}
+		nsp, err := srv.namespaces.GetNamespace(ctx, bop.Create.Namespace)
+		if err != nil {
+			return err
+		}
+
+		if err := checkNamespace(nsp, bop.Create.Namespace); err != nil {
+			return err
+		}
+
+		return checkContentSchema(nsp, bop.Create.Content)
+	case model.BatchUpdate:
+		if bop.Update == nil {
+			return fmt.Errorf("missing attributes for updating content")
+		}
+		if err := bop.Update.Validate(); err != nil {
+			return err
+		}
+		mcont, err := srv.repo.GetContent(ctx, bop.ID)
+		if err != nil || mcont.NotFound {
+			return err
+		}
+		nsp, err := srv.namespaces.GetNamespace(ctx, mcont.Namespace)
+		if err != nil {
+			return err
+		}
+
+		return checkContentSchema(nsp, bop.Update.Content)
+	}
+
+	return nil
+}
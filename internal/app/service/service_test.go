@@ -9,10 +9,10 @@ import (
 	"time"
 
 	"github.com/dictyBase/aphgrpc"
-	manager "github.com/dictyBase/arangomanager"
-	"github.com/dictyBase/arangomanager/testarango"
 	"github.com/dictyBase/go-genproto/dictybaseapis/content"
-	"github.com/dictyBase/modware-content/internal/repository/arangodb"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
 	"github.com/dictyBase/modware-content/internal/testutils"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -24,7 +24,11 @@ import (
 
 type MockMessage struct{}
 
-func (msn *MockMessage) Publish(subject string, cont *content.Content) error {
+func (msn *MockMessage) Publish(
+	ctx context.Context,
+	subject string,
+	cont *content.Content,
+) error {
 	return nil
 }
 
@@ -33,28 +37,36 @@ func (msn *MockMessage) Close() error {
 }
 
 func setup(t *testing.T) (content.ContentServiceClient, *require.Assertions) {
+	t.Helper()
+	client, assert, _ := setupWithAudit(t)
+
+	return client, assert
+}
+
+// setupWithAudit also returns the audit repository that is given to the
+// service.
+func setupWithAudit(t *testing.T) (
+	content.ContentServiceClient,
+	*require.Assertions,
+	repository.AuditRepository,
+) {
 	t.Helper()
 	assert := require.New(t)
-	tra, err := testarango.NewTestArangoFromEnv(true)
-	assert.NoError(err, "expect no error from creating an arangodb instance")
-	repo, err := arangodb.NewContentRepo(
-		&manager.ConnectParams{
-			User:     tra.User,
-			Pass:     tra.Pass,
-			Database: tra.Database,
-			Host:     tra.Host,
-			Port:     tra.Port,
-			Istls:    false,
-		}, manager.RandomString(16, 19),
-	)
-	assert.NoErrorf(
-		err,
-		"expect no error connecting to annotation repository, received %s",
-		err,
-	)
+	aud := memory.NewAuditRepo()
+	repo := memory.NewContentRepo()
+	nrepo := memory.NewNamespaceRepo()
+	_, err := nrepo.AddNamespace(context.Background(), &model.NamespaceDoc{
+		Name:        "dsc",
+		DisplayName: "Dicty Stock Center",
+		CreatedBy:   "content@content.org",
+	})
+	assert.NoError(err, "expect no error from registering namespace")
 	baseServer := grpc.NewServer()
 	srv, err := NewContentService(&Params{
 		Repository: repo,
+		Namespaces: nrepo,
+		Audit:      aud,
+		Locks:      memory.NewLockRepo(),
 		Publisher:  &MockMessage{},
 		Group:      "groups",
 		Options: []aphgrpc.Option{
@@ -88,13 +100,12 @@ func setup(t *testing.T) (content.ContentServiceClient, *require.Assertions) {
 	)
 	assert.NoError(err, "expect no error in creating grpc client")
 	t.Cleanup(func() {
-		_ = repo.Dbh().Drop()
 		conn.Close()
 		listener.Close()
 		baseServer.Stop()
 	})
 
-	return content.NewContentServiceClient(conn), assert
+	return content.NewContentServiceClient(conn), assert, aud
 }
 
 func TestStoreContent(t *testing.T) {
@@ -135,6 +146,25 @@ func TestStoreContent(t *testing.T) {
 	)
 }
 
+func TestStoreContentNamespace(t *testing.T) {
+	t.Parallel()
+	client, assert := setup(t)
+	_, err := client.StoreContent(
+		context.Background(),
+		&content.StoreContentRequest{
+			Data: &content.StoreContentRequest_Data{
+				Attributes: testutils.NewStoreContent("catalog", "DSC"),
+			},
+		},
+	)
+	assert.Error(err, "expect error from storing in unknown namespace")
+	assert.Equal(
+		status.Code(err),
+		codes.InvalidArgument,
+		"should match the invalid argument error",
+	)
+}
+
 func TestGetContentBySlug(t *testing.T) {
 	t.Parallel()
 	client, assert := setup(t)
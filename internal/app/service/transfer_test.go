@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTransferContents(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	nrepo := memory.NewNamespaceRepo()
+	locks := memory.NewLockRepo()
+	_, err := nrepo.AddNamespace(ctx, &model.NamespaceDoc{
+		Name:        "dictybase",
+		DisplayName: "dictyBase",
+		CreatedBy:   "content@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from adding namespace %s", err)
+	srv, err := NewContentService(&Params{
+		Repository: repo,
+		Namespaces: nrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      locks,
+		Publisher:  &MockMessage{},
+		Group:      "groups",
+		Options:    []aphgrpc.Option{aphgrpc.TopicsOption(nil)},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	cids := make([]int64, 0)
+	for _, name := range []string{"catalog", "order"} {
+		mcont, err := repo.AddContent(
+			ctx,
+			testutils.NewStoreContent(name, "dsc"),
+		)
+		assert.NoErrorf(err, "expect no error from creating content %s", err)
+		cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+		cids = append(cids, cid)
+	}
+	_, err = srv.TransferContents(ctx, &model.ContentTransfer{
+		From:      "dsc",
+		To:        "dictybase",
+		UpdatedBy: "packer@packer.com",
+	})
+	assert.Equal(
+		codes.InvalidArgument,
+		status.Code(err),
+		"expect copy without slug pattern to be rejected",
+	)
+	_, err = srv.TransferContents(ctx, &model.ContentTransfer{
+		From:            "dsc",
+		To:              "dictybase",
+		Slugs:           []string{"catalog-dsc", "price-dsc"},
+		SlugPattern:     "-dsc$",
+		SlugReplacement: "-dictybase",
+		UpdatedBy:       "packer@packer.com",
+	})
+	assert.Equal(
+		codes.NotFound,
+		status.Code(err),
+		"expect missing slug to be reported",
+	)
+	ecnt, err := repo.GetContentBySlug(ctx, "catalog-dictybase")
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.True(ecnt.NotFound, "expect nothing to be copied")
+	ctnts, err := srv.TransferContents(ctx, &model.ContentTransfer{
+		From:            "dsc",
+		To:              "dictybase",
+		Slugs:           []string{"catalog-dsc"},
+		SlugPattern:     "-dsc$",
+		SlugReplacement: "-dictybase",
+		UpdatedBy:       "packer@packer.com",
+	})
+	assert.NoErrorf(err, "expect no error from copying contents %s", err)
+	assert.Len(ctnts, 1, "expect a single copied content")
+	move := &model.ContentTransfer{
+		From:      "dsc",
+		To:        "dictybase",
+		Slugs:     []string{"order-dsc"},
+		Move:      true,
+		UpdatedBy: "packer@packer.com",
+	}
+	_, err = locks.AcquireLock(ctx, cids[1], "curator@content.org", time.Minute)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	_, err = srv.TransferContents(ctx, move)
+	assert.Equal(
+		codes.FailedPrecondition,
+		status.Code(err),
+		"expect move of a leased content to be refused",
+	)
+	err = locks.ReleaseLock(ctx, cids[1], "curator@content.org")
+	assert.NoErrorf(err, "expect no error from releasing lock %s", err)
+	editor, err := srv.CollaborateContent(ctx, cids[1], "curator@content.org")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	_, err = srv.TransferContents(ctx, move)
+	assert.Equal(
+		codes.FailedPrecondition,
+		status.Code(err),
+		"expect move of a content in a session to be refused",
+	)
+	assert.NoError(editor.Leave(), "expect no error from leaving")
+	ctnts, err = srv.TransferContents(ctx, move)
+	assert.NoErrorf(err, "expect no error from moving contents %s", err)
+	assert.Equal(
+		ctnts[0].Data.Attributes.Slug,
+		"order-dsc",
+		"expect a move to keep the slug",
+	)
+}
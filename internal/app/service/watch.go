@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/watch"
+)
+
+// EventHandlers feed the watches with the create, update and delete events,
+// keyed by their subject. They are meant to be subscribed to the messaging
+// server, so the changes made through any replica are watched.
+func (srv *ContentService) EventHandlers() map[string]message.EventHandler {
+	return srv.broker.Handlers()
+}
+
+// WatchContents delivers the create, update and delete events of the
+// contents that pass the filter until the context is done. The retained
+// events after the given sequence are delivered first, a zero sequence
+// watches only the events to come.
+func (srv *ContentService) WatchContents(
+	ctx context.Context,
+	flt *watch.Filter,
+	after uint64,
+) (*watch.Subscription, error) {
+	sub, err := srv.broker.Subscribe(flt, after)
+	if err != nil {
+		return nil, aphgrpc.HandleInvalidParamError(ctx, err)
+	}
+	go func() {
+		<-ctx.Done()
+		sub.Close()
+	}()
+
+	return sub, nil
+}
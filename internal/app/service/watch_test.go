@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/dictyBase/modware-content/internal/watch"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// loopback hands the published contents to the event handlers like the
+// subscription of the messaging server.
+type loopback struct {
+	MockMessage
+	handlers map[string]message.EventHandler
+}
+
+func (lbk *loopback) Publish(
+	ctx context.Context,
+	subject string,
+	cont *content.Content,
+) error {
+	if hdl, ok := lbk.handlers[subject]; ok {
+		hdl(ctx, cont)
+	}
+
+	return nil
+}
+
+func TestWatchContents(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert := require.New(t)
+	nrepo := memory.NewNamespaceRepo()
+	for _, name := range []string{"dsc", "dfp"} {
+		_, err := nrepo.AddNamespace(ctx, &model.NamespaceDoc{
+			Name:        name,
+			DisplayName: name,
+			CreatedBy:   "content@content.org",
+		})
+		assert.NoErrorf(err, "expect no error from adding namespace %s", err)
+	}
+	pub := &loopback{}
+	srv, err := NewContentService(&Params{
+		Repository: memory.NewContentRepo(),
+		Namespaces: nrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  pub,
+		Group:      "groups",
+		Options: []aphgrpc.Option{
+			aphgrpc.TopicsOption(map[string]string{
+				"contentCreate": "ContentService.Create",
+				"contentDelete": "ContentService.Delete",
+				"contentUpdate": "ContentService.Update",
+			}),
+		},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	pub.handlers = srv.EventHandlers()
+	sub, err := srv.WatchContents(ctx, &watch.Filter{Namespace: "dsc"}, 0)
+	assert.NoErrorf(err, "expect no error from watching %s", err)
+	store := func(name, namespace string) *content.Content {
+		ctnt, err := srv.StoreContent(ctx, &content.StoreContentRequest{
+			Data: &content.StoreContentRequest_Data{
+				Attributes: testutils.NewStoreContent(name, namespace),
+			},
+		})
+		assert.NoErrorf(err, "expect no error from storing content %s", err)
+
+		return ctnt
+	}
+	ctnt := store("catalog", "dsc")
+	store("catalog", "dfp")
+	_, err = srv.UpdateContent(
+		ctx,
+		updateRequest(ctnt.Data.Id, "curator@content.org"),
+	)
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	_, err = srv.DeleteContent(ctx, &content.ContentIdRequest{Id: ctnt.Data.Id})
+	assert.NoErrorf(err, "expect no error from deleting content %s", err)
+	actions := make([]string, 0)
+	for idx := 0; idx < 3; idx++ {
+		evt := <-sub.Events()
+		assert.Equal(evt.Content.Data.Id, ctnt.Data.Id, "should match content")
+		actions = append(actions, evt.Action)
+	}
+	assert.Equal(
+		actions,
+		[]string{watch.ActionCreate, watch.ActionUpdate, watch.ActionDelete},
+		"should watch every change of the namespace",
+	)
+	resumed, err := srv.WatchContents(
+		ctx,
+		&watch.Filter{SlugPrefix: "catalog"},
+		1,
+	)
+	assert.NoErrorf(err, "expect no error from resuming %s", err)
+	evt := <-resumed.Events()
+	assert.Equal(evt.Sequence, uint64(2), "should resume after the sequence")
+	assert.Equal(
+		evt.Content.Data.Attributes.Namespace,
+		"dfp",
+		"should match the other namespace",
+	)
+	_, err = srv.WatchContents(ctx, nil, 100)
+	assert.Equal(
+		status.Code(err),
+		codes.InvalidArgument,
+		"expect invalid argument for unknown sequence",
+	)
+	cancel()
+	// drains the events until the watch is closed
+	for range sub.Events() {
+	}
+	assert.NoError(sub.Err(), "expect watch to end with the context")
+}
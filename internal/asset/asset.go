@@ -0,0 +1,267 @@
+// Package asset manages the images and files embedded in the contents. The
+// bytes of an asset go to a blob store while its metadata, along with the
+// contents that embed it, is kept in the asset repository.
+package asset
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+
+	"github.com/dictyBase/modware-content/internal/blob"
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// DefaultMaxSize is the largest upload accepted by default, 10 MiB.
+const DefaultMaxSize = 10 << 20
+
+// the mime type is sniffed from the leading bytes of the upload.
+const sniffLen = 3072
+
+var (
+	// ErrNotFound is returned for an asset id without an asset.
+	ErrNotFound = errors.New("asset not found")
+	// ErrTooLarge is returned when the upload is over the size limit.
+	ErrTooLarge = errors.New("asset is larger than the size limit")
+	// ErrReferenced is returned when deleting an asset that is still
+	// embedded in a content.
+	ErrReferenced = errors.New("asset is embedded in contents")
+)
+
+// Params are the stores of the manager, a MaxSize of zero or less means
+// no limit. Contents is read for the slugs of the contents that embed a
+// deleted asset.
+type Params struct {
+	Repo     repository.AssetRepository
+	Blobs    blob.Store
+	Contents repository.ContentRepository
+	MaxSize  int64
+}
+
+// Manager uploads, downloads and deletes the assets.
+type Manager struct {
+	repo     repository.AssetRepository
+	blobs    blob.Store
+	contents repository.ContentRepository
+	maxSize  int64
+}
+
+func NewManager(params *Params) *Manager {
+	return &Manager{
+		repo:     params.Repo,
+		blobs:    params.Blobs,
+		contents: params.Contents,
+		maxSize:  params.MaxSize,
+	}
+}
+
+// Upload streams the reader into a new blob and records its metadata. The
+// blob is removed again when the upload is too large or the metadata could
+// not be stored.
+func (mgr *Manager) Upload(
+	ctx context.Context,
+	name, createdBy string,
+	rdr io.Reader,
+) (*model.AssetDoc, error) {
+	head := make([]byte, sniffLen)
+	nread, err := io.ReadFull(rdr, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) &&
+		!errors.Is(err, io.EOF) {
+		return &model.AssetDoc{}, fmt.Errorf("error in reading asset %s", err)
+	}
+	head = head[:nread]
+	key, err := blobKey()
+	if err != nil {
+		return &model.AssetDoc{}, err
+	}
+	crdr := &countReader{
+		rdr:   io.MultiReader(bytes.NewReader(head), rdr),
+		hash:  sha256.New(),
+		limit: mgr.maxSize,
+	}
+	if err := mgr.blobs.Put(ctx, key, crdr); err != nil {
+		//nolint:errcheck
+		mgr.blobs.Delete(ctx, key)
+		if crdr.over {
+			return &model.AssetDoc{}, fmt.Errorf(
+				"error in storing asset %s %w",
+				name,
+				ErrTooLarge,
+			)
+		}
+
+		return &model.AssetDoc{}, fmt.Errorf("error in storing asset %s", err)
+	}
+	ast, err := mgr.repo.AddAsset(ctx, &model.AssetDoc{
+		Name:      name,
+		MimeType:  mimetype.Detect(head).String(),
+		Size:      crdr.count,
+		Checksum:  hex.EncodeToString(crdr.hash.Sum(nil)),
+		BlobKey:   key,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		//nolint:errcheck
+		mgr.blobs.Delete(ctx, key)
+
+		return &model.AssetDoc{}, err
+	}
+
+	return ast, nil
+}
+
+// Download returns the metadata of the asset and opens its blob, the caller
+// should close it.
+func (mgr *Manager) Download(
+	ctx context.Context,
+	aid int64,
+) (*model.AssetDoc, io.ReadCloser, error) {
+	ast, err := mgr.find(ctx, aid)
+	if err != nil {
+		return ast, nil, err
+	}
+	rdc, err := mgr.blobs.Get(ctx, ast.BlobKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return ast, nil, fmt.Errorf(
+				"error in reading asset %d %w",
+				aid,
+				ErrNotFound,
+			)
+		}
+
+		return ast, nil, fmt.Errorf("error in reading asset %d %s", aid, err)
+	}
+
+	return ast, rdc, nil
+}
+
+// Delete removes the asset and its blob, an asset that is still embedded
+// is only removed when forced. The slugs of the contents that are left
+// embedding the removed asset are returned.
+func (mgr *Manager) Delete(
+	ctx context.Context,
+	aid int64,
+	force bool,
+) ([]string, error) {
+	ast, err := mgr.find(ctx, aid)
+	if err != nil {
+		return nil, err
+	}
+	slugs, err := mgr.referenceSlugs(ctx, ast.References)
+	if err != nil {
+		return nil, err
+	}
+	if len(ast.References) > 0 && !force {
+		return slugs, fmt.Errorf(
+			"error in deleting asset %d embedded in %v %w",
+			aid,
+			slugs,
+			ErrReferenced,
+		)
+	}
+	if err := mgr.repo.RemoveAsset(ctx, aid); err != nil {
+		return nil, err
+	}
+	if err := mgr.blobs.Delete(ctx, ast.BlobKey); err != nil {
+		return slugs, fmt.Errorf(
+			"error in deleting blob of asset %d %s",
+			aid,
+			err,
+		)
+	}
+
+	return slugs, nil
+}
+
+// referenceSlugs reads the slugs of the contents, a content that is gone is
+// left out. The ids are given as is without a content repository.
+func (mgr *Manager) referenceSlugs(
+	ctx context.Context,
+	cids []int64,
+) ([]string, error) {
+	slugs := make([]string, 0, len(cids))
+	for _, cid := range cids {
+		if mgr.contents == nil {
+			slugs = append(slugs, strconv.FormatInt(cid, 10))
+
+			continue
+		}
+		mcont, err := mgr.contents.GetContent(ctx, cid)
+		if err != nil {
+			return slugs, fmt.Errorf("error in reading content %d %s", cid, err)
+		}
+		if !mcont.NotFound {
+			slugs = append(slugs, mcont.Slug)
+		}
+	}
+
+	return slugs, nil
+}
+
+// SetReferences records the assets embedded in the body of the content.
+func (mgr *Manager) SetReferences(
+	ctx context.Context,
+	cid int64,
+	body string,
+) error {
+	return mgr.repo.SetReferences(ctx, cid, links.AssetRefs(body))
+}
+
+func (mgr *Manager) find(
+	ctx context.Context,
+	aid int64,
+) (*model.AssetDoc, error) {
+	ast, err := mgr.repo.GetAsset(ctx, aid)
+	if err != nil {
+		return ast, err
+	}
+	if ast.NotFound {
+		return ast, fmt.Errorf("error in finding asset %d %w", aid, ErrNotFound)
+	}
+
+	return ast, nil
+}
+
+// blobKey is a random hex key, uploads of the same file get separate blobs.
+func blobKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error in generating blob key %s", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// countReader hashes and counts the bytes passing through it and fails once
+// they go over the limit.
+type countReader struct {
+	rdr   io.Reader
+	hash  hash.Hash
+	count int64
+	limit int64
+	over  bool
+}
+
+func (crd *countReader) Read(buf []byte) (int, error) {
+	nread, err := crd.rdr.Read(buf)
+	crd.count += int64(nread)
+	crd.hash.Write(buf[:nread])
+	if crd.limit > 0 && crd.count > crd.limit {
+		crd.over = true
+
+		return nread, ErrTooLarge
+	}
+
+	return nread, err
+}
@@ -0,0 +1,114 @@
+package asset
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/dictyBase/modware-content/internal/blob"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// a one pixel png.
+var pixel, _ = hex.DecodeString(
+	"89504e470d0a1a0a0000000d4948445200000001000000010806000000" +
+		"1f15c4890000000d49444154789c6360000002000001e221bc330000" +
+		"000049454e44ae426082",
+)
+
+func newManager(
+	t *testing.T,
+	maxSize int64,
+) (*Manager, repository.AssetRepository) {
+	t.Helper()
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	require.NoErrorf(t, err, "expect no error from local store %s", err)
+	repo := memory.NewAssetRepo()
+
+	return NewManager(
+		&Params{Repo: repo, Blobs: blobs, MaxSize: maxSize},
+	), repo
+}
+
+func TestUpload(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	ctx := context.Background()
+	mgr, _ := newManager(t, DefaultMaxSize)
+	ast, err := mgr.Upload(
+		ctx, "pixel.png", "art@vandelay.com", bytes.NewReader(pixel),
+	)
+	assert.NoErrorf(err, "expect no error from upload %s", err)
+	sum := sha256.Sum256(pixel)
+	assert.Equal("image/png", ast.MimeType, "should sniff the mime type")
+	assert.Equal(int64(len(pixel)), ast.Size, "should match the size")
+	assert.Equal(hex.EncodeToString(sum[:]), ast.Checksum, "should match sum")
+	aid, err := strconv.ParseInt(ast.Key, 10, 64)
+	assert.NoErrorf(err, "expect no error from parsing key %s", err)
+	doc, rdc, err := mgr.Download(ctx, aid)
+	assert.NoErrorf(err, "expect no error from download %s", err)
+	defer rdc.Close()
+	data, err := io.ReadAll(rdc)
+	assert.NoErrorf(err, "expect no error from reading blob %s", err)
+	assert.Equal(pixel, data, "should match the uploaded bytes")
+	assert.Equal("pixel.png", doc.Name, "should match the name")
+	text, err := mgr.Upload(
+		ctx, "notes.txt", "art@vandelay.com", bytes.NewBufferString("notes"),
+	)
+	assert.NoErrorf(err, "expect no error from upload %s", err)
+	assert.Contains(text.MimeType, "text/plain", "should sniff plain text")
+	assert.Equal(int64(5), text.Size, "should match the size")
+}
+
+func TestUploadLimit(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	ctx := context.Background()
+	mgr, repo := newManager(t, 10)
+	_, err := mgr.Upload(
+		ctx, "big.txt", "art@vandelay.com", bytes.NewReader(make([]byte, 11)),
+	)
+	assert.ErrorIs(err, ErrTooLarge, "should reject upload over the limit")
+	stats, err := repo.Stats(ctx)
+	assert.NoErrorf(err, "expect no error from stats %s", err)
+	assert.Equal(int64(0), stats.Records, "should not record the asset")
+	_, err = mgr.Upload(
+		ctx, "fit.txt", "art@vandelay.com", bytes.NewReader(make([]byte, 10)),
+	)
+	assert.NoErrorf(err, "expect no error from upload at the limit %s", err)
+	_, err = mgr.Upload(
+		ctx, "fit.txt", "not an email", bytes.NewReader(make([]byte, 10)),
+	)
+	assert.Error(err, "should reject invalid metadata")
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	ctx := context.Background()
+	mgr, _ := newManager(t, 0)
+	ast, err := mgr.Upload(
+		ctx, "pixel.png", "art@vandelay.com", bytes.NewReader(pixel),
+	)
+	assert.NoErrorf(err, "expect no error from upload %s", err)
+	aid, err := strconv.ParseInt(ast.Key, 10, 64)
+	assert.NoErrorf(err, "expect no error from parsing key %s", err)
+	body := `{"src":"/assets/` + ast.Key + `"}`
+	err = mgr.SetReferences(ctx, 7, body)
+	assert.NoErrorf(err, "expect no error from setting references %s", err)
+	_, err = mgr.Delete(ctx, aid, false)
+	assert.ErrorIs(err, ErrReferenced, "should refuse an embedded asset")
+	refs, err := mgr.Delete(ctx, aid, true)
+	assert.NoErrorf(err, "expect no error from forced delete %s", err)
+	assert.Equal(refs, []string{"7"}, "should report the dangling reference")
+	_, _, err = mgr.Download(ctx, aid)
+	assert.ErrorIs(err, ErrNotFound, "should not find the deleted asset")
+	_, err = mgr.Delete(ctx, aid, false)
+	assert.ErrorIs(err, ErrNotFound, "should not delete a missing asset")
+}
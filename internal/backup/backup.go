@@ -0,0 +1,233 @@
+// Package backup reads and writes dumps of contents either as newline
+// delimited json(ndjson) or as a gzipped tar archive.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/dictyBase/modware-content/internal/model"
+)
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatArchive = "tar.gz"
+	metadataFile  = "metadata.json"
+	contentsFile  = "contents.ndjson"
+	fileMode      = 0o644
+	maxLineSize   = 16 * 1024 * 1024
+)
+
+// Metadata describes a dump, it is only stored in archives.
+type Metadata struct {
+	Collection string    `json:"collection"`
+	Namespaces []string  `json:"namespaces"`
+	Count      int       `json:"count"`
+	ExportedOn time.Time `json:"exported_on"`
+}
+
+// Record is the serialized form of a single content in a dump.
+type Record struct {
+	Key       string    `json:"key"`
+	Rev       string    `json:"rev"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Namespace string    `json:"namespace"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedBy string    `json:"updated_by"`
+	Content   string    `json:"content"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+func NewRecord(cnt *model.ContentDoc) *Record {
+	return &Record{
+		Key:       cnt.Key,
+		Rev:       cnt.Rev,
+		Name:      cnt.Name,
+		Slug:      cnt.Slug,
+		Namespace: cnt.Namespace,
+		CreatedBy: cnt.CreatedBy,
+		UpdatedBy: cnt.UpdatedBy,
+		Content:   cnt.Content,
+		CreatedOn: cnt.CreatedOn,
+		UpdatedOn: cnt.UpdatedOn,
+	}
+}
+
+func (rec *Record) ContentDoc() *model.ContentDoc {
+	return &model.ContentDoc{
+		DocumentMeta: driver.DocumentMeta{
+			Key: rec.Key,
+			Rev: rec.Rev,
+		},
+		Name:      rec.Name,
+		Slug:      rec.Slug,
+		Namespace: rec.Namespace,
+		CreatedBy: rec.CreatedBy,
+		UpdatedBy: rec.UpdatedBy,
+		Content:   rec.Content,
+		CreatedOn: rec.CreatedOn,
+		UpdatedOn: rec.UpdatedOn,
+	}
+}
+
+// Write dumps the contents to the writer in the given format.
+func Write(
+	wrt io.Writer,
+	format string,
+	meta *Metadata,
+	cnts []*model.ContentDoc,
+) error {
+	switch format {
+	case FormatNDJSON:
+		return writeNDJSON(wrt, cnts)
+	case FormatArchive:
+		return writeArchive(wrt, meta, cnts)
+	}
+
+	return fmt.Errorf("unsupported format %s", format)
+}
+
+// Read loads the contents from a dump in the given format. The metadata is
+// empty for ndjson dumps.
+func Read(
+	rdr io.Reader,
+	format string,
+) (*Metadata, []*model.ContentDoc, error) {
+	switch format {
+	case FormatNDJSON:
+		cnts, err := readNDJSON(rdr)
+
+		return &Metadata{}, cnts, err
+	case FormatArchive:
+		return readArchive(rdr)
+	}
+
+	return &Metadata{}, nil, fmt.Errorf("unsupported format %s", format)
+}
+
+func writeNDJSON(wrt io.Writer, cnts []*model.ContentDoc) error {
+	enc := json.NewEncoder(wrt)
+	for _, cnt := range cnts {
+		if err := enc.Encode(NewRecord(cnt)); err != nil {
+			return fmt.Errorf("error in encoding content %s %s", cnt.Slug, err)
+		}
+	}
+
+	return nil
+}
+
+func readNDJSON(rdr io.Reader) ([]*model.ContentDoc, error) {
+	cnts := make([]*model.ContentDoc, 0)
+	scanner := bufio.NewScanner(rdr)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		rec := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			return cnts, fmt.Errorf("error in decoding record %s", err)
+		}
+		cnts = append(cnts, rec.ContentDoc())
+	}
+	if err := scanner.Err(); err != nil {
+		return cnts, fmt.Errorf("error in reading dump %s", err)
+	}
+
+	return cnts, nil
+}
+
+func writeArchive(
+	wrt io.Writer,
+	meta *Metadata,
+	cnts []*model.ContentDoc,
+) error {
+	gzw := gzip.NewWriter(wrt)
+	trw := tar.NewWriter(gzw)
+	meta.Count = len(cnts)
+	mdata, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error in encoding metadata %s", err)
+	}
+	if err := addFile(trw, metadataFile, mdata); err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	if err := writeNDJSON(buf, cnts); err != nil {
+		return err
+	}
+	if err := addFile(trw, contentsFile, buf.Bytes()); err != nil {
+		return err
+	}
+	if err := trw.Close(); err != nil {
+		return fmt.Errorf("error in closing archive %s", err)
+	}
+	if err := gzw.Close(); err != nil {
+		return fmt.Errorf("error in closing compressed stream %s", err)
+	}
+
+	return nil
+}
+
+func readArchive(rdr io.Reader) (*Metadata, []*model.ContentDoc, error) {
+	meta := &Metadata{}
+	var cnts []*model.ContentDoc
+	gzr, err := gzip.NewReader(rdr)
+	if err != nil {
+		return meta, cnts, fmt.Errorf("error in opening compressed stream %s", err)
+	}
+	defer gzr.Close()
+	trr := tar.NewReader(gzr)
+	for {
+		hdr, err := trr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return meta, cnts, fmt.Errorf("error in reading archive %s", err)
+		}
+		switch hdr.Name {
+		case metadataFile:
+			if err := json.NewDecoder(trr).Decode(meta); err != nil {
+				return meta, cnts, fmt.Errorf("error in decoding metadata %s", err)
+			}
+		case contentsFile:
+			cnts, err = readNDJSON(trr)
+			if err != nil {
+				return meta, cnts, err
+			}
+		}
+	}
+	if cnts == nil {
+		return meta, cnts, fmt.Errorf("archive is missing %s", contentsFile)
+	}
+
+	return meta, cnts, nil
+}
+
+func addFile(trw *tar.Writer, name string, data []byte) error {
+	err := trw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    fileMode,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("error in writing header for %s %s", name, err)
+	}
+	if _, err := trw.Write(data); err != nil {
+		return fmt.Errorf("error in writing %s %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testContents() []*model.ContentDoc {
+	created := time.Date(2019, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	return []*model.ContentDoc{
+		{
+			DocumentMeta: driver.DocumentMeta{Key: "14", Rev: "_gHr1-Fe---"},
+			Name:         "catalog",
+			Slug:         "catalog-dsc",
+			Namespace:    "dsc",
+			CreatedBy:    "content@content.org",
+			UpdatedBy:    "packer@packer.com",
+			Content:      `{"paragraph":"paragraph","text":"text"}`,
+			CreatedOn:    created,
+			UpdatedOn:    created.Add(time.Hour),
+		},
+		{
+			DocumentMeta: driver.DocumentMeta{Key: "27", Rev: "_gHr1-Fe--A"},
+			Name:         "about",
+			Slug:         "about-dictybase",
+			Namespace:    "dictybase",
+			CreatedBy:    "content@content.org",
+			UpdatedBy:    "content@content.org",
+			Content:      `{"paragraph":"about","text":"us"}`,
+			CreatedOn:    created,
+			UpdatedOn:    created,
+		},
+	}
+}
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	buf := &bytes.Buffer{}
+	err := Write(buf, FormatNDJSON, &Metadata{}, testContents())
+	assert.NoError(err, "expect no error from writing ndjson dump")
+	assert.Equal(bytes.Count(buf.Bytes(), []byte("\n")), 2, "expect a line per content")
+	_, cnts, err := Read(buf, FormatNDJSON)
+	assert.NoError(err, "expect no error from reading ndjson dump")
+	assert.Equal(cnts, testContents(), "should match the exported contents")
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	buf := &bytes.Buffer{}
+	err := Write(buf, FormatArchive, &Metadata{
+		Collection: "serialized_json",
+		Namespaces: []string{"dsc", "dictybase"},
+		ExportedOn: time.Now(),
+	}, testContents())
+	assert.NoError(err, "expect no error from writing archive dump")
+	meta, cnts, err := Read(buf, FormatArchive)
+	assert.NoError(err, "expect no error from reading archive dump")
+	assert.Equal(meta.Collection, "serialized_json", "collection should match")
+	assert.Equal(meta.Count, 2, "count should match")
+	assert.Equal(
+		meta.Namespaces,
+		[]string{"dsc", "dictybase"},
+		"namespaces should match",
+	)
+	assert.Equal(cnts, testContents(), "should match the exported contents")
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	err := Write(&bytes.Buffer{}, "csv", &Metadata{}, testContents())
+	assert.Error(err, "expect error from unsupported format")
+	_, _, err = Read(&bytes.Buffer{}, "csv")
+	assert.Error(err, "expect error from unsupported format")
+}
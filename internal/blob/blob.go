@@ -0,0 +1,23 @@
+// Package blob keeps the files of the uploaded assets, either in a local
+// directory or in a bucket of an s3 compatible storage. A blob is written
+// once under a key and read back whole, there is no update in place.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned for a key without a blob.
+var ErrNotFound = errors.New("blob not found")
+
+// Store writes, reads and deletes the blobs by their key.
+type Store interface {
+	// Put streams the reader into the blob of the key
+	Put(ctx context.Context, key string, rdr io.Reader) error
+	// Get opens the blob of the key, the caller should close it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob, deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
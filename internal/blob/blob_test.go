@@ -0,0 +1,146 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a stand-in for an s3 bucket, it only accepts the requests
+// signed with the known access key.
+type fakeS3 struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+}
+
+func (fks *fakeS3) ServeHTTP(wrt http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		s3Error(wrt, "IncompleteBody", http.StatusBadRequest)
+
+		return
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") {
+		s3Error(wrt, "InvalidAccessKeyId", http.StatusForbidden)
+
+		return
+	}
+	fks.mutex.Lock()
+	defer fks.mutex.Unlock()
+	switch req.Method {
+	case http.MethodPut:
+		fks.objects[req.URL.Path] = body
+		wrt.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := fks.objects[req.URL.Path]
+		if !ok {
+			s3Error(wrt, "NoSuchKey", http.StatusNotFound)
+
+			return
+		}
+		wrt.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		wrt.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		wrt.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		if req.Method == http.MethodGet {
+			_, _ = wrt.Write(data)
+		}
+	case http.MethodDelete:
+		delete(fks.objects, req.URL.Path)
+		wrt.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func s3Error(wrt http.ResponseWriter, code string, status int) {
+	wrt.Header().Set("Content-Type", "application/xml")
+	wrt.WriteHeader(status)
+	fmt.Fprintf(wrt, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func testStore(assert *require.Assertions, store Store) {
+	ctx := context.Background()
+	err := store.Put(ctx, "a1b2c3", strings.NewReader("strain image"))
+	assert.NoErrorf(err, "expect no error from storing blob %s", err)
+	rdr, err := store.Get(ctx, "a1b2c3")
+	assert.NoErrorf(err, "expect no error from reading blob %s", err)
+	data, err := io.ReadAll(rdr)
+	assert.NoErrorf(err, "expect no error from reading blob %s", err)
+	assert.NoError(rdr.Close(), "expect no error from closing blob")
+	assert.Equal(string(data), "strain image", "should match the blob")
+	err = store.Delete(ctx, "a1b2c3")
+	assert.NoErrorf(err, "expect no error from deleting blob %s", err)
+	_, err = store.Get(ctx, "a1b2c3")
+	assert.True(errors.Is(err, ErrNotFound), "expect missing blob")
+	err = store.Delete(ctx, "a1b2c3")
+	assert.NoErrorf(err, "expect no error from deleting missing blob %s", err)
+}
+
+func TestLocalStore(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoErrorf(err, "expect no error from creating store %s", err)
+	testStore(assert, store)
+	for _, key := range []string{"../../etc/passwd", "ab", "ab/cd"} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"))
+		assert.Errorf(err, "expect error for key %s", key)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	srv := httptest.NewTLSServer(&fakeS3{objects: make(map[string][]byte)})
+	t.Cleanup(srv.Close)
+	params := &S3Params{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		Bucket:    "assets",
+		AccessKey: "access",
+		SecretKey: "secret",
+		Transport: srv.Client().Transport,
+	}
+	store, err := NewS3Store(params)
+	assert.NoErrorf(err, "expect no error from creating store %s", err)
+	testStore(assert, store)
+	bad := *params
+	bad.AccessKey = "wrong"
+	bstore, err := NewS3Store(&bad)
+	assert.NoErrorf(err, "expect no error from creating store %s", err)
+	err = bstore.Put(context.Background(), "a1b2c3", strings.NewReader("x"))
+	assert.Error(err, "expect error for a wrong access key")
+	assert.Contains(err.Error(), "InvalidAccessKeyId", "should report the error")
+	_, err = NewS3Store(&S3Params{Endpoint: srv.URL})
+	assert.Error(err, "expect error for missing params")
+}
+
+func TestS3Transport(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	params := &S3Params{}
+	transport, err := s3Transport(params, true)
+	assert.NoErrorf(err, "expect no error from creating transport %s", err)
+	assert.Equal(
+		transport.(*http.Transport).ResponseHeaderTimeout,
+		DefaultS3Timeout,
+		"expect the default timeout",
+	)
+	params.Timeout = time.Second
+	transport, err = s3Transport(params, false)
+	assert.NoErrorf(err, "expect no error from creating transport %s", err)
+	assert.Equal(
+		transport.(*http.Transport).ResponseHeaderTimeout,
+		time.Second,
+		"expect the given timeout",
+	)
+}
@@ -0,0 +1,120 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// the keys are restricted so that they could not escape the directory.
+var keyReg = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+type localStore struct {
+	dir string
+}
+
+// NewLocalStore keeps the blobs as files in the directory, which is created
+// if missing. The files are spread over sub directories named after the
+// first two characters of the key.
+func NewLocalStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return &localStore{}, fmt.Errorf(
+			"error in creating blob directory %s",
+			err,
+		)
+	}
+
+	return &localStore{dir: dir}, nil
+}
+
+// Put writes to a temporary file that is renamed once complete, a failed
+// write leaves no partial blob behind.
+func (lst *localStore) Put(
+	ctx context.Context,
+	key string,
+	rdr io.Reader,
+) error {
+	path, err := lst.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error in creating blob directory %s", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error in creating blob file %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, &contextReader{ctx: ctx, rdr: rdr})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("error in writing blob %s %s", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error in storing blob %s %s", key, err)
+	}
+
+	return nil
+}
+
+func (lst *localStore) Get(
+	_ context.Context,
+	key string,
+) (io.ReadCloser, error) {
+	path, err := lst.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fh, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error in reading blob %s %w", key, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error in reading blob %s %s", key, err)
+	}
+
+	return fh, nil
+}
+
+func (lst *localStore) Delete(_ context.Context, key string) error {
+	path, err := lst.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error in deleting blob %s %s", key, err)
+	}
+
+	return nil
+}
+
+func (lst *localStore) path(key string) (string, error) {
+	if len(key) < 3 || !keyReg.MatchString(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return filepath.Join(lst.dir, key[:2], key), nil
+}
+
+// contextReader stops reading once the context is done.
+type contextReader struct {
+	ctx context.Context
+	rdr io.Reader
+}
+
+func (crd *contextReader) Read(buf []byte) (int, error) {
+	if err := crd.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return crd.rdr.Read(buf)
+}
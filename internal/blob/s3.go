@@ -0,0 +1,174 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// DefaultS3Timeout is the longest wait for the storage to answer a request.
+const DefaultS3Timeout = 30 * time.Second
+
+// S3Params are the attributes for creating an s3 store.
+type S3Params struct {
+	// Endpoint is the base url of the storage, e.g.
+	// https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// Timeout bounds the wait for the answer of a request, defaults to
+	// DefaultS3Timeout
+	Timeout time.Duration
+	// Transport defaults to the transport of minio with the Timeout, a given
+	// transport is used as is
+	Transport http.RoundTripper
+}
+
+type s3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store keeps the blobs in a bucket of an s3 compatible storage. The
+// objects are addressed by path, so any storage that speaks the s3 api
+// could be used.
+func NewS3Store(params *S3Params) (Store, error) {
+	for name, val := range map[string]string{
+		"endpoint":   params.Endpoint,
+		"region":     params.Region,
+		"bucket":     params.Bucket,
+		"access key": params.AccessKey,
+		"secret key": params.SecretKey,
+	} {
+		if len(val) == 0 {
+			return &s3Store{}, fmt.Errorf("%s of s3 store is required", name)
+		}
+	}
+	endpoint, err := url.Parse(params.Endpoint)
+	if err != nil {
+		return &s3Store{}, fmt.Errorf("error in parsing s3 endpoint %s", err)
+	}
+	secure := endpoint.Scheme == "https"
+	transport, err := s3Transport(params, secure)
+	if err != nil {
+		return &s3Store{}, err
+	}
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds: credentials.NewStaticV4(
+			params.AccessKey,
+			params.SecretKey,
+			"",
+		),
+		Secure:       secure,
+		Region:       params.Region,
+		BucketLookup: minio.BucketLookupPath,
+		Transport:    transport,
+	})
+	if err != nil {
+		return &s3Store{}, fmt.Errorf("error in creating s3 client %s", err)
+	}
+
+	return &s3Store{client: client, bucket: params.Bucket}, nil
+}
+
+func s3Transport(params *S3Params, secure bool) (http.RoundTripper, error) {
+	if params.Transport != nil {
+		return params.Transport, nil
+	}
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = DefaultS3Timeout
+	}
+	transport, err := minio.DefaultTransport(secure)
+	if err != nil {
+		return nil, fmt.Errorf("error in creating s3 transport %s", err)
+	}
+	transport.ResponseHeaderTimeout = timeout
+
+	return transport, nil
+}
+
+// Put spools the reader to a temporary file first, so that the length of
+// the blob is known upfront and it is sent in a single request.
+func (sst *s3Store) Put(ctx context.Context, key string, rdr io.Reader) error {
+	if len(key) == 0 {
+		return fmt.Errorf("blob key is required")
+	}
+	tmp, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return fmt.Errorf("error in creating spool file %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	size, err := io.Copy(tmp, rdr)
+	if err != nil {
+		return fmt.Errorf("error in spooling blob %s %s", key, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error in rewinding spool file %s", err)
+	}
+	_, err = sst.client.PutObject(
+		ctx, sst.bucket, key, tmp, size,
+		minio.PutObjectOptions{ContentType: "application/octet-stream"},
+	)
+	if err != nil {
+		return fmt.Errorf("error in storing blob %s %s", key, err)
+	}
+
+	return nil
+}
+
+// Get checks the object before returning it, so that a missing blob is
+// reported right away rather than on the first read.
+func (sst *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("blob key is required")
+	}
+	obj, err := sst.client.GetObject(
+		ctx, sst.bucket, key,
+		minio.GetObjectOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading blob %s %s", key, err)
+	}
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if isMissing(err) {
+			return nil, fmt.Errorf("error in reading blob %s %w", key, ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("error in reading blob %s %s", key, err)
+	}
+
+	return obj, nil
+}
+
+func (sst *s3Store) Delete(ctx context.Context, key string) error {
+	if len(key) == 0 {
+		return fmt.Errorf("blob key is required")
+	}
+	err := sst.client.RemoveObject(
+		ctx, sst.bucket, key,
+		minio.RemoveObjectOptions{},
+	)
+	if err != nil && !isMissing(err) {
+		return fmt.Errorf("error in deleting blob %s %s", key, err)
+	}
+
+	return nil
+}
+
+func isMissing(err error) bool {
+	res := minio.ToErrorResponse(err)
+
+	return res.StatusCode == http.StatusNotFound || res.Code == "NoSuchKey"
+}
@@ -0,0 +1,268 @@
+// Package cache keeps the recently read contents in memory in front of a
+// content repository. The entries are looked up by slug, evicted in least
+// recently used order beyond the size limit and dropped once their ttl
+// lapses. The writes made through the repository invalidate their entries
+// right away, the writes of the other replicas are learnt from the content
+// events.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+const (
+	// DefaultSize is the number of contents kept in the cache.
+	DefaultSize = 1000
+	// DefaultTTL is how long a content is served from the cache.
+	DefaultTTL = 5 * time.Minute
+)
+
+// Params are the attributes for creating a Repository.
+type Params struct {
+	// Size defaults to DefaultSize
+	Size int
+	// TTL defaults to DefaultTTL
+	TTL time.Duration
+}
+
+// Stats are the counts of the cache since its creation.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+type entry struct {
+	doc     *model.ContentDoc
+	expires time.Time
+}
+
+// Repository is a read-through cache of GetContentBySlug, every other
+// operation is passed on to the wrapped repository.
+type Repository struct {
+	repository.ContentRepository
+	mutex sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List
+	slugs map[string]*list.Element
+	// generation changes with every invalidation, a content read before an
+	// invalidation is not cached after it
+	generation uint64
+	stats      Stats
+}
+
+func NewRepository(
+	repo repository.ContentRepository,
+	params *Params,
+) *Repository {
+	size, ttl := params.Size, params.TTL
+	if size <= 0 {
+		size = DefaultSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Repository{
+		ContentRepository: repo,
+		size:              size,
+		ttl:               ttl,
+		order:             list.New(),
+		slugs:             make(map[string]*list.Element),
+	}
+}
+
+// GetContentBySlug serves the content from the cache, a miss reads it from
+// the repository. A content that is not found is not cached.
+func (crp *Repository) GetContentBySlug(
+	ctx context.Context,
+	slug string,
+) (*model.ContentDoc, error) {
+	doc, generation, ok := crp.lookup(slug)
+	if ok {
+		return doc, nil
+	}
+	doc, err := crp.ContentRepository.GetContentBySlug(ctx, slug)
+	if err != nil || doc.NotFound {
+		return doc, err
+	}
+	crp.store(doc, generation)
+
+	return doc, nil
+}
+
+func (crp *Repository) EditContent(
+	ctx context.Context,
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	defer crp.InvalidateID(cid)
+
+	return crp.ContentRepository.EditContent(ctx, cid, cattr)
+}
+
+func (crp *Repository) DeleteContent(ctx context.Context, cid int64) error {
+	defer crp.InvalidateID(cid)
+
+	return crp.ContentRepository.DeleteContent(ctx, cid)
+}
+
+func (crp *Repository) TransferContents(
+	ctx context.Context,
+	trn *model.ContentTransfer,
+) ([]*model.ContentDoc, error) {
+	docs, err := crp.ContentRepository.TransferContents(ctx, trn)
+	crp.invalidateDocs(docs)
+
+	return docs, err
+}
+
+func (crp *Repository) ImportContent(
+	ctx context.Context,
+	cnt *model.ContentDoc,
+	overwrite bool,
+) (*model.ContentDoc, bool, error) {
+	defer crp.Invalidate(cnt.Slug)
+
+	return crp.ContentRepository.ImportContent(ctx, cnt, overwrite)
+}
+
+func (crp *Repository) BatchContents(
+	ctx context.Context,
+	ops []*model.BatchOperation,
+	atomic bool,
+) ([]*model.BatchResult, error) {
+	res, err := crp.ContentRepository.BatchContents(ctx, ops, atomic)
+	for _, op := range ops {
+		if op.ID > 0 {
+			crp.InvalidateID(op.ID)
+		}
+	}
+	for _, rst := range res {
+		if rst.Content != nil {
+			crp.Invalidate(rst.Content.Slug)
+		}
+	}
+
+	return res, err
+}
+
+// HandleEvent drops the content of an update or delete event, it is meant
+// to be subscribed to the subjects of those events.
+func (crp *Repository) HandleEvent(_ context.Context, cont *content.Content) {
+	crp.Invalidate(cont.GetData().GetAttributes().GetSlug())
+	crp.InvalidateID(cont.GetData().GetId())
+}
+
+// Invalidate drops the content of the slug.
+func (crp *Repository) Invalidate(slug string) {
+	crp.mutex.Lock()
+	defer crp.mutex.Unlock()
+	crp.generation++
+	if elm, ok := crp.slugs[slug]; ok {
+		crp.remove(elm)
+	}
+}
+
+// InvalidateID drops the content of the id, the slug of a content might
+// have changed since it was cached.
+func (crp *Repository) InvalidateID(cid int64) {
+	key := strconv.FormatInt(cid, 10)
+	crp.mutex.Lock()
+	defer crp.mutex.Unlock()
+	crp.generation++
+	for elm := crp.order.Front(); elm != nil; {
+		next := elm.Next()
+		if elm.Value.(*entry).doc.Key == key {
+			crp.remove(elm)
+		}
+		elm = next
+	}
+}
+
+// CacheStats returns the counts of the cache, Stats is left to the
+// repository.
+func (crp *Repository) CacheStats() Stats {
+	crp.mutex.Lock()
+	defer crp.mutex.Unlock()
+	stats := crp.stats
+	stats.Entries = crp.order.Len()
+
+	return stats
+}
+
+// lookup returns a copy of the live content of the slug, along with the
+// generation to store a missed content with.
+func (crp *Repository) lookup(
+	slug string,
+) (*model.ContentDoc, uint64, bool) {
+	crp.mutex.Lock()
+	defer crp.mutex.Unlock()
+	elm, ok := crp.slugs[slug]
+	if !ok {
+		crp.stats.Misses++
+
+		return nil, crp.generation, false
+	}
+	ent := elm.Value.(*entry)
+	if time.Now().After(ent.expires) {
+		crp.remove(elm)
+		crp.stats.Misses++
+
+		return nil, crp.generation, false
+	}
+	crp.order.MoveToFront(elm)
+	crp.stats.Hits++
+	doc := *ent.doc
+
+	return &doc, crp.generation, true
+}
+
+// store caches a copy of the content unless there was an invalidation
+// since it was read, the least recently used content is evicted beyond the
+// size.
+func (crp *Repository) store(doc *model.ContentDoc, generation uint64) {
+	crp.mutex.Lock()
+	defer crp.mutex.Unlock()
+	if generation != crp.generation {
+		return
+	}
+	cpy := *doc
+	ent := &entry{doc: &cpy, expires: time.Now().Add(crp.ttl)}
+	if elm, ok := crp.slugs[doc.Slug]; ok {
+		elm.Value = ent
+		crp.order.MoveToFront(elm)
+
+		return
+	}
+	crp.slugs[doc.Slug] = crp.order.PushFront(ent)
+	if crp.order.Len() > crp.size {
+		crp.remove(crp.order.Back())
+		crp.stats.Evictions++
+	}
+}
+
+func (crp *Repository) invalidateDocs(docs []*model.ContentDoc) {
+	for _, doc := range docs {
+		crp.Invalidate(doc.Slug)
+		if cid, err := strconv.ParseInt(doc.Key, 10, 64); err == nil {
+			crp.InvalidateID(cid)
+		}
+	}
+}
+
+// remove drops the entry, the lock should be held.
+func (crp *Repository) remove(elm *list.Element) {
+	crp.order.Remove(elm)
+	delete(crp.slugs, elm.Value.(*entry).doc.Slug)
+}
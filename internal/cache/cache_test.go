@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRepo counts the reads that reach the repository.
+type countingRepo struct {
+	repository.ContentRepository
+	reads int
+}
+
+func (crp *countingRepo) GetContentBySlug(
+	ctx context.Context,
+	slug string,
+) (*model.ContentDoc, error) {
+	crp.reads++
+
+	return crp.ContentRepository.GetContentBySlug(ctx, slug)
+}
+
+func setup(
+	t *testing.T,
+	params *Params,
+	names ...string,
+) (*Repository, *countingRepo, []*model.ContentDoc) {
+	t.Helper()
+	crp := &countingRepo{ContentRepository: memory.NewContentRepo()}
+	docs := make([]*model.ContentDoc, 0, len(names))
+	for _, name := range names {
+		doc, err := crp.AddContent(
+			context.Background(),
+			testutils.NewStoreContent(name, "dsc"),
+		)
+		require.NoErrorf(t, err, "expect no error from creating content %s", err)
+		docs = append(docs, doc)
+	}
+
+	return NewRepository(crp, params), crp, docs
+}
+
+func TestReadThrough(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo, crp, docs := setup(t, &Params{}, "catalog")
+	for i := 0; i < 3; i++ {
+		doc, err := repo.GetContentBySlug(ctx, docs[0].Slug)
+		assert.NoErrorf(err, "expect no error from reading content %s", err)
+		assert.Equal(doc.Content, docs[0].Content, "should match the content")
+	}
+	assert.Equal(crp.reads, 1, "expect a single read of the repository")
+	stats := repo.CacheStats()
+	assert.Equal(stats.Hits, uint64(2), "expect two hits")
+	assert.Equal(stats.Misses, uint64(1), "expect one miss")
+	assert.Equal(stats.Entries, 1, "expect one entry")
+	doc, err := repo.GetContentBySlug(ctx, "dsc-missing")
+	assert.NoErrorf(err, "expect no error from reading missing content %s", err)
+	assert.True(doc.NotFound, "expect missing content")
+	assert.Equal(repo.CacheStats().Entries, 1, "should not cache missing content")
+}
+
+func TestEviction(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo, crp, docs := setup(t, &Params{Size: 2}, "catalog", "order", "about")
+	for _, doc := range docs[:2] {
+		_, err := repo.GetContentBySlug(ctx, doc.Slug)
+		assert.NoErrorf(err, "expect no error from reading content %s", err)
+	}
+	// touch the first so that the second is the least recently used
+	_, err := repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	_, err = repo.GetContentBySlug(ctx, docs[2].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	stats := repo.CacheStats()
+	assert.Equal(stats.Evictions, uint64(1), "expect one eviction")
+	assert.Equal(stats.Entries, 2, "should keep within the size")
+	reads := crp.reads
+	_, err = repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	assert.Equal(crp.reads, reads, "expect recently used content in cache")
+	_, err = repo.GetContentBySlug(ctx, docs[1].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	assert.Equal(crp.reads, reads+1, "expect evicted content to be read")
+}
+
+func TestExpiry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo, crp, docs := setup(t, &Params{TTL: 20 * time.Millisecond}, "catalog")
+	_, err := repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	time.Sleep(40 * time.Millisecond)
+	_, err = repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	assert.Equal(crp.reads, 2, "expect lapsed content to be read again")
+}
+
+func TestInvalidation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo, crp, docs := setup(t, &Params{}, "catalog", "order")
+	cid, _ := strconv.ParseInt(docs[0].Key, 10, 64)
+	_, err := repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	_, err = repo.EditContent(ctx, cid, &content.ExistingContentAttributes{
+		UpdatedBy: "packer@packer.com",
+		Content:   `{"updated": true}`,
+	})
+	assert.NoErrorf(err, "expect no error from editing content %s", err)
+	doc, err := repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	assert.Equal(doc.Content, `{"updated": true}`, "expect the edited content")
+	assert.Equal(crp.reads, 2, "expect edited content to be read again")
+	// an event of another replica
+	_, err = repo.GetContentBySlug(ctx, docs[1].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	repo.HandleEvent(ctx, &content.Content{
+		Data: &content.ContentData{
+			Attributes: &content.ContentAttributes{Slug: docs[1].Slug},
+		},
+	})
+	assert.Equal(repo.CacheStats().Entries, 1, "expect event to drop the content")
+	err = repo.DeleteContent(ctx, cid)
+	assert.NoErrorf(err, "expect no error from deleting content %s", err)
+	doc, err = repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	assert.True(doc.NotFound, "expect deleted content to be missing")
+}
+
+func TestStaleStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo, _, docs := setup(t, &Params{}, "catalog")
+	_, generation, ok := repo.lookup(docs[0].Slug)
+	assert.False(ok, "expect a miss")
+	repo.Invalidate(docs[0].Slug)
+	repo.store(docs[0], generation)
+	assert.Equal(repo.CacheStats().Entries, 0, "should not cache a stale read")
+	_, err := repo.GetContentBySlug(ctx, docs[0].Slug)
+	assert.NoErrorf(err, "expect no error from reading content %s", err)
+	assert.Equal(repo.CacheStats().Entries, 1, "expect content to be cached")
+}
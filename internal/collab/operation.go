@@ -0,0 +1,275 @@
+// Package collab merges the concurrent edits of the editor json with
+// operational transformation. An operation walks over the whole document
+// retaining, inserting and deleting runs of characters, the lengths are
+// counted in runes. Two operations made on the same revision are
+// transformed against each other so that applying them in either order
+// converges to the same document.
+package collab
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Component is a single step of an operation, only one of the fields is
+// set.
+type Component struct {
+	Retain int
+	Insert string
+	Delete int
+}
+
+func (cmp Component) isRetain() bool {
+	return cmp.Retain > 0
+}
+
+func (cmp Component) isInsert() bool {
+	return len(cmp.Insert) > 0
+}
+
+func (cmp Component) isDelete() bool {
+	return cmp.Delete > 0
+}
+
+// Operation is the list of components along with the length of the document
+// it applies to(BaseLen) and the length of the document it results
+// in(TargetLen).
+type Operation struct {
+	Components []Component
+	BaseLen    int
+	TargetLen  int
+}
+
+// Retain skips over the next n characters.
+func (op *Operation) Retain(n int) *Operation {
+	if n <= 0 {
+		return op
+	}
+	op.BaseLen += n
+	op.TargetLen += n
+	if last := op.last(); last != nil && last.isRetain() {
+		last.Retain += n
+
+		return op
+	}
+	op.Components = append(op.Components, Component{Retain: n})
+
+	return op
+}
+
+// Insert adds the text at the current position, an insert right after a
+// delete is kept before it so that equal operations have the same form.
+func (op *Operation) Insert(text string) *Operation {
+	if len(text) == 0 {
+		return op
+	}
+	op.TargetLen += utf8.RuneCountInString(text)
+	last := op.last()
+	switch {
+	case last != nil && last.isInsert():
+		last.Insert += text
+	case last != nil && last.isDelete():
+		cmps := op.Components
+		if len(cmps) > 1 && cmps[len(cmps)-2].isInsert() {
+			cmps[len(cmps)-2].Insert += text
+
+			break
+		}
+		del := *last
+		op.Components = append(cmps[:len(cmps)-1], Component{Insert: text}, del)
+	default:
+		op.Components = append(op.Components, Component{Insert: text})
+	}
+
+	return op
+}
+
+// Delete removes the next n characters.
+func (op *Operation) Delete(n int) *Operation {
+	if n <= 0 {
+		return op
+	}
+	op.BaseLen += n
+	if last := op.last(); last != nil && last.isDelete() {
+		last.Delete += n
+
+		return op
+	}
+	op.Components = append(op.Components, Component{Delete: n})
+
+	return op
+}
+
+func (op *Operation) last() *Component {
+	if len(op.Components) == 0 {
+		return nil
+	}
+
+	return &op.Components[len(op.Components)-1]
+}
+
+// IsNoop reports whether the operation leaves the document as is.
+func (op *Operation) IsNoop() bool {
+	return len(op.Components) == 0 ||
+		(len(op.Components) == 1 && op.Components[0].isRetain())
+}
+
+// Apply returns the document with the operation applied.
+func (op *Operation) Apply(doc string) (string, error) {
+	runes := []rune(doc)
+	if len(runes) != op.BaseLen {
+		return "", fmt.Errorf(
+			"operation is for length %d, document has %d",
+			op.BaseLen,
+			len(runes),
+		)
+	}
+	out := make([]rune, 0, op.TargetLen)
+	pos := 0
+	for _, cmp := range op.Components {
+		switch {
+		case cmp.isRetain():
+			out = append(out, runes[pos:pos+cmp.Retain]...)
+			pos += cmp.Retain
+		case cmp.isInsert():
+			out = append(out, []rune(cmp.Insert)...)
+		case cmp.isDelete():
+			pos += cmp.Delete
+		}
+	}
+
+	return string(out), nil
+}
+
+// Transform takes two operations made on the same document and returns the
+// pair(first', second') such that second' applies after first and first'
+// applies after second with the same result. At the same position the text
+// inserted by first is kept ahead.
+func Transform(first, second *Operation) (*Operation, *Operation, error) {
+	if first.BaseLen != second.BaseLen {
+		return nil, nil, fmt.Errorf(
+			"operations are for different lengths %d and %d",
+			first.BaseLen,
+			second.BaseLen,
+		)
+	}
+	fprime, sprime := &Operation{}, &Operation{}
+	fcmps, scmps := first.Components, second.Components
+	fcmp, scmp := nextComponent(&fcmps), nextComponent(&scmps)
+	for fcmp != nil || scmp != nil {
+		if fcmp != nil && fcmp.isInsert() {
+			fprime.Insert(fcmp.Insert)
+			sprime.Retain(utf8.RuneCountInString(fcmp.Insert))
+			fcmp = nextComponent(&fcmps)
+
+			continue
+		}
+		if scmp != nil && scmp.isInsert() {
+			fprime.Retain(utf8.RuneCountInString(scmp.Insert))
+			sprime.Insert(scmp.Insert)
+			scmp = nextComponent(&scmps)
+
+			continue
+		}
+		if fcmp == nil || scmp == nil {
+			return nil, nil, fmt.Errorf("operations are of unequal lengths")
+		}
+		size := minInt(fcmp.Retain+fcmp.Delete, scmp.Retain+scmp.Delete)
+		switch {
+		case fcmp.isRetain() && scmp.isRetain():
+			fprime.Retain(size)
+			sprime.Retain(size)
+		case fcmp.isDelete() && scmp.isRetain():
+			fprime.Delete(size)
+		case fcmp.isRetain() && scmp.isDelete():
+			sprime.Delete(size)
+		}
+		// a run deleted by both is left out of either
+		fcmp = shrink(fcmp, size, &fcmps)
+		scmp = shrink(scmp, size, &scmps)
+	}
+
+	return fprime, sprime, nil
+}
+
+// shrink consumes size characters of a retain or delete component, moving
+// on to the next component when it is used up.
+func shrink(cmp *Component, size int, cmps *[]Component) *Component {
+	if cmp.isRetain() {
+		cmp.Retain -= size
+	} else {
+		cmp.Delete -= size
+	}
+	if cmp.Retain+cmp.Delete == 0 {
+		return nextComponent(cmps)
+	}
+
+	return cmp
+}
+
+// nextComponent pops a copy of the first component, nil when none is left.
+func nextComponent(cmps *[]Component) *Component {
+	if len(*cmps) == 0 {
+		return nil
+	}
+	cmp := (*cmps)[0]
+	*cmps = (*cmps)[1:]
+
+	return &cmp
+}
+
+// MarshalJSON encodes the operation in the compact form used by the
+// javascript ot libraries, a positive number retains, a negative number
+// deletes and a string inserts.
+func (op *Operation) MarshalJSON() ([]byte, error) {
+	cmps := make([]interface{}, 0, len(op.Components))
+	for _, cmp := range op.Components {
+		switch {
+		case cmp.isRetain():
+			cmps = append(cmps, cmp.Retain)
+		case cmp.isInsert():
+			cmps = append(cmps, cmp.Insert)
+		case cmp.isDelete():
+			cmps = append(cmps, -cmp.Delete)
+		}
+	}
+
+	return json.Marshal(cmps)
+}
+
+// UnmarshalJSON decodes the compact form of the operation.
+func (op *Operation) UnmarshalJSON(data []byte) error {
+	var cmps []interface{}
+	if err := json.Unmarshal(data, &cmps); err != nil {
+		return fmt.Errorf("error in decoding operation %s", err)
+	}
+	*op = Operation{}
+	for _, cmp := range cmps {
+		switch val := cmp.(type) {
+		case float64:
+			if val != float64(int(val)) || val == 0 {
+				return fmt.Errorf("invalid component %v", val)
+			}
+			if val > 0 {
+				op.Retain(int(val))
+			} else {
+				op.Delete(-int(val))
+			}
+		case string:
+			op.Insert(val)
+		default:
+			return fmt.Errorf("invalid component %v", val)
+		}
+	}
+
+	return nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,112 @@
+package collab
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	op := (&Operation{}).Retain(6).Delete(5).Insert("strain").Retain(7)
+	doc, err := op.Apply("dicty stock center")
+	assert.NoErrorf(err, "expect no error from applying %s", err)
+	assert.Equal(doc, "dicty strain center", "should match edited text")
+	_, err = op.Apply("dicty")
+	assert.Error(err, "expect error for document of another length")
+	uop := (&Operation{}).Retain(2).Insert("ü").Retain(1)
+	doc, err = uop.Apply("中文!")
+	assert.NoErrorf(err, "expect no error from applying %s", err)
+	assert.Equal(doc, "中文ü!", "should count the length in runes")
+}
+
+func TestInsertBeforeDelete(t *testing.T) {
+	t.Parallel()
+	op := (&Operation{}).Delete(2).Insert("ab")
+	require.Equal(
+		t,
+		op.Components,
+		[]Component{{Insert: "ab"}, {Delete: 2}},
+		"should keep the insert ahead of the delete",
+	)
+}
+
+func TestTransformConverges(t *testing.T) {
+	t.Parallel()
+	doc := "order strains online"
+	cases := []struct {
+		name          string
+		first, second *Operation
+		result        string
+	}{
+		{
+			name:   "inserts at different positions",
+			first:  (&Operation{}).Insert("please ").Retain(20),
+			second: (&Operation{}).Retain(20).Insert(" today"),
+			result: "please order strains online today",
+		},
+		{
+			name:   "inserts at the same position",
+			first:  (&Operation{}).Retain(6).Insert("new ").Retain(14),
+			second: (&Operation{}).Retain(6).Insert("mutant ").Retain(14),
+			result: "order new mutant strains online",
+		},
+		{
+			name:   "overlapping deletes",
+			first:  (&Operation{}).Retain(5).Delete(8).Retain(7),
+			second: (&Operation{}).Retain(6).Delete(8).Retain(6),
+			result: "orderonline",
+		},
+		{
+			name:   "insert within a deleted run",
+			first:  (&Operation{}).Retain(6).Delete(8).Retain(6),
+			second: (&Operation{}).Retain(9).Insert("-").Retain(11),
+			result: "order -online",
+		},
+	}
+	for _, tcs := range cases {
+		tcs := tcs
+		t.Run(tcs.name, func(t *testing.T) {
+			t.Parallel()
+			assert := require.New(t)
+			fprime, sprime, err := Transform(tcs.first, tcs.second)
+			assert.NoErrorf(err, "expect no error from transforming %s", err)
+			left, err := tcs.first.Apply(doc)
+			assert.NoError(err, "expect no error from applying first")
+			left, err = sprime.Apply(left)
+			assert.NoError(err, "expect no error from applying second'")
+			right, err := tcs.second.Apply(doc)
+			assert.NoError(err, "expect no error from applying second")
+			right, err = fprime.Apply(right)
+			assert.NoError(err, "expect no error from applying first'")
+			assert.Equal(left, right, "should converge")
+			assert.Equal(left, tcs.result, "should match merged text")
+		})
+	}
+}
+
+func TestTransformMismatch(t *testing.T) {
+	t.Parallel()
+	_, _, err := Transform(
+		(&Operation{}).Retain(3),
+		(&Operation{}).Retain(4),
+	)
+	require.Error(t, err, "expect error for different base lengths")
+}
+
+func TestOperationJSON(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	op := (&Operation{}).Retain(6).Delete(5).Insert("strain").Retain(7)
+	data, err := json.Marshal(op)
+	assert.NoErrorf(err, "expect no error from encoding %s", err)
+	assert.JSONEq(string(data), `[6, "strain", -5, 7]`, "should be compact")
+	dop := &Operation{}
+	err = json.Unmarshal(data, dop)
+	assert.NoErrorf(err, "expect no error from decoding %s", err)
+	assert.Equal(dop, op, "should decode to the same operation")
+	err = json.Unmarshal([]byte(`[1.5]`), dop)
+	assert.Error(err, "expect error for fractional component")
+}
@@ -0,0 +1,371 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInterval is the period between checkpoints of an edited
+	// document.
+	DefaultInterval = 30 * time.Second
+	// buffered updates of a participant, a participant that falls further
+	// behind is dropped from the session.
+	updateBuffer = 64
+	// HistoryLimit is the number of the latest operations kept for
+	// transforming the operations of lagging participants, an operation
+	// made on an older revision is refused.
+	HistoryLimit = 1024
+)
+
+// ErrLeft is returned for a participant that has left or has been dropped
+// from the session.
+var ErrLeft = errors.New("participant is not in the session")
+
+// Update is an operation merged into the document, Revision is the revision
+// of the document that the operation results in.
+type Update struct {
+	ContentID   int64      `json:"content_id"`
+	Participant string     `json:"participant"`
+	Revision    int        `json:"revision"`
+	Operation   *Operation `json:"operation"`
+}
+
+// LoadFunc reads the stored document that a new session starts from.
+type LoadFunc func(ctx context.Context, cid int64) (string, error)
+
+// CheckpointFunc stores the merged document, the editor is the participant
+// behind the last merged operation.
+type CheckpointFunc func(
+	ctx context.Context,
+	cid int64,
+	doc, editor string,
+) error
+
+// HubParams are the attributes for creating a Hub.
+type HubParams struct {
+	Load       LoadFunc
+	Checkpoint CheckpointFunc
+	// Interval defaults to DefaultInterval
+	Interval time.Duration
+}
+
+// Hub keeps an editing session for every content that has participants.
+type Hub struct {
+	mutex    sync.Mutex
+	sessions map[int64]*session
+	// ending has the sessions whose final checkpoint is in progress, a new
+	// session of the content waits for it before loading the document
+	ending     map[int64]chan struct{}
+	load       LoadFunc
+	checkpoint CheckpointFunc
+	interval   time.Duration
+}
+
+func NewHub(params *HubParams) *Hub {
+	interval := params.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &Hub{
+		sessions:   make(map[int64]*session),
+		ending:     make(map[int64]chan struct{}),
+		load:       params.Load,
+		checkpoint: params.Checkpoint,
+		interval:   interval,
+	}
+}
+
+// Join adds a participant to the session of the content, the session is
+// started from the stored document when it is the first participant. The
+// document is loaded without holding the hub, a session started by someone
+// else in the meantime is joined instead.
+func (hub *Hub) Join(
+	ctx context.Context,
+	cid int64,
+	name string,
+) (*Participant, error) {
+	for {
+		hub.mutex.Lock()
+		if ssn, ok := hub.sessions[cid]; ok {
+			ptc := ssn.add(name)
+			hub.mutex.Unlock()
+
+			return ptc, nil
+		}
+		ending, ok := hub.ending[cid]
+		hub.mutex.Unlock()
+		if ok {
+			select {
+			case <-ending:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		doc, err := hub.load(ctx, cid)
+		if err != nil {
+			return nil, fmt.Errorf("error in loading content %d %s", cid, err)
+		}
+		hub.mutex.Lock()
+		_, started := hub.sessions[cid]
+		_, ended := hub.ending[cid]
+		if started || ended {
+			hub.mutex.Unlock()
+
+			continue
+		}
+		ssn := &session{
+			hub:          hub,
+			cid:          cid,
+			doc:          doc,
+			participants: make(map[*Participant]bool),
+			done:         make(chan struct{}),
+		}
+		hub.sessions[cid] = ssn
+		ptc := ssn.add(name)
+		hub.mutex.Unlock()
+		go ssn.run(hub.interval)
+
+		return ptc, nil
+	}
+}
+
+// Active reports whether the content has an editing session, including one
+// whose final checkpoint is in progress.
+func (hub *Hub) Active(cid int64) bool {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	_, started := hub.sessions[cid]
+	_, ended := hub.ending[cid]
+
+	return started || ended
+}
+
+// Sessions returns the number of contents that are being edited.
+func (hub *Hub) Sessions() int {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	return len(hub.sessions)
+}
+
+// Participant is a member of an editing session. Document and Revision are
+// the state of the session at the time of joining, later changes of the
+// others arrive through Updates.
+type Participant struct {
+	Name     string
+	Document string
+	Revision int
+	session  *session
+	updates  chan *Update
+}
+
+// Updates delivers the operations of the other participants, the channel is
+// closed once the participant leaves or is dropped for falling behind.
+func (ptc *Participant) Updates() <-chan *Update {
+	return ptc.updates
+}
+
+// Submit merges an operation made on the given revision of the document. The
+// returned update holds the operation as it was transformed and applied.
+func (ptc *Participant) Submit(revision int, op *Operation) (*Update, error) {
+	return ptc.session.submit(ptc, revision, op)
+}
+
+// Leave removes the participant, the last one to leave ends the session
+// with a final checkpoint. An error of that checkpoint is returned as the
+// merged document is not kept past the session.
+func (ptc *Participant) Leave() error {
+	return ptc.session.leave(ptc)
+}
+
+type session struct {
+	mutex sync.Mutex
+	// saving keeps the checkpoints in order
+	saving sync.Mutex
+	hub    *Hub
+	cid    int64
+	doc    string
+	// history has the latest operations, base is the revision before the
+	// first of them
+	history      []*Operation
+	base         int
+	participants map[*Participant]bool
+	dirty        bool
+	editor       string
+	done         chan struct{}
+}
+
+// add makes a new participant, the lock of the hub should be held.
+func (ssn *session) add(name string) *Participant {
+	ptc := &Participant{
+		Name:    name,
+		session: ssn,
+		updates: make(chan *Update, updateBuffer),
+	}
+	ssn.mutex.Lock()
+	defer ssn.mutex.Unlock()
+	ssn.participants[ptc] = true
+	ptc.Document, ptc.Revision = ssn.doc, ssn.revision()
+
+	return ptc
+}
+
+// revision is the revision of the document, the lock of the session should
+// be held.
+func (ssn *session) revision() int {
+	return ssn.base + len(ssn.history)
+}
+
+func (ssn *session) submit(
+	ptc *Participant,
+	revision int,
+	op *Operation,
+) (*Update, error) {
+	ssn.mutex.Lock()
+	defer ssn.mutex.Unlock()
+	if !ssn.participants[ptc] {
+		return nil, ErrLeft
+	}
+	if revision < ssn.base || revision > ssn.revision() {
+		return nil, fmt.Errorf(
+			"revision %d is not within %d and %d",
+			revision,
+			ssn.base,
+			ssn.revision(),
+		)
+	}
+	for _, concurrent := range ssn.history[revision-ssn.base:] {
+		_, transformed, err := Transform(concurrent, op)
+		if err != nil {
+			return nil, fmt.Errorf("error in transforming operation %s", err)
+		}
+		op = transformed
+	}
+	doc, err := op.Apply(ssn.doc)
+	if err != nil {
+		return nil, fmt.Errorf("error in applying operation %s", err)
+	}
+	ssn.doc = doc
+	ssn.history = append(ssn.history, op)
+	if over := len(ssn.history) - HistoryLimit; over > 0 {
+		ssn.history = append([]*Operation{}, ssn.history[over:]...)
+		ssn.base += over
+	}
+	ssn.dirty, ssn.editor = true, ptc.Name
+	upd := &Update{
+		ContentID:   ssn.cid,
+		Participant: ptc.Name,
+		Revision:    ssn.revision(),
+		Operation:   op,
+	}
+	for other := range ssn.participants {
+		if other == ptc {
+			continue
+		}
+		select {
+		case other.updates <- upd:
+		default:
+			log.Printf(
+				"dropping %s from session of content %d, too far behind",
+				other.Name,
+				ssn.cid,
+			)
+			ssn.remove(other)
+		}
+	}
+
+	return upd, nil
+}
+
+func (ssn *session) leave(ptc *Participant) error {
+	hub := ssn.hub
+	hub.mutex.Lock()
+	ssn.mutex.Lock()
+	if !ssn.participants[ptc] {
+		ssn.mutex.Unlock()
+		hub.mutex.Unlock()
+
+		return ErrLeft
+	}
+	ssn.remove(ptc)
+	last := len(ssn.participants) == 0
+	ssn.mutex.Unlock()
+	if !last {
+		hub.mutex.Unlock()
+
+		return nil
+	}
+	ending := make(chan struct{})
+	delete(hub.sessions, ssn.cid)
+	hub.ending[ssn.cid] = ending
+	hub.mutex.Unlock()
+	close(ssn.done)
+	err := ssn.save()
+	hub.mutex.Lock()
+	delete(hub.ending, ssn.cid)
+	hub.mutex.Unlock()
+	close(ending)
+
+	return err
+}
+
+// remove drops the participant, the lock of the session should be held.
+func (ssn *session) remove(ptc *Participant) {
+	delete(ssn.participants, ptc)
+	close(ptc.updates)
+}
+
+// run checkpoints the document periodically until the session ends.
+func (ssn *session) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ssn.done:
+			return
+		case <-ticker.C:
+			if err := ssn.save(); err != nil {
+				log.Print(err)
+			}
+		}
+	}
+}
+
+// save checkpoints the document if it has changed since the last one, a
+// document that is not valid json midway through the edits is left for the
+// next checkpoint.
+func (ssn *session) save() error {
+	ssn.saving.Lock()
+	defer ssn.saving.Unlock()
+	ssn.mutex.Lock()
+	if !ssn.dirty || !json.Valid([]byte(ssn.doc)) {
+		ssn.mutex.Unlock()
+
+		return nil
+	}
+	doc, editor := ssn.doc, ssn.editor
+	ssn.dirty = false
+	ssn.mutex.Unlock()
+	err := ssn.hub.checkpoint(context.Background(), ssn.cid, doc, editor)
+	if err != nil {
+		ssn.mutex.Lock()
+		ssn.dirty = true
+		ssn.mutex.Unlock()
+
+		return fmt.Errorf(
+			"error in checkpointing content %d %s",
+			ssn.cid,
+			err,
+		)
+	}
+
+	return nil
+}
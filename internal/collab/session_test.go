@@ -0,0 +1,194 @@
+package collab
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore keeps the checkpoints of the documents.
+type memoryStore struct {
+	mutex   sync.Mutex
+	docs    map[int64]string
+	editors map[int64]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		docs:    map[int64]string{10: `{"text": "catalog"}`},
+		editors: make(map[int64]string),
+	}
+}
+
+func (mst *memoryStore) load(_ context.Context, cid int64) (string, error) {
+	mst.mutex.Lock()
+	defer mst.mutex.Unlock()
+	doc, ok := mst.docs[cid]
+	if !ok {
+		return "", errors.New("content not found")
+	}
+
+	return doc, nil
+}
+
+func (mst *memoryStore) checkpoint(
+	_ context.Context,
+	cid int64,
+	doc, editor string,
+) error {
+	mst.mutex.Lock()
+	defer mst.mutex.Unlock()
+	mst.docs[cid] = doc
+	mst.editors[cid] = editor
+
+	return nil
+}
+
+func (mst *memoryStore) document(cid int64) string {
+	mst.mutex.Lock()
+	defer mst.mutex.Unlock()
+
+	return mst.docs[cid]
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	ctx := context.Background()
+	mst := newMemoryStore()
+	hub := NewHub(&HubParams{
+		Load:       mst.load,
+		Checkpoint: mst.checkpoint,
+		Interval:   time.Hour,
+	})
+	_, err := hub.Join(ctx, 11, "curator")
+	assert.Error(err, "expect error for missing content")
+	alice, err := hub.Join(ctx, 10, "curator")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	bob, err := hub.Join(ctx, 10, "editor")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	assert.Equal(bob.Document, `{"text": "catalog"}`, "should start from store")
+	assert.Equal(hub.Sessions(), 1, "expect a single session")
+	// both edit the first revision concurrently
+	upd, err := alice.Submit(0, (&Operation{}).Retain(10).Insert("new ").Retain(9))
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	assert.Equal(upd.Revision, 1, "should be the first revision")
+	_, err = bob.Submit(0, (&Operation{}).Retain(17).Insert(" of strains").Retain(2))
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	bupd := <-bob.Updates()
+	assert.Equal(bupd.Participant, "curator", "should get the edit of others")
+	aupd := <-alice.Updates()
+	assert.Equal(aupd.Revision, 2, "should be the second revision")
+	// replaying the updates on the joined document converges
+	doc, err := bupd.Operation.Apply(bob.Document)
+	assert.NoError(err, "expect no error from applying update")
+	doc, err = aupd.Operation.Apply(doc)
+	assert.NoError(err, "expect no error from applying update")
+	assert.Equal(doc, `{"text": "new catalog of strains"}`, "should merge edits")
+	_, err = bob.Submit(5, (&Operation{}).Retain(3))
+	assert.Error(err, "expect error for unknown revision")
+	assert.NoError(alice.Leave(), "expect no error from leaving")
+	assert.ErrorIs(alice.Leave(), ErrLeft, "expect error for leaving twice")
+	_, ok := <-alice.Updates()
+	assert.False(ok, "expect updates to be closed")
+	assert.Equal(mst.document(10), `{"text": "catalog"}`, "should not save yet")
+	assert.NoError(bob.Leave(), "expect no error from leaving")
+	assert.Equal(mst.document(10), doc, "should checkpoint on the last leave")
+	assert.Equal(mst.editors[10], "editor", "should record the last editor")
+	assert.Equal(hub.Sessions(), 0, "expect the session to end")
+}
+
+func TestSessionCheckpoint(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mst := newMemoryStore()
+	hub := NewHub(&HubParams{
+		Load:       mst.load,
+		Checkpoint: mst.checkpoint,
+		Interval:   20 * time.Millisecond,
+	})
+	ptc, err := hub.Join(context.Background(), 10, "curator")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	defer func() { _ = ptc.Leave() }()
+	// a half typed edit that is not valid json is not saved
+	_, err = ptc.Submit(0, (&Operation{}).Retain(18).Insert(`, "bold`).Retain(1))
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(mst.document(10), `{"text": "catalog"}`, "should skip invalid")
+	_, err = ptc.Submit(1, (&Operation{}).Retain(25).Insert(`": true`).Retain(1))
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	assert.Eventually(func() bool {
+		return mst.document(10) == `{"text": "catalog", "bold": true}`
+	}, time.Second, 10*time.Millisecond, "should checkpoint periodically")
+}
+
+func TestSessionHistory(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mst := newMemoryStore()
+	hub := NewHub(&HubParams{
+		Load:       mst.load,
+		Checkpoint: mst.checkpoint,
+		Interval:   time.Hour,
+	})
+	ptc, err := hub.Join(context.Background(), 10, "curator")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	defer func() { _ = ptc.Leave() }()
+	size := len(ptc.Document)
+	for rev := 0; rev <= HistoryLimit; rev++ {
+		_, err := ptc.Submit(rev, (&Operation{}).Retain(size))
+		assert.NoErrorf(err, "expect no error from submitting %s", err)
+	}
+	_, err = ptc.Submit(0, (&Operation{}).Retain(size))
+	assert.Error(err, "expect error for a revision past the history")
+	_, err = ptc.Submit(1, (&Operation{}).Retain(size))
+	assert.NoErrorf(err, "expect no error for the oldest kept revision %s", err)
+}
+
+func TestSessionSlowCheckpoint(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	ctx := context.Background()
+	mst := newMemoryStore()
+	mst.docs[11] = `{"text": "order"}`
+	release := make(chan struct{})
+	hub := NewHub(&HubParams{
+		Load: mst.load,
+		Checkpoint: func(ctx context.Context, cid int64, doc, editor string) error {
+			<-release
+
+			return mst.checkpoint(ctx, cid, doc, editor)
+		},
+		Interval: time.Hour,
+	})
+	ptc, err := hub.Join(ctx, 10, "curator")
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	_, err = ptc.Submit(0, (&Operation{}).Retain(17).Insert(" list").Retain(2))
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	left := make(chan error)
+	go func() { left <- ptc.Leave() }()
+	assert.Eventually(func() bool {
+		return hub.Sessions() == 0 && hub.Active(10)
+	}, time.Second, time.Millisecond, "expect the final checkpoint to run")
+	other, err := hub.Join(ctx, 11, "editor")
+	assert.NoErrorf(err, "expect joining another content to go on %s", err)
+	defer func() { _ = other.Leave() }()
+	joined := make(chan *Participant)
+	go func() {
+		rejoin, _ := hub.Join(ctx, 10, "editor")
+		joined <- rejoin
+	}()
+	close(release)
+	assert.NoError(<-left, "expect no error from leaving")
+	rejoin := <-joined
+	assert.Equal(
+		rejoin.Document,
+		`{"text": "catalog list"}`,
+		"expect a new session to start from the final checkpoint",
+	)
+	assert.NoError(rejoin.Leave(), "expect no error from leaving")
+}
@@ -0,0 +1,367 @@
+// Package diff compares two versions of the editor json. The structural
+// diff lists the inserted, deleted and changed nodes addressed by their json
+// pointer, a changed text is further broken down into word level edits.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	Inserted = "inserted"
+	Deleted  = "deleted"
+	Changed  = "changed"
+
+	EditEqual  = "equal"
+	EditInsert = "insert"
+	EditDelete = "delete"
+
+	defaultContext = 3
+)
+
+// splits a text into words keeping the whitespace in between as tokens.
+var wordReg = regexp.MustCompile(`\s+|[^\s]+`)
+
+// keys of the editor nodes that hold the child nodes.
+var childKeys = []string{"children", "nodes", "leaves"}
+
+// Change is a single difference between the two versions. The path of an
+// inserted node points into the after version, the others point into the
+// before version.
+type Change struct {
+	Path   string      `json:"path"`
+	Kind   string      `json:"kind"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+	// Text is set for a changed string
+	Text []*TextEdit `json:"text,omitempty"`
+}
+
+// TextEdit is a run of words that is kept, inserted or deleted.
+type TextEdit struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Result is the diff of two versions, Unified is only filled in on request.
+type Result struct {
+	Changes []*Change `json:"changes"`
+	Unified string    `json:"unified,omitempty"`
+}
+
+// Options tunes the diff, Context is the number of unchanged lines around
+// every hunk of the unified diff.
+type Options struct {
+	Unified bool
+	Context int
+}
+
+// Compare returns the structural diff of two editor json documents.
+func Compare(before, after string, opt *Options) (*Result, error) {
+	var bdoc, adoc interface{}
+	if err := json.Unmarshal([]byte(before), &bdoc); err != nil {
+		return nil, fmt.Errorf("error in decoding before version %s", err)
+	}
+	if err := json.Unmarshal([]byte(after), &adoc); err != nil {
+		return nil, fmt.Errorf("error in decoding after version %s", err)
+	}
+	res := &Result{Changes: make([]*Change, 0)}
+	res.Changes = compareValue("", bdoc, adoc, res.Changes)
+	if opt == nil || !opt.Unified {
+		return res, nil
+	}
+	unified, err := Unified(PlainText(bdoc), PlainText(adoc), opt.Context)
+	if err != nil {
+		return nil, err
+	}
+	res.Unified = unified
+
+	return res, nil
+}
+
+// Unified returns the unified diff of two texts, a negative context is
+// replaced by the default of three lines.
+func Unified(before, after string, context int) (string, error) {
+	if context < 0 {
+		context = defaultContext
+	}
+	out, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: "before",
+		ToFile:   "after",
+		Context:  context,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error in creating unified diff %s", err)
+	}
+
+	return out, nil
+}
+
+func compareValue(
+	path string,
+	before, after interface{},
+	changes []*Change,
+) []*Change {
+	switch bval := before.(type) {
+	case map[string]interface{}:
+		if aval, ok := after.(map[string]interface{}); ok {
+			return compareObject(path, bval, aval, changes)
+		}
+	case []interface{}:
+		if aval, ok := after.([]interface{}); ok {
+			return compareArray(path, bval, aval, changes)
+		}
+	case string:
+		if aval, ok := after.(string); ok {
+			if bval == aval {
+				return changes
+			}
+
+			return append(changes, &Change{
+				Path:   path,
+				Kind:   Changed,
+				Before: bval,
+				After:  aval,
+				Text:   Words(bval, aval),
+			})
+		}
+	}
+	if canonical(before) == canonical(after) {
+		return changes
+	}
+
+	return append(changes, &Change{
+		Path:   path,
+		Kind:   Changed,
+		Before: before,
+		After:  after,
+	})
+}
+
+func compareObject(
+	path string,
+	before, after map[string]interface{},
+	changes []*Change,
+) []*Change {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		kpath := path + "/" + escape(key)
+		bval, bok := before[key]
+		aval, aok := after[key]
+		switch {
+		case !aok:
+			changes = append(changes, &Change{
+				Path:   kpath,
+				Kind:   Deleted,
+				Before: bval,
+			})
+		case !bok:
+			changes = append(changes, &Change{
+				Path:  kpath,
+				Kind:  Inserted,
+				After: aval,
+			})
+		default:
+			changes = compareValue(kpath, bval, aval, changes)
+		}
+	}
+
+	return changes
+}
+
+// compareArray aligns the nodes by their longest common subsequence, within
+// a replaced run the nodes are paired by position and compared as changed,
+// the rest are inserted or deleted.
+func compareArray(
+	path string,
+	before, after []interface{},
+	changes []*Change,
+) []*Change {
+	matcher := difflib.NewMatcher(canonicals(before), canonicals(after))
+	for _, opc := range matcher.GetOpCodes() {
+		paired := 0
+		if opc.Tag == 'r' {
+			paired = minInt(opc.I2-opc.I1, opc.J2-opc.J1)
+			for idx := 0; idx < paired; idx++ {
+				changes = compareValue(
+					path+"/"+strconv.Itoa(opc.I1+idx),
+					before[opc.I1+idx],
+					after[opc.J1+idx],
+					changes,
+				)
+			}
+		}
+		if opc.Tag == 'r' || opc.Tag == 'd' {
+			for idx := opc.I1 + paired; idx < opc.I2; idx++ {
+				changes = append(changes, &Change{
+					Path:   path + "/" + strconv.Itoa(idx),
+					Kind:   Deleted,
+					Before: before[idx],
+				})
+			}
+		}
+		if opc.Tag == 'r' || opc.Tag == 'i' {
+			for idx := opc.J1 + paired; idx < opc.J2; idx++ {
+				changes = append(changes, &Change{
+					Path:  path + "/" + strconv.Itoa(idx),
+					Kind:  Inserted,
+					After: after[idx],
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+// Words returns the word level edits that turn the before text into the
+// after text.
+func Words(before, after string) []*TextEdit {
+	btoks := wordReg.FindAllString(before, -1)
+	atoks := wordReg.FindAllString(after, -1)
+	edits := make([]*TextEdit, 0)
+	matcher := difflib.NewMatcher(btoks, atoks)
+	for _, opc := range matcher.GetOpCodes() {
+		switch opc.Tag {
+		case 'e':
+			edits = append(edits, &TextEdit{
+				Op:   EditEqual,
+				Text: strings.Join(btoks[opc.I1:opc.I2], ""),
+			})
+		case 'd':
+			edits = append(edits, &TextEdit{
+				Op:   EditDelete,
+				Text: strings.Join(btoks[opc.I1:opc.I2], ""),
+			})
+		case 'i':
+			edits = append(edits, &TextEdit{
+				Op:   EditInsert,
+				Text: strings.Join(atoks[opc.J1:opc.J2], ""),
+			})
+		case 'r':
+			edits = append(
+				edits,
+				&TextEdit{
+					Op:   EditDelete,
+					Text: strings.Join(btoks[opc.I1:opc.I2], ""),
+				},
+				&TextEdit{
+					Op:   EditInsert,
+					Text: strings.Join(atoks[opc.J1:opc.J2], ""),
+				},
+			)
+		}
+	}
+
+	return edits
+}
+
+// PlainText renders the text of the editor nodes with every block on its
+// own line.
+func PlainText(doc interface{}) string {
+	var bld strings.Builder
+	renderText(&bld, doc)
+
+	return strings.TrimRight(bld.String(), "\n")
+}
+
+func renderText(bld *strings.Builder, node interface{}) {
+	switch val := node.(type) {
+	case []interface{}:
+		renderNodes(bld, val)
+	case map[string]interface{}:
+		if text, ok := val["text"].(string); ok {
+			bld.WriteString(text)
+
+			return
+		}
+		for _, key := range childKeys {
+			if children, ok := val[key].([]interface{}); ok {
+				renderNodes(bld, children)
+
+				return
+			}
+		}
+		// the value of slate editor keeps the nodes under document
+		if doc, ok := val["document"]; ok {
+			renderText(bld, doc)
+		}
+	}
+}
+
+// renderNodes puts every node on its own line unless the nodes are the
+// text runs of a single block.
+func renderNodes(bld *strings.Builder, nodes []interface{}) {
+	inline := hasText(nodes)
+	for _, node := range nodes {
+		renderText(bld, node)
+		if inline || bld.Len() == 0 || strings.HasSuffix(bld.String(), "\n") {
+			continue
+		}
+		bld.WriteString("\n")
+	}
+}
+
+// hasText reports whether any of the nodes is a text run, either a leaf
+// with text or a text node of the older slate editor.
+func hasText(nodes []interface{}) bool {
+	for _, node := range nodes {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := obj["text"].(string); ok || obj["object"] == "text" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func canonicals(nodes []interface{}) []string {
+	vals := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		vals = append(vals, canonical(node))
+	}
+
+	return vals
+}
+
+// canonical encodes the value with sorted keys, so equal values encode the
+// same.
+func canonical(val interface{}) string {
+	data, _ := json.Marshal(val)
+
+	return string(data)
+}
+
+// escape encodes a key as a json pointer(RFC 6901) token.
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
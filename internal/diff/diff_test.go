@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func paragraphs(texts ...string) string {
+	nodes := make([]map[string]interface{}, 0, len(texts))
+	for _, text := range texts {
+		nodes = append(nodes, map[string]interface{}{
+			"type":     "paragraph",
+			"children": []map[string]interface{}{{"text": text}},
+		})
+	}
+	data, _ := json.Marshal(nodes)
+
+	return string(data)
+}
+
+func TestCompareIdentical(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	doc := paragraphs("dicty stock center", "catalog")
+	res, err := Compare(doc, doc, &Options{Unified: true, Context: 3})
+	assert.NoErrorf(err, "expect no error from comparing %s", err)
+	assert.Empty(res.Changes, "expect no change")
+	assert.Empty(res.Unified, "expect no unified diff")
+}
+
+func TestCompareText(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	res, err := Compare(
+		paragraphs("dicty stock center", "catalog"),
+		paragraphs("dicty strain center", "catalog"),
+		nil,
+	)
+	assert.NoErrorf(err, "expect no error from comparing %s", err)
+	assert.Len(res.Changes, 1, "expect a single change")
+	chg := res.Changes[0]
+	assert.Equal(chg.Path, "/0/children/0/text", "should point to the text")
+	assert.Equal(chg.Kind, Changed, "should be a changed text")
+	assert.Equal(
+		chg.Text,
+		[]*TextEdit{
+			{Op: EditEqual, Text: "dicty "},
+			{Op: EditDelete, Text: "stock"},
+			{Op: EditInsert, Text: "strain"},
+			{Op: EditEqual, Text: " center"},
+		},
+		"should match the word edits",
+	)
+}
+
+func TestCompareNodes(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	res, err := Compare(
+		paragraphs("intro", "catalog", "orders"),
+		paragraphs("intro", "orders", "contact", "faq"),
+		nil,
+	)
+	assert.NoErrorf(err, "expect no error from comparing %s", err)
+	kinds := make(map[string]string)
+	for _, chg := range res.Changes {
+		kinds[chg.Path] = chg.Kind
+	}
+	assert.Equal(
+		kinds,
+		map[string]string{
+			"/1": Deleted,
+			"/2": Inserted,
+			"/3": Inserted,
+		},
+		"should match the node changes",
+	)
+}
+
+func TestCompareAttributes(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	res, err := Compare(
+		`{"type": "heading", "level": 1, "align/x": "left"}`,
+		`{"type": "heading", "level": 2, "bold": true}`,
+		nil,
+	)
+	assert.NoErrorf(err, "expect no error from comparing %s", err)
+	assert.Len(res.Changes, 3, "expect three changes")
+	assert.Equal(res.Changes[0].Path, "/align~1x", "should escape the key")
+	assert.Equal(res.Changes[0].Kind, Deleted, "should be deleted")
+	assert.Equal(res.Changes[1].Path, "/bold", "should be sorted by key")
+	assert.Equal(res.Changes[1].Kind, Inserted, "should be inserted")
+	assert.Equal(res.Changes[2].Path, "/level", "should be sorted by key")
+	assert.Equal(res.Changes[2].Kind, Changed, "should be changed")
+	assert.Equal(res.Changes[2].Before, float64(1), "should match before")
+	assert.Equal(res.Changes[2].After, float64(2), "should match after")
+}
+
+func TestCompareInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := Compare(`{"type":`, paragraphs("catalog"), nil)
+	require.Error(t, err, "expect error for invalid json")
+}
+
+func TestPlainText(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	var doc interface{}
+	err := json.Unmarshal([]byte(`[
+		{"type": "paragraph", "children": [
+			{"text": "order "},
+			{"type": "link", "children": [{"text": "strains"}]},
+			{"text": " online"}
+		]},
+		{"type": "list", "children": [
+			{"type": "item", "children": [{"text": "one"}]},
+			{"type": "item", "children": [{"text": "two"}]}
+		]}
+	]`), &doc)
+	assert.NoErrorf(err, "expect no error from decoding %s", err)
+	assert.Equal(
+		PlainText(doc),
+		"order strains online\none\ntwo",
+		"should put every block on its own line",
+	)
+	err = json.Unmarshal([]byte(`{"object": "value", "document": {
+		"object": "document", "nodes": [
+			{"object": "block", "type": "paragraph", "nodes": [
+				{"object": "text", "leaves": [{"object": "leaf", "text": "legacy"}]}
+			]}
+		]}
+	}`), &doc)
+	assert.NoErrorf(err, "expect no error from decoding %s", err)
+	assert.Equal(PlainText(doc), "legacy", "should render older slate value")
+}
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	res, err := Compare(
+		paragraphs("intro", "catalog"),
+		paragraphs("intro", "catalog of strains"),
+		&Options{Unified: true, Context: 3},
+	)
+	assert.NoErrorf(err, "expect no error from comparing %s", err)
+	assert.True(
+		strings.HasPrefix(res.Unified, "--- before\n+++ after\n"),
+		"should have the file headers",
+	)
+	assert.Contains(res.Unified, "-catalog\n", "should remove the old line")
+	assert.Contains(res.Unified, "+catalog of strains\n", "should add the line")
+	assert.Contains(res.Unified, " intro\n", "should keep the context")
+}
@@ -0,0 +1,197 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/watch"
+	"google.golang.org/grpc"
+)
+
+// Client calls the extension service.
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+// CollabStream is the stream of a collaborating client.
+type CollabStream struct {
+	grpc.ClientStream
+}
+
+func (cst *CollabStream) Send(req *CollabRequest) error {
+	return cst.SendMsg(req)
+}
+
+func (cst *CollabStream) Recv() (*CollabReply, error) {
+	rep := &CollabReply{}
+	if err := cst.RecvMsg(rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+// CollaborateContent opens a collaboration stream, the first request joins
+// the session.
+func (clt *Client) CollaborateContent(
+	ctx context.Context,
+) (*CollabStream, error) {
+	stream, err := clt.newStream(ctx, "CollaborateContent")
+	if err != nil {
+		return nil, err
+	}
+
+	return &CollabStream{ClientStream: stream}, nil
+}
+
+// WatchStream is the stream of the watched events.
+type WatchStream struct {
+	grpc.ClientStream
+}
+
+func (wst *WatchStream) Recv() (*watch.Event, error) {
+	evt := &watch.Event{}
+	if err := wst.RecvMsg(evt); err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
+
+// WatchContents starts watching the content events of the request.
+func (clt *Client) WatchContents(
+	ctx context.Context,
+	req *WatchRequest,
+) (*WatchStream, error) {
+	stream, err := clt.newStream(ctx, "WatchContents")
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return &WatchStream{ClientStream: stream}, nil
+}
+
+// CreateNamespace registers a new namespace.
+func (clt *Client) CreateNamespace(
+	ctx context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	rep := &model.NamespaceDoc{}
+
+	if err := clt.invoke(ctx, "CreateNamespace", nsp, rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+// GetNamespace returns the settings of a namespace.
+func (clt *Client) GetNamespace(
+	ctx context.Context,
+	req *NamespaceRequest,
+) (*model.NamespaceDoc, error) {
+	rep := &model.NamespaceDoc{}
+
+	if err := clt.invoke(ctx, "GetNamespace", req, rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+// ListNamespaces returns all the registered namespaces.
+func (clt *Client) ListNamespaces(ctx context.Context) (*NamespaceList, error) {
+	rep := &NamespaceList{}
+
+	if err := clt.invoke(ctx, "ListNamespaces", &struct{}{}, rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+// ArchiveNamespace archives a namespace.
+func (clt *Client) ArchiveNamespace(
+	ctx context.Context,
+	req *NamespaceRequest,
+) (*model.NamespaceDoc, error) {
+	rep := &model.NamespaceDoc{}
+
+	if err := clt.invoke(ctx, "ArchiveNamespace", req, rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+// SeedNamespaces registers the namespaces of the stored contents that are
+// not registered yet.
+func (clt *Client) SeedNamespaces(
+	ctx context.Context,
+	req *SeedRequest,
+) (*NamespaceList, error) {
+	rep := &NamespaceList{}
+
+	if err := clt.invoke(ctx, "SeedNamespaces", req, rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+// ListAuditEntries returns the audit entries selected by the request.
+func (clt *Client) ListAuditEntries(
+	ctx context.Context,
+	req *AuditRequest,
+) (*AuditList, error) {
+	rep := &AuditList{}
+	if err := clt.invoke(ctx, "ListAuditEntries", req, rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+func (clt *Client) invoke(
+	ctx context.Context,
+	name string,
+	req, rep interface{},
+) error {
+	return clt.conn.Invoke(
+		ctx,
+		fmt.Sprintf("/%s/%s", ServiceName, name),
+		req,
+		rep,
+		grpc.CallContentSubtype(Codec),
+	)
+}
+
+func (clt *Client) newStream(
+	ctx context.Context,
+	name string,
+) (grpc.ClientStream, error) {
+	for idx := range ServiceDesc.Streams {
+		desc := &ServiceDesc.Streams[idx]
+		if desc.StreamName == name {
+			return clt.conn.NewStream(
+				ctx,
+				desc,
+				fmt.Sprintf("/%s/%s", ServiceName, name),
+				grpc.CallContentSubtype(Codec),
+			)
+		}
+	}
+
+	return nil, fmt.Errorf("unknown stream %s", name)
+}
@@ -0,0 +1,41 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// Codec is the content subtype of the extension methods, their messages are
+// json encoded as the content api has no protocol buffers for them. A client
+// calls them with grpc.CallContentSubtype(Codec), the content type of the
+// requests is application/grpc+json.
+const Codec = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(val interface{}) ([]byte, error) {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, fmt.Errorf("error in encoding message %s", err)
+	}
+
+	return data, nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, val interface{}) error {
+	if err := json.Unmarshal(data, val); err != nil {
+		return fmt.Errorf("error in decoding message %s", err)
+	}
+
+	return nil
+}
+
+func (jsonCodec) Name() string {
+	return Codec
+}
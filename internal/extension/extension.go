@@ -0,0 +1,358 @@
+// Package extension serves the methods of the content service that the
+// content api has no rpcs for, such as the collaborative editing session,
+// the namespace registry and the audit log, as a separate grpc service on the same
+// server. The messages are json
+// encoded, see Codec.
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/dictyBase/modware-content/internal/collab"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/watch"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName is the full name of the grpc service.
+const ServiceName = "dictybase.content.ContentExtensionService"
+
+// CollabRequest is a message of a collaborating client. The first one
+// joins the session of the content as the participant, every later one
+// submits the operation made on the revision.
+type CollabRequest struct {
+	ContentID   int64             `json:"content_id,omitempty"`
+	Participant string            `json:"participant,omitempty"`
+	Revision    int               `json:"revision"`
+	Operation   *collab.Operation `json:"operation,omitempty"`
+}
+
+// CollabReply is a message to a collaborating client. The first one has
+// the document and its revision at the time of joining, every later one an
+// update, either the submitted operation as it was merged or an operation
+// of another participant.
+type CollabReply struct {
+	ContentID int64          `json:"content_id"`
+	Document  string         `json:"document,omitempty"`
+	Revision  int            `json:"revision"`
+	Update    *collab.Update `json:"update,omitempty"`
+}
+
+// WatchRequest selects the content events of a namespace and or the slugs
+// with a prefix, the retained events after the sequence are delivered
+// first.
+type WatchRequest struct {
+	Namespace  string `json:"namespace,omitempty"`
+	SlugPrefix string `json:"slug_prefix,omitempty"`
+	After      uint64 `json:"after,omitempty"`
+}
+
+// NamespaceRequest names a registered namespace.
+type NamespaceRequest struct {
+	Name string `json:"name"`
+}
+
+// SeedRequest backfills the registry with the namespaces of the stored
+// contents.
+type SeedRequest struct {
+	CreatedBy string `json:"created_by"`
+}
+
+// NamespaceList is a list of namespaces.
+type NamespaceList struct {
+	Namespaces []*model.NamespaceDoc `json:"namespaces"`
+}
+
+// AuditRequest selects the audit entries by actor, namespace and time range,
+// an empty field matches every entry and zero limit returns all of them.
+type AuditRequest struct {
+	Actor     string    `json:"actor,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	From      time.Time `json:"from,omitempty"`
+	To        time.Time `json:"to,omitempty"`
+	Limit     int       `json:"limit,omitempty"`
+}
+
+// AuditList is a list of audit entries, oldest first.
+type AuditList struct {
+	Entries []*model.AuditEntry `json:"entries"`
+}
+
+// Server adapts the content service to the extension service.
+type Server struct {
+	srv *service.ContentService
+}
+
+// Register adds the extension service backed by the content service to the
+// grpc server.
+func Register(grpcS *grpc.Server, srv *service.ContentService) {
+	grpcS.RegisterService(&ServiceDesc, &Server{srv: srv})
+}
+
+// ServiceDesc describes the extension service for the grpc server.
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("CreateNamespace", (*Server).createNamespace),
+		unaryMethod("GetNamespace", (*Server).getNamespace),
+		unaryMethod("ListNamespaces", (*Server).listNamespaces),
+		unaryMethod("ArchiveNamespace", (*Server).archiveNamespace),
+		unaryMethod("SeedNamespaces", (*Server).seedNamespaces),
+		unaryMethod("ListAuditEntries", (*Server).listAuditEntries),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "WatchContents",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(*Server).watchContents(stream)
+			},
+			ServerStreams: true,
+		},
+		{
+			StreamName: "CollaborateContent",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(*Server).collaborate(stream)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "extension",
+}
+
+// unaryMethod describes a unary method whose request is decoded into a new
+// value of the request type, the interceptors of the server are applied.
+func unaryMethod[T any, R any](
+	name string,
+	call func(*Server, context.Context, *T) (R, error),
+) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(
+			srv interface{},
+			ctx context.Context,
+			dec func(interface{}) error,
+			icp grpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			req := new(T)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(*Server), ctx, req.(*T))
+			}
+			if icp == nil {
+				return handler(ctx, req)
+			}
+
+			return icp(ctx, req, &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: fmt.Sprintf("/%s/%s", ServiceName, name),
+			}, handler)
+		},
+	}
+}
+
+func (ext *Server) createNamespace(
+	ctx context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	return ext.srv.CreateNamespace(ctx, nsp)
+}
+
+func (ext *Server) getNamespace(
+	ctx context.Context,
+	req *NamespaceRequest,
+) (*model.NamespaceDoc, error) {
+	return ext.srv.GetNamespace(ctx, req.Name)
+}
+
+func (ext *Server) listNamespaces(
+	ctx context.Context,
+	_ *struct{},
+) (*NamespaceList, error) {
+	nsps, err := ext.srv.ListNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NamespaceList{Namespaces: nsps}, nil
+}
+
+func (ext *Server) archiveNamespace(
+	ctx context.Context,
+	req *NamespaceRequest,
+) (*model.NamespaceDoc, error) {
+	return ext.srv.ArchiveNamespace(ctx, req.Name)
+}
+
+func (ext *Server) seedNamespaces(
+	ctx context.Context,
+	req *SeedRequest,
+) (*NamespaceList, error) {
+	nsps, err := ext.srv.SeedNamespaces(ctx, req.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NamespaceList{Namespaces: nsps}, nil
+}
+
+func (ext *Server) listAuditEntries(
+	ctx context.Context,
+	req *AuditRequest,
+) (*AuditList, error) {
+	ents, err := ext.srv.ListAudit(ctx, &model.AuditFilter{
+		Actor:     req.Actor,
+		Namespace: req.Namespace,
+		From:      req.From,
+		To:        req.To,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditList{Entries: ents}, nil
+}
+
+// collaborate joins the client to the editing session and relays the
+// operations both ways. The participant leaves the session when the stream
+// ends for whatever reason, a client that goes away without a word does not
+// keep the session open.
+func (ext *Server) collaborate(stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	join := &CollabRequest{}
+	if err := stream.RecvMsg(join); err != nil {
+		return err
+	}
+	ptc, err := ext.srv.CollaborateContent(ctx, join.ContentID, join.Participant)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := ptc.Leave(); err != nil && !errors.Is(err, collab.ErrLeft) {
+			log.Printf(
+				"error in leaving session of content %d %s",
+				join.ContentID,
+				err,
+			)
+		}
+	}()
+	err = stream.SendMsg(&CollabReply{
+		ContentID: join.ContentID,
+		Document:  ptc.Document,
+		Revision:  ptc.Revision,
+	})
+	if err != nil {
+		return err
+	}
+	reqs, errc := receiveOperations(ctx, stream)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errc:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		case req := <-reqs:
+			if req.Operation == nil {
+				return status.Error(codes.InvalidArgument, "operation is required")
+			}
+			upd, err := ptc.Submit(req.Revision, req.Operation)
+			if err != nil {
+				return status.Error(codes.FailedPrecondition, err.Error())
+			}
+			err = stream.SendMsg(&CollabReply{
+				ContentID: join.ContentID,
+				Revision:  upd.Revision,
+				Update:    upd,
+			})
+			if err != nil {
+				return err
+			}
+		case upd, ok := <-ptc.Updates():
+			if !ok {
+				return status.Error(
+					codes.Aborted,
+					fmt.Sprintf("%s fell behind the session", ptc.Name),
+				)
+			}
+			err := stream.SendMsg(&CollabReply{
+				ContentID: join.ContentID,
+				Revision:  upd.Revision,
+				Update:    upd,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// receiveOperations reads the requests of the stream until it fails, the
+// error is sent once the reading stops.
+func receiveOperations(
+	ctx context.Context,
+	stream grpc.ServerStream,
+) (<-chan *CollabRequest, <-chan error) {
+	reqs := make(chan *CollabRequest)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			req := &CollabRequest{}
+			if err := stream.RecvMsg(req); err != nil {
+				errc <- err
+
+				return
+			}
+			select {
+			case reqs <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return reqs, errc
+}
+
+// watchContents sends the watched events until the client goes away, a
+// watch dropped for falling behind ends with Aborted and could be resumed
+// from the last sequence received.
+func (ext *Server) watchContents(stream grpc.ServerStream) error {
+	req := &WatchRequest{}
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	sub, err := ext.srv.WatchContents(stream.Context(), &watch.Filter{
+		Namespace:  req.Namespace,
+		SlugPrefix: req.SlugPrefix,
+	}, req.After)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	for evt := range sub.Events() {
+		if err := stream.SendMsg(evt); err != nil {
+			return err
+		}
+	}
+	if err := sub.Err(); err != nil {
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	return stream.Context().Err()
+}
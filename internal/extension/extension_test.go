@@ -0,0 +1,336 @@
+package extension
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/dictyBase/modware-content/internal/collab"
+	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// loopback hands the published contents to the event handlers like the
+// subscription of the messaging server.
+type loopback struct {
+	handlers map[string]message.EventHandler
+}
+
+func (lbk *loopback) Publish(
+	ctx context.Context,
+	subject string,
+	cont *content.Content,
+) error {
+	if hdl, ok := lbk.handlers[subject]; ok {
+		hdl(ctx, cont)
+	}
+
+	return nil
+}
+
+func (lbk *loopback) Close() error {
+	return nil
+}
+
+// setup serves the extension of a content service over memory repositories
+// and returns a client of it along with the content repository.
+func setup(t *testing.T) (
+	*Client,
+	*service.ContentService,
+	repository.ContentRepository,
+) {
+	t.Helper()
+	assert := require.New(t)
+	repo := memory.NewContentRepo()
+	nrepo := memory.NewNamespaceRepo()
+	for _, name := range []string{"dsc", "dfp"} {
+		_, err := nrepo.AddNamespace(context.Background(), &model.NamespaceDoc{
+			Name:        name,
+			DisplayName: name,
+			CreatedBy:   "content@content.org",
+		})
+		assert.NoErrorf(err, "expect no error from adding namespace %s", err)
+	}
+	pub := &loopback{}
+	srv, err := service.NewContentService(&service.Params{
+		Repository: repo,
+		Namespaces: nrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  pub,
+		Group:      "groups",
+		Options: []aphgrpc.Option{
+			aphgrpc.TopicsOption(map[string]string{
+				"contentCreate": "ContentService.Create",
+				"contentDelete": "ContentService.Delete",
+				"contentUpdate": "ContentService.Update",
+			}),
+		},
+		CheckpointInterval: time.Hour,
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	pub.handlers = srv.EventHandlers()
+	grpcS := grpc.NewServer()
+	Register(grpcS, srv)
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		//nolint:errcheck
+		grpcS.Serve(listener)
+	}()
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoErrorf(err, "expect no error from creating client %s", err)
+	t.Cleanup(func() {
+		conn.Close()
+		listener.Close()
+		grpcS.Stop()
+	})
+
+	return NewClient(conn), srv, repo
+}
+
+func joinSession(
+	t *testing.T,
+	ctx context.Context,
+	clt *Client,
+	cid int64,
+	name string,
+) (*CollabStream, *CollabReply) {
+	t.Helper()
+	assert := require.New(t)
+	stream, err := clt.CollaborateContent(ctx)
+	assert.NoErrorf(err, "expect no error from opening stream %s", err)
+	err = stream.Send(&CollabRequest{ContentID: cid, Participant: name})
+	assert.NoErrorf(err, "expect no error from joining %s", err)
+	rep, err := stream.Recv()
+	assert.NoErrorf(err, "expect no error from receiving document %s", err)
+
+	return stream, rep
+}
+
+func TestCollaborateContent(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	clt, srv, repo := setup(t)
+	ctx := context.Background()
+	mcont, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	cid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	curator, joined := joinSession(t, ctx, clt, cid, "curator@content.org")
+	assert.Equal(joined.Document, mcont.Content, "should start from stored")
+	ectx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	editor, _ := joinSession(t, ectx, clt, cid, "editor@content.org")
+	size := len([]rune(mcont.Content))
+	err = curator.Send(&CollabRequest{
+		Revision: joined.Revision,
+		Operation: (&collab.Operation{}).
+			Retain(size - 1).
+			Insert(`, "bold": true`).
+			Retain(1),
+	})
+	assert.NoErrorf(err, "expect no error from submitting %s", err)
+	ack, err := curator.Recv()
+	assert.NoErrorf(err, "expect no error from receiving ack %s", err)
+	assert.Equal(ack.Update.Participant, "curator@content.org", "expect own op")
+	upd, err := editor.Recv()
+	assert.NoErrorf(err, "expect no error from receiving update %s", err)
+	assert.Equal(upd.Revision, ack.Revision, "should match the revision")
+	doc, err := upd.Update.Operation.Apply(mcont.Content)
+	assert.NoErrorf(err, "expect no error from applying update %s", err)
+	direct := &content.UpdateContentRequest{
+		Id: cid,
+		Data: &content.UpdateContentRequest_Data{
+			Attributes: &content.ExistingContentAttributes{
+				UpdatedBy: "admin@content.org",
+				Content:   `{"text": "direct"}`,
+			},
+		},
+	}
+	_, err = srv.UpdateContent(ctx, direct)
+	assert.Error(err, "expect update to be refused during a session")
+	// the editor goes away without leaving, the curator closes its stream
+	cancel()
+	assert.NoError(curator.CloseSend(), "expect no error from closing")
+	assert.Eventually(func() bool {
+		uct, err := repo.GetContent(ctx, cid)
+
+		return err == nil && uct.Content == doc
+	}, 5*time.Second, 10*time.Millisecond, "should end the session")
+	_, err = srv.UpdateContent(ctx, direct)
+	assert.NoErrorf(err, "expect no error from updating after session %s", err)
+}
+
+func TestWatchContents(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	clt, srv, _ := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := func(name, namespace string) {
+		_, err := srv.StoreContent(ctx, &content.StoreContentRequest{
+			Data: &content.StoreContentRequest_Data{
+				Attributes: testutils.NewStoreContent(name, namespace),
+			},
+		})
+		assert.NoErrorf(err, "expect no error from storing content %s", err)
+	}
+	store("catalog", "dfp")
+	// resuming after the first event delivers the second one whether it is
+	// dispatched before or after the watch starts
+	stream, err := clt.WatchContents(ctx, &WatchRequest{
+		Namespace: "dsc",
+		After:     1,
+	})
+	assert.NoErrorf(err, "expect no error from watching %s", err)
+	store("order", "dfp")
+	store("catalog", "dsc")
+	evt, err := stream.Recv()
+	assert.NoErrorf(err, "expect no error from receiving event %s", err)
+	assert.Equal(uint64(3), evt.Sequence, "expect the event of dsc")
+	assert.Equal("create", evt.Action, "expect a create event")
+	assert.Equal(
+		"catalog-dsc",
+		evt.Content.Data.Attributes.Slug,
+		"expect the stored content",
+	)
+	stream, err = clt.WatchContents(ctx, &WatchRequest{After: 10})
+	assert.NoErrorf(err, "expect no error from opening the stream %s", err)
+	_, err = stream.Recv()
+	assert.Equal(
+		codes.InvalidArgument,
+		status.Code(err),
+		"expect a sequence beyond the latest to be refused",
+	)
+}
+
+func TestNamespaces(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	clt, srv, repo := setup(t)
+	ctx := context.Background()
+	_, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "legacy"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	seeded, err := clt.SeedNamespaces(ctx, &SeedRequest{
+		CreatedBy: "admin@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from seeding namespaces %s", err)
+	assert.Len(seeded.Namespaces, 1, "expect the unregistered namespace only")
+	assert.Equal(seeded.Namespaces[0].Name, "legacy", "should match the name")
+	nsp, err := clt.CreateNamespace(ctx, &model.NamespaceDoc{
+		Name:          "pages",
+		DisplayName:   "Pages",
+		ContentSchema: `{"type": "object", "required": ["text"]}`,
+		CreatedBy:     "admin@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	assert.Equal(nsp.DisplayName, "Pages", "should match the display name")
+	_, err = clt.CreateNamespace(ctx, nsp)
+	assert.Equal(
+		codes.AlreadyExists,
+		status.Code(err),
+		"expect a namespace to be registered once",
+	)
+	_, err = clt.CreateNamespace(ctx, &model.NamespaceDoc{
+		Name:          "broken",
+		DisplayName:   "Broken",
+		ContentSchema: `{"type": 12}`,
+		CreatedBy:     "admin@content.org",
+	})
+	assert.Equal(
+		codes.InvalidArgument,
+		status.Code(err),
+		"expect an invalid schema to be refused",
+	)
+	nsps, err := clt.ListNamespaces(ctx)
+	assert.NoErrorf(err, "expect no error from listing namespaces %s", err)
+	assert.Len(nsps.Namespaces, 4, "expect the seeded and created namespaces")
+	cattr := testutils.NewStoreContent("about", "pages")
+	cattr.Content = `{"paragraph": "no text"}`
+	_, err = srv.StoreContent(ctx, &content.StoreContentRequest{
+		Data: &content.StoreContentRequest_Data{Attributes: cattr},
+	})
+	assert.Equal(
+		codes.InvalidArgument,
+		status.Code(err),
+		"expect a content that misses the schema to be refused",
+	)
+	ansp, err := clt.ArchiveNamespace(ctx, &NamespaceRequest{Name: "pages"})
+	assert.NoErrorf(err, "expect no error from archiving namespace %s", err)
+	assert.True(ansp.Archived, "expect an archived namespace")
+	gnsp, err := clt.GetNamespace(ctx, &NamespaceRequest{Name: "pages"})
+	assert.NoErrorf(err, "expect no error from getting namespace %s", err)
+	assert.True(gnsp.Archived, "expect the namespace to stay archived")
+	_, err = clt.GetNamespace(ctx, &NamespaceRequest{Name: "missing"})
+	assert.Equal(codes.NotFound, status.Code(err), "expect missing namespace")
+}
+
+func TestListAuditEntries(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	clt, srv, _ := setup(t)
+	ctx := context.Background()
+	_, err := srv.StoreContent(ctx, &content.StoreContentRequest{
+		Data: &content.StoreContentRequest_Data{
+			Attributes: testutils.NewStoreContent("order", "dsc"),
+		},
+	})
+	assert.NoErrorf(err, "expect no error from storing content %s", err)
+	_, err = srv.TransferContents(ctx, &model.ContentTransfer{
+		From:            "dsc",
+		To:              "dfp",
+		Slugs:           []string{"order-dsc"},
+		SlugPattern:     "-dsc$",
+		SlugReplacement: "-dfp",
+		Move:            true,
+		UpdatedBy:       "packer@packer.com",
+	})
+	assert.NoErrorf(err, "expect no error from moving content %s", err)
+	ents, err := clt.ListAuditEntries(ctx, &AuditRequest{Namespace: "dsc"})
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents.Entries, 2, "expect the store and the move out of dsc")
+	mvd := ents.Entries[1]
+	assert.Equal(mvd.Operation, model.AuditRename, "should audit a rename")
+	assert.Equal(mvd.Actor, "packer@packer.com", "should match updated_by")
+	assert.Equal(mvd.FromSlug, "order-dsc", "should keep the old slug")
+	assert.Equal(mvd.FromNamespace, "dsc", "should keep the old namespace")
+	assert.Equal(mvd.Slug, "order-dfp", "should match the new slug")
+	assert.Equal(mvd.Namespace, "dfp", "should match the new namespace")
+	assert.Equal(mvd.BeforeHash, mvd.AfterHash, "should keep the body")
+	ents, err = clt.ListAuditEntries(ctx, &AuditRequest{
+		Actor:     "packer@packer.com",
+		Namespace: "dfp",
+	})
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents.Entries, 1, "expect the move into dfp")
+	now := time.Now()
+	_, err = clt.ListAuditEntries(ctx, &AuditRequest{
+		From: now,
+		To:   now.Add(-time.Hour),
+	})
+	assert.Equal(
+		codes.InvalidArgument,
+		status.Code(err),
+		"expect an inverted time range to be refused",
+	)
+}
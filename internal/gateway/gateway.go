@@ -0,0 +1,36 @@
+// Package gateway has the options for serving the content api through the
+// grpc http gateway.
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// ErrorHandler answers the not modified status of a conditional request
+// with 304 and the entity tag of the content, any other error is left to
+// the default handler of the gateway. It is meant to be given to the mux
+// with runtime.WithErrorHandler.
+func ErrorHandler(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	mrs runtime.Marshaler,
+	wrt http.ResponseWriter,
+	req *http.Request,
+	err error,
+) {
+	if !service.IsNotModified(err) {
+		runtime.DefaultHTTPErrorHandler(ctx, mux, mrs, wrt, req, err)
+
+		return
+	}
+	if smd, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		for _, etag := range smd.HeaderMD.Get(service.ETagKey) {
+			wrt.Header().Add("ETag", etag)
+		}
+	}
+	wrt.WriteHeader(http.StatusNotModified)
+}
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	ctx := runtime.NewServerMetadataContext(
+		context.Background(),
+		runtime.ServerMetadata{HeaderMD: metadata.Pairs("etag", `"4f2a"`)},
+	)
+	mux := runtime.NewServeMux(runtime.WithErrorHandler(ErrorHandler))
+	req := httptest.NewRequest(http.MethodGet, "/contents/1", nil)
+	sts, err := status.New(
+		codes.FailedPrecondition,
+		"content is not modified",
+	).WithDetails(&errdetails.ErrorInfo{
+		Reason: service.NotModifiedReason,
+		Domain: service.ErrorDomain,
+	})
+	assert.NoErrorf(err, "expect no error from adding details %s", err)
+	rec := httptest.NewRecorder()
+	ErrorHandler(ctx, mux, &runtime.JSONPb{}, rec, req, sts.Err())
+	assert.Equal(rec.Code, http.StatusNotModified, "expect 304")
+	assert.Equal(rec.Header().Get("ETag"), `"4f2a"`, "expect the etag")
+	assert.Empty(rec.Body.Bytes(), "expect no body")
+	rec = httptest.NewRecorder()
+	ErrorHandler(
+		ctx, mux, &runtime.JSONPb{}, rec, req,
+		status.Error(codes.FailedPrecondition, "content 1 is locked"),
+	)
+	assert.Equal(
+		rec.Code,
+		http.StatusBadRequest,
+		"expect other errors to be left to the default handler",
+	)
+}
@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dictyBase/modware-content/internal/watch"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/status"
+)
+
+// Watcher opens the watches of the content events, the content service is
+// one.
+type Watcher interface {
+	WatchContents(
+		ctx context.Context,
+		flt *watch.Filter,
+		after uint64,
+	) (*watch.Subscription, error)
+}
+
+// WatchHandler streams the content events as server sent events, so that a
+// browser could watch them with an EventSource. The events are filtered by
+// the namespace and slug_prefix query parameters. The id of an event is
+// its sequence, the watch resumes after the Last-Event-ID header of a
+// reconnecting EventSource or else the after query parameter.
+func WatchHandler(wtc Watcher) http.Handler {
+	return http.HandlerFunc(func(wrt http.ResponseWriter, req *http.Request) {
+		flusher, ok := wrt.(http.Flusher)
+		if !ok {
+			http.Error(wrt, "streaming is not supported", http.StatusInternalServerError)
+
+			return
+		}
+		after, err := watchSequence(req)
+		if err != nil {
+			http.Error(wrt, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+		query := req.URL.Query()
+		sub, err := wtc.WatchContents(req.Context(), &watch.Filter{
+			Namespace:  query.Get("namespace"),
+			SlugPrefix: query.Get("slug_prefix"),
+		}, after)
+		if err != nil {
+			http.Error(
+				wrt,
+				status.Convert(err).Message(),
+				runtime.HTTPStatusFromCode(status.Code(err)),
+			)
+
+			return
+		}
+		defer sub.Close()
+		wrt.Header().Set("Content-Type", "text/event-stream")
+		wrt.Header().Set("Cache-Control", "no-cache")
+		wrt.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		for evt := range sub.Events() {
+			if err := writeEvent(wrt, evt); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+		if err := sub.Err(); err != nil {
+			fmt.Fprintf(wrt, "event: error\ndata: %s\n\n", err)
+			flusher.Flush()
+		}
+	})
+}
+
+func watchSequence(req *http.Request) (uint64, error) {
+	val := req.Header.Get("Last-Event-ID")
+	if len(val) == 0 {
+		val = req.URL.Query().Get("after")
+	}
+	if len(val) == 0 {
+		return 0, nil
+	}
+	after, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error in parsing sequence %s", err)
+	}
+
+	return after, nil
+}
+
+func writeEvent(wrt http.ResponseWriter, evt *watch.Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("error in encoding event %s", err)
+	}
+	_, err = fmt.Fprintf(
+		wrt,
+		"id: %d\nevent: %s\ndata: %s\n\n",
+		evt.Sequence,
+		evt.Action,
+		data,
+	)
+
+	return err
+}
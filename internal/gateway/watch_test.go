@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dictyBase/aphgrpc"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/app/service"
+	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// loopback hands the published contents to the event handlers like the
+// subscription of the messaging server.
+type loopback struct {
+	handlers map[string]message.EventHandler
+}
+
+func (lbk *loopback) Publish(
+	ctx context.Context,
+	subject string,
+	cont *content.Content,
+) error {
+	if hdl, ok := lbk.handlers[subject]; ok {
+		hdl(ctx, cont)
+	}
+
+	return nil
+}
+
+func (lbk *loopback) Close() error {
+	return nil
+}
+
+// readEvent reads the fields of the next server sent event.
+func readEvent(t *testing.T, rdr *bufio.Reader) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	for {
+		line, err := rdr.ReadString('\n')
+		require.NoErrorf(t, err, "expect no error from reading event %s", err)
+		line = strings.TrimSuffix(line, "\n")
+		if len(line) == 0 {
+			return fields
+		}
+		key, val, _ := strings.Cut(line, ": ")
+		fields[key] = val
+	}
+}
+
+func TestWatchHandler(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	nrepo := memory.NewNamespaceRepo()
+	for _, name := range []string{"dsc", "dfp"} {
+		_, err := nrepo.AddNamespace(ctx, &model.NamespaceDoc{
+			Name:        name,
+			DisplayName: name,
+			CreatedBy:   "content@content.org",
+		})
+		assert.NoErrorf(err, "expect no error from adding namespace %s", err)
+	}
+	pub := &loopback{}
+	srv, err := service.NewContentService(&service.Params{
+		Repository: memory.NewContentRepo(),
+		Namespaces: nrepo,
+		Audit:      memory.NewAuditRepo(),
+		Locks:      memory.NewLockRepo(),
+		Publisher:  pub,
+		Group:      "groups",
+		Options: []aphgrpc.Option{
+			aphgrpc.TopicsOption(map[string]string{
+				"contentCreate": "ContentService.Create",
+				"contentDelete": "ContentService.Delete",
+				"contentUpdate": "ContentService.Update",
+			}),
+		},
+	})
+	assert.NoErrorf(err, "expect no error from creating service %s", err)
+	pub.handlers = srv.EventHandlers()
+	hsrv := httptest.NewServer(WatchHandler(srv))
+	defer hsrv.Close()
+	watchURL := func(query string, lastID string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, hsrv.URL+"/watch"+query, nil)
+		assert.NoErrorf(err, "expect no error from creating request %s", err)
+		if len(lastID) > 0 {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		resp, err := hsrv.Client().Do(req)
+		assert.NoErrorf(err, "expect no error from watching %s", err)
+
+		return resp
+	}
+	resp := watchURL("?namespace=dsc", "")
+	assert.Equal(http.StatusOK, resp.StatusCode, "expect the stream")
+	assert.Equal(
+		"text/event-stream",
+		resp.Header.Get("Content-Type"),
+		"expect server sent events",
+	)
+	for _, nsp := range []string{"dfp", "dsc"} {
+		_, err := srv.StoreContent(ctx, &content.StoreContentRequest{
+			Data: &content.StoreContentRequest_Data{
+				Attributes: testutils.NewStoreContent("catalog", nsp),
+			},
+		})
+		assert.NoErrorf(err, "expect no error from storing content %s", err)
+	}
+	evt := readEvent(t, bufio.NewReader(resp.Body))
+	resp.Body.Close()
+	assert.Equal("2", evt["id"], "expect the sequence as id")
+	assert.Equal("create", evt["event"], "expect the action as event")
+	assert.Contains(evt["data"], `"slug":"catalog-dsc"`, "expect the content")
+	resp = watchURL("", "1")
+	evt = readEvent(t, bufio.NewReader(resp.Body))
+	resp.Body.Close()
+	assert.Equal("2", evt["id"], "expect to resume after the last event id")
+	for _, query := range []string{"?after=x", "?after=10"} {
+		resp = watchURL(query, "")
+		resp.Body.Close()
+		assert.Equal(
+			http.StatusBadRequest,
+			resp.StatusCode,
+			"expect bad request for sequence %s",
+			query,
+		)
+	}
+}
@@ -0,0 +1,368 @@
+// Package linkcheck finds the broken external links of the contents. The
+// urls are checked by a bounded number of workers, the requests to a single
+// host are spaced apart and the outcome of a url is reused until it
+// expires, so repeated runs over the same contents stay cheap.
+package linkcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+)
+
+const (
+	// DefaultConcurrency is the number of urls checked at the same time.
+	DefaultConcurrency = 8
+	// DefaultHostInterval is the least time between two requests to a
+	// host.
+	DefaultHostInterval = time.Second
+	// DefaultTimeout bounds the check of a single url.
+	DefaultTimeout = 15 * time.Second
+	// DefaultCacheTTL is how long the outcome of a url is reused.
+	DefaultCacheTTL = 24 * time.Hour
+	userAgent       = "modware-content-linkcheck"
+)
+
+// Params configure the checker, the zero values take the defaults and a
+// negative HostInterval turns off the spacing of the requests. The client
+// defaults to one with the timeout.
+type Params struct {
+	Concurrency  int
+	HostInterval time.Duration
+	Timeout      time.Duration
+	CacheTTL     time.Duration
+	Client       *http.Client
+}
+
+// Result is the outcome of checking a url, the status is zero when no
+// response was received.
+type Result struct {
+	URL       string    `json:"url"`
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Broken    bool      `json:"broken"`
+	CheckedOn time.Time `json:"checked_on"`
+}
+
+// ContentReport lists the broken links of a content.
+type ContentReport struct {
+	Namespace string    `json:"namespace"`
+	Slug      string    `json:"slug"`
+	ContentID int64     `json:"content_id"`
+	Links     []*Result `json:"links"`
+}
+
+// Report is the outcome of checking the links of a set of contents, only
+// the contents with broken links are listed.
+type Report struct {
+	Contents  []*ContentReport `json:"contents"`
+	Checked   int              `json:"checked"`
+	Broken    int              `json:"broken"`
+	CreatedOn time.Time        `json:"created_on"`
+}
+
+// Checker checks urls, it is safe for concurrent use.
+type Checker struct {
+	client      *http.Client
+	concurrency int
+	timeout     time.Duration
+	ttl         time.Duration
+	limiter     *hostLimiter
+	mutex       sync.Mutex
+	cache       map[string]*Result
+}
+
+func NewChecker(params *Params) *Checker {
+	chk := &Checker{
+		client:      params.Client,
+		concurrency: params.Concurrency,
+		timeout:     params.Timeout,
+		ttl:         params.CacheTTL,
+		cache:       make(map[string]*Result),
+	}
+	if chk.concurrency <= 0 {
+		chk.concurrency = DefaultConcurrency
+	}
+	if chk.timeout <= 0 {
+		chk.timeout = DefaultTimeout
+	}
+	if chk.ttl <= 0 {
+		chk.ttl = DefaultCacheTTL
+	}
+	if chk.client == nil {
+		chk.client = &http.Client{Timeout: chk.timeout}
+	}
+	interval := params.HostInterval
+	if interval == 0 {
+		interval = DefaultHostInterval
+	}
+	chk.limiter = newHostLimiter(interval)
+
+	return chk
+}
+
+// CheckContents checks the external urls of the contents and reports the
+// broken ones per namespace and slug.
+func (chk *Checker) CheckContents(
+	ctx context.Context,
+	cnts []*model.ContentDoc,
+) (*Report, error) {
+	urls := make([]string, 0)
+	for _, cnt := range cnts {
+		urls = append(urls, links.ExternalURLs(cnt.Content)...)
+	}
+	results, err := chk.Check(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		Contents:  make([]*ContentReport, 0),
+		Checked:   len(results),
+		CreatedOn: time.Now().UTC(),
+	}
+	for _, res := range results {
+		if res.Broken {
+			report.Broken++
+		}
+	}
+	for _, cnt := range cnts {
+		crp := &ContentReport{Namespace: cnt.Namespace, Slug: cnt.Slug}
+		crp.ContentID, _ = strconv.ParseInt(cnt.Key, 10, 64)
+		for _, addr := range links.ExternalURLs(cnt.Content) {
+			if res := results[addr]; res.Broken {
+				crp.Links = append(crp.Links, res)
+			}
+		}
+		if len(crp.Links) > 0 {
+			report.Contents = append(report.Contents, crp)
+		}
+	}
+	sort.Slice(report.Contents, func(i, j int) bool {
+		if report.Contents[i].Namespace != report.Contents[j].Namespace {
+			return report.Contents[i].Namespace < report.Contents[j].Namespace
+		}
+
+		return report.Contents[i].Slug < report.Contents[j].Slug
+	})
+
+	return report, nil
+}
+
+// Check checks the urls, every url once, and returns the results keyed by
+// url. It fails only when the context is done.
+func (chk *Checker) Check(
+	ctx context.Context,
+	urls []string,
+) (map[string]*Result, error) {
+	chk.prune()
+	results := make(map[string]*Result)
+	pending := make([]string, 0)
+	for _, addr := range urls {
+		if _, ok := results[addr]; ok {
+			continue
+		}
+		if res, ok := chk.cached(addr); ok {
+			results[addr] = res
+
+			continue
+		}
+		results[addr] = nil
+		pending = append(pending, addr)
+	}
+	jobs := make(chan string)
+	var mutex sync.Mutex
+	var wgr sync.WaitGroup
+	for i := 0; i < chk.concurrency && i < len(pending); i++ {
+		wgr.Add(1)
+		go func() {
+			defer wgr.Done()
+			for addr := range jobs {
+				res := chk.checkURL(ctx, addr)
+				mutex.Lock()
+				results[addr] = res
+				mutex.Unlock()
+			}
+		}()
+	}
+	for _, addr := range pending {
+		select {
+		case jobs <- addr:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wgr.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error in checking links %s", err)
+	}
+
+	return results, nil
+}
+
+func (chk *Checker) cached(addr string) (*Result, bool) {
+	chk.mutex.Lock()
+	defer chk.mutex.Unlock()
+	res, ok := chk.cache[addr]
+	if !ok || chk.expired(res) {
+		return nil, false
+	}
+
+	return res, true
+}
+
+func (chk *Checker) expired(res *Result) bool {
+	return time.Since(res.CheckedOn) > chk.ttl
+}
+
+// prune drops the expired results, so the cache holds no more than the
+// urls checked within the ttl.
+func (chk *Checker) prune() {
+	chk.mutex.Lock()
+	defer chk.mutex.Unlock()
+	for addr, res := range chk.cache {
+		if chk.expired(res) {
+			delete(chk.cache, addr)
+		}
+	}
+}
+
+// ReadCache adds the results written by WriteCache to the cache, the
+// expired ones are skipped. It lets the outcomes outlive a single run.
+func (chk *Checker) ReadCache(rdr io.Reader) error {
+	var results []*Result
+	if err := json.NewDecoder(rdr).Decode(&results); err != nil {
+		return fmt.Errorf("error in decoding link cache %s", err)
+	}
+	chk.mutex.Lock()
+	defer chk.mutex.Unlock()
+	for _, res := range results {
+		if !chk.expired(res) {
+			chk.cache[res.URL] = res
+		}
+	}
+
+	return nil
+}
+
+// WriteCache writes the results that have not expired ordered by url.
+func (chk *Checker) WriteCache(wrt io.Writer) error {
+	chk.prune()
+	chk.mutex.Lock()
+	results := make([]*Result, 0, len(chk.cache))
+	for _, res := range chk.cache {
+		results = append(results, res)
+	}
+	chk.mutex.Unlock()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].URL < results[j].URL
+	})
+	if err := json.NewEncoder(wrt).Encode(results); err != nil {
+		return fmt.Errorf("error in encoding link cache %s", err)
+	}
+
+	return nil
+}
+
+// checkURL requests the url with a head request, falling back to a get
+// for the servers that do not answer it. A result is only cached when it
+// is not cut short by the context or throttled by the host, a throttled
+// url is requested again by the next run.
+func (chk *Checker) checkURL(ctx context.Context, addr string) *Result {
+	res := &Result{URL: addr}
+	status, err := chk.request(ctx, http.MethodHead, addr)
+	if err == nil && (status == http.StatusMethodNotAllowed ||
+		status == http.StatusNotImplemented ||
+		status == http.StatusForbidden) {
+		status, err = chk.request(ctx, http.MethodGet, addr)
+	}
+	res.CheckedOn = time.Now().UTC()
+	res.Status = status
+	if err != nil {
+		res.Error = err.Error()
+	}
+	res.Broken = err != nil ||
+		(status >= http.StatusBadRequest &&
+			status != http.StatusTooManyRequests)
+	if ctx.Err() == nil && status != http.StatusTooManyRequests {
+		chk.mutex.Lock()
+		chk.cache[addr] = res
+		chk.mutex.Unlock()
+	}
+
+	return res
+}
+
+func (chk *Checker) request(
+	ctx context.Context,
+	method, addr string,
+) (int, error) {
+	parsed, err := url.Parse(addr)
+	if err != nil {
+		return 0, fmt.Errorf("error in parsing url %s", err)
+	}
+	if err := chk.limiter.wait(ctx, parsed.Host); err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, chk.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, addr, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error in creating request %s", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := chk.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error in requesting url %s", err)
+	}
+	defer resp.Body.Close()
+	//nolint:errcheck
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, nil
+}
+
+// hostLimiter spaces apart the requests to every host by the interval, a
+// negative interval turns it off.
+type hostLimiter struct {
+	interval time.Duration
+	mutex    sync.Mutex
+	next     map[string]time.Time
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{interval: interval, next: make(map[string]time.Time)}
+}
+
+// wait blocks until the host could be requested again or the context is
+// done.
+func (hlm *hostLimiter) wait(ctx context.Context, host string) error {
+	if hlm.interval <= 0 {
+		return nil
+	}
+	hlm.mutex.Lock()
+	now := time.Now()
+	slot := hlm.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	hlm.next[host] = slot.Add(hlm.interval)
+	hlm.mutex.Unlock()
+	timer := time.NewTimer(slot.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("error in waiting for host %s %s", host, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
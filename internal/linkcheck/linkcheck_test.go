@@ -0,0 +1,235 @@
+package linkcheck
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// linkServer answers /ok, /head-less(no head requests), /busy(throttled),
+// /gone and /slow,
+// counting the requests and the most of them in flight.
+type linkServer struct {
+	*httptest.Server
+	requests int64
+	mutex    sync.Mutex
+	inflight int
+	peak     int
+}
+
+func newLinkServer(t *testing.T) *linkServer {
+	t.Helper()
+	lsv := &linkServer{}
+	lsv.Server = httptest.NewServer(http.HandlerFunc(lsv.handle))
+	t.Cleanup(lsv.Close)
+
+	return lsv
+}
+
+func (lsv *linkServer) handle(wrt http.ResponseWriter, req *http.Request) {
+	atomic.AddInt64(&lsv.requests, 1)
+	lsv.mutex.Lock()
+	lsv.inflight++
+	if lsv.inflight > lsv.peak {
+		lsv.peak = lsv.inflight
+	}
+	lsv.mutex.Unlock()
+	defer func() {
+		lsv.mutex.Lock()
+		lsv.inflight--
+		lsv.mutex.Unlock()
+	}()
+	switch req.URL.Path {
+	case "/ok":
+		wrt.WriteHeader(http.StatusOK)
+	case "/head-less":
+		if req.Method == http.MethodHead {
+			wrt.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+		wrt.WriteHeader(http.StatusOK)
+	case "/busy":
+		wrt.WriteHeader(http.StatusTooManyRequests)
+	case "/slow":
+		time.Sleep(50 * time.Millisecond)
+		wrt.WriteHeader(http.StatusOK)
+	default:
+		wrt.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (lsv *linkServer) count() int64 {
+	return atomic.LoadInt64(&lsv.requests)
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lsv := newLinkServer(t)
+	chk := NewChecker(&Params{HostInterval: -1})
+	urls := []string{
+		lsv.URL + "/ok", lsv.URL + "/head-less", lsv.URL + "/gone",
+		lsv.URL + "/ok", "http://127.0.0.1:1/refused",
+	}
+	results, err := chk.Check(context.Background(), urls)
+	assert.NoErrorf(err, "expect no error from check %s", err)
+	assert.Len(results, 4, "expect a result for every url")
+	assert.False(results[lsv.URL+"/ok"].Broken, "ok should not be broken")
+	assert.False(
+		results[lsv.URL+"/head-less"].Broken,
+		"should fall back to get",
+	)
+	assert.True(results[lsv.URL+"/gone"].Broken, "gone should be broken")
+	assert.Equal(http.StatusNotFound, results[lsv.URL+"/gone"].Status)
+	refused := results["http://127.0.0.1:1/refused"]
+	assert.True(refused.Broken, "refused should be broken")
+	assert.NotEmpty(refused.Error, "expect the error of refused")
+	requests := lsv.count()
+	_, err = chk.Check(context.Background(), urls)
+	assert.NoErrorf(err, "expect no error from check %s", err)
+	assert.Equal(requests, lsv.count(), "should reuse the cached results")
+}
+
+func TestCacheExpiry(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lsv := newLinkServer(t)
+	chk := NewChecker(&Params{HostInterval: -1, CacheTTL: time.Nanosecond})
+	for i := 0; i < 2; i++ {
+		_, err := chk.Check(context.Background(), []string{lsv.URL + "/ok"})
+		assert.NoErrorf(err, "expect no error from check %s", err)
+	}
+	assert.Equal(int64(2), lsv.count(), "should check the expired url")
+	var buf bytes.Buffer
+	assert.NoError(chk.WriteCache(&buf), "expect no error from writing cache")
+	assert.JSONEq("[]", buf.String(), "should drop the expired results")
+}
+
+func TestThrottled(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lsv := newLinkServer(t)
+	chk := NewChecker(&Params{HostInterval: -1})
+	for i := 0; i < 2; i++ {
+		results, err := chk.Check(
+			context.Background(),
+			[]string{lsv.URL + "/busy"},
+		)
+		assert.NoErrorf(err, "expect no error from check %s", err)
+		assert.False(
+			results[lsv.URL+"/busy"].Broken,
+			"throttled should not be broken",
+		)
+	}
+	assert.Equal(int64(2), lsv.count(), "should not cache a throttled url")
+}
+
+func TestCacheFile(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lsv := newLinkServer(t)
+	urls := []string{lsv.URL + "/ok", lsv.URL + "/gone"}
+	chk := NewChecker(&Params{HostInterval: -1})
+	_, err := chk.Check(context.Background(), urls)
+	assert.NoErrorf(err, "expect no error from check %s", err)
+	var buf bytes.Buffer
+	assert.NoError(chk.WriteCache(&buf), "expect no error from writing cache")
+	requests := lsv.count()
+	next := NewChecker(&Params{HostInterval: -1})
+	assert.NoError(next.ReadCache(&buf), "expect no error from reading cache")
+	results, err := next.Check(context.Background(), urls)
+	assert.NoErrorf(err, "expect no error from check %s", err)
+	assert.Equal(requests, lsv.count(), "should reuse the saved results")
+	assert.True(results[lsv.URL+"/gone"].Broken, "gone should stay broken")
+}
+
+func TestConcurrency(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lsv := newLinkServer(t)
+	chk := NewChecker(&Params{HostInterval: -1, Concurrency: 2})
+	urls := make([]string, 0)
+	for _, query := range []string{"a", "b", "c", "d", "e", "f"} {
+		urls = append(urls, lsv.URL+"/slow?"+query)
+	}
+	_, err := chk.Check(context.Background(), urls)
+	assert.NoErrorf(err, "expect no error from check %s", err)
+	lsv.mutex.Lock()
+	defer lsv.mutex.Unlock()
+	assert.LessOrEqual(lsv.peak, 2, "should bound the requests in flight")
+}
+
+func TestHostInterval(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lsv := newLinkServer(t)
+	interval := 40 * time.Millisecond
+	chk := NewChecker(&Params{HostInterval: interval, Concurrency: 4})
+	start := time.Now()
+	_, err := chk.Check(context.Background(), []string{
+		lsv.URL + "/ok?a", lsv.URL + "/ok?b", lsv.URL + "/ok?c",
+	})
+	assert.NoErrorf(err, "expect no error from check %s", err)
+	assert.GreaterOrEqual(
+		time.Since(start),
+		2*interval,
+		"should space apart the requests to the host",
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = chk.Check(ctx, []string{lsv.URL + "/ok?d"})
+	assert.Error(err, "expect error from cancelled check")
+}
+
+func TestCheckContents(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lsv := newLinkServer(t)
+	link := func(path string) string {
+		return `{"type": "link", "url": "` + lsv.URL + path + `"}`
+	}
+	cnts := []*model.ContentDoc{
+		{
+			Slug:      "order-dsc",
+			Namespace: "dsc",
+			Content:   `{"children": [` + link("/ok") + `,` + link("/gone") + `]}`,
+		},
+		{Slug: "faq-dsc", Namespace: "dsc", Content: `{"children": []}`},
+		{
+			Slug:      "about-dictybase",
+			Namespace: "dictybase",
+			Content:   `{"children": [` + link("/missing") + `]}`,
+		},
+		{
+			Slug:      "news-dsc",
+			Namespace: "dsc",
+			Content:   `{"children": [` + link("/gone") + `]}`,
+		},
+	}
+	cnts[0].Key, cnts[2].Key, cnts[3].Key = "1", "3", "4"
+	chk := NewChecker(&Params{HostInterval: -1})
+	report, err := chk.CheckContents(context.Background(), cnts)
+	assert.NoErrorf(err, "expect no error from checking contents %s", err)
+	assert.Equal(3, report.Checked, "expect three distinct urls")
+	assert.Equal(2, report.Broken, "expect two broken urls")
+	slugs := make([]string, 0)
+	for _, crp := range report.Contents {
+		slugs = append(slugs, crp.Slug)
+	}
+	assert.Equal(
+		[]string{"about-dictybase", "news-dsc", "order-dsc"},
+		slugs,
+		"should report by namespace and slug",
+	)
+	assert.Len(report.Contents[2].Links, 1, "expect only the broken link")
+	assert.Equal(int64(1), report.Contents[2].ContentID, "id should match")
+}
@@ -0,0 +1,224 @@
+// Package links extracts the references of the editor json of a content,
+// the embedded assets, the internal links to other slugs and the external
+// urls, and builds the link documents kept by the backends.
+package links
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+// contents embed an asset by its download path.
+var assetReg = regexp.MustCompile(`/assets/([0-9]+)\b`)
+
+// AssetRefs returns the ids of the assets embedded in the content, in
+// ascending order without repeats.
+func AssetRefs(body string) []int64 {
+	seen := make(map[int64]bool)
+	aids := make([]int64, 0)
+	for _, match := range assetReg.FindAllStringSubmatch(body, -1) {
+		aid, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || seen[aid] {
+			continue
+		}
+		seen[aid] = true
+		aids = append(aids, aid)
+	}
+	sort.Slice(aids, func(i, j int) bool { return aids[i] < aids[j] })
+
+	return aids
+}
+
+// NewLinks builds the links of the source to the targets, ordered by target
+// without repeats.
+func NewLinks(src *model.LinkDoc, targets []string) []*model.LinkDoc {
+	sorted := append([]string{}, targets...)
+	sort.Strings(sorted)
+	lnks := make([]*model.LinkDoc, 0, len(sorted))
+	for idx, target := range sorted {
+		if idx > 0 && sorted[idx-1] == target {
+			continue
+		}
+		lnks = append(lnks, &model.LinkDoc{
+			ContentID: src.ContentID,
+			Slug:      src.Slug,
+			Namespace: src.Namespace,
+			Target:    target,
+		})
+	}
+
+	return lnks
+}
+
+// BrokenLinks returns the links whose target does not exist, every target
+// is looked up once.
+func BrokenLinks(
+	lnks []*model.LinkDoc,
+	exists func(slug string) (bool, error),
+) ([]*model.LinkDoc, error) {
+	found := make(map[string]bool)
+	broken := make([]*model.LinkDoc, 0)
+	for _, lnk := range lnks {
+		if _, ok := found[lnk.Target]; !ok {
+			exist, err := exists(lnk.Target)
+			if err != nil {
+				return nil, err
+			}
+			found[lnk.Target] = exist
+		}
+		if !found[lnk.Target] {
+			broken = append(broken, lnk)
+		}
+	}
+
+	return broken, nil
+}
+
+// FindBroken returns the links of the contents in the namespace, all of
+// them when empty, whose target content does not exist.
+func FindBroken(
+	ctx context.Context,
+	repo repository.ContentRepository,
+	lrepo repository.LinkRepository,
+	namespace string,
+) ([]*model.LinkDoc, error) {
+	lnks, err := lrepo.ListLinks(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return BrokenLinks(lnks, func(slug string) (bool, error) {
+		mcont, err := repo.GetContentBySlug(ctx, slug)
+		if err != nil {
+			return false, err
+		}
+
+		return !mcont.NotFound, nil
+	})
+}
+
+// the editor keeps the address of a link under either of the keys.
+var linkKeys = []string{"url", "href"}
+
+// LinkRefs returns the slugs linked from the editor json, in ascending
+// order without repeats. An internal link is a path on the same host, its
+// last segment is the slug, the paths of assets are left out.
+func LinkRefs(body string) []string {
+	seen := make(map[string]bool)
+	slugs := make([]string, 0)
+	for _, addr := range linkAddresses(body) {
+		if !strings.HasPrefix(addr, "/") || strings.HasPrefix(addr, "//") ||
+			assetReg.MatchString(addr) {
+			continue
+		}
+		if idx := strings.IndexAny(addr, "?#"); idx >= 0 {
+			addr = addr[:idx]
+		}
+		slug := path.Base(strings.TrimRight(addr, "/"))
+		if slug == "/" || slug == "." || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	return slugs
+}
+
+// linkAddresses collects the link addresses anywhere in the editor json, a
+// body that is not json has none.
+func linkAddresses(body string) []string {
+	addrs := make([]string, 0)
+	walkStrings(body, func(key, val string) {
+		for _, lkey := range linkKeys {
+			if key == lkey {
+				addrs = append(addrs, strings.TrimSpace(val))
+			}
+		}
+	})
+
+	return addrs
+}
+
+// an external url runs up to a space, quote or angle bracket.
+var externalReg = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// ExternalURLs returns the http and https urls found anywhere in the editor
+// json, either as link addresses or in the text, in ascending order without
+// repeats. The trailing punctuation of the text is left out.
+func ExternalURLs(body string) []string {
+	seen := make(map[string]bool)
+	urls := make([]string, 0)
+	walkStrings(body, func(_, val string) {
+		for _, addr := range externalReg.FindAllString(val, -1) {
+			addr = trimPunctuation(addr)
+			if seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			urls = append(urls, addr)
+		}
+	})
+	sort.Strings(urls)
+
+	return urls
+}
+
+// closing brackets of the url, a closing bracket is kept only when it
+// matches an opening one, as in /wiki/Foo_(bar).
+var brackets = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+// trimPunctuation drops the trailing punctuation of the text from the url.
+func trimPunctuation(addr string) string {
+	for len(addr) > 0 {
+		last := addr[len(addr)-1]
+		if strings.IndexByte(".,;:!?", last) >= 0 {
+			addr = addr[:len(addr)-1]
+
+			continue
+		}
+		open, ok := brackets[last]
+		if !ok || strings.Count(addr, string(open)) >=
+			strings.Count(addr, string(last)) {
+			return addr
+		}
+		addr = addr[:len(addr)-1]
+	}
+
+	return addr
+}
+
+// walkStrings visits every string value of the json along with the key it
+// is kept under, the key is empty for an element of an array. A body that
+// is not json has no values.
+func walkStrings(body string, visit func(key, val string)) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return
+	}
+	var walk func(string, interface{})
+	walk = func(key string, node interface{}) {
+		switch val := node.(type) {
+		case string:
+			visit(key, val)
+		case map[string]interface{}:
+			for ckey, child := range val {
+				walk(ckey, child)
+			}
+		case []interface{}:
+			for _, child := range val {
+				walk("", child)
+			}
+		}
+	}
+	walk("", doc)
+}
@@ -0,0 +1,93 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetRefs(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	body := `{"children": [
+		{"type": "image", "url": "https://dictybase.org/assets/12"},
+		{"type": "image", "url": "/assets/3?width=200"},
+		{"type": "link", "url": "/assets/12"},
+		{"type": "link", "url": "/assets/12abc"},
+		{"type": "link", "url": "/stockcenter/assets"}
+	]}`
+	assert.Equal(AssetRefs(body), []int64{3, 12}, "should match asset ids")
+	assert.Empty(AssetRefs(`{"text": "no assets"}`), "expect no assets")
+}
+
+func TestLinkRefs(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	body := `{"children": [
+		{"type": "link", "url": "/stockcenter/order-info", "children": [
+			{"type": "link", "href": "/about#team"}
+		]},
+		{"type": "link", "url": "/order-info?tab=1"},
+		{"type": "link", "url": "https://dictybase.org/external"},
+		{"type": "link", "url": "//cdn.dictybase.org/lib"},
+		{"type": "image", "url": "/assets/12"},
+		{"type": "link", "url": "/"},
+		{"text": "/not-a-link"}
+	]}`
+	assert.Equal(
+		LinkRefs(body),
+		[]string{"about", "order-info"},
+		"should match the linked slugs",
+	)
+	assert.Empty(LinkRefs("/about"), "expect no links outside json")
+}
+
+func TestBrokenLinks(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	lnks := NewLinks(
+		&model.LinkDoc{ContentID: 1, Slug: "home", Namespace: "dsc"},
+		[]string{"order", "about", "faq"},
+	)
+	lookups := 0
+	broken, err := BrokenLinks(
+		append(lnks, lnks...),
+		func(slug string) (bool, error) {
+			lookups++
+
+			return slug == "about", nil
+		},
+	)
+	assert.NoErrorf(err, "expect no error from broken links %s", err)
+	assert.Len(broken, 4, "expect the links to missing targets")
+	assert.Equal(broken[0].Target, "faq", "should keep the order")
+	assert.Equal(lookups, 3, "should look up every target once")
+}
+
+func TestExternalURLs(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	body := `{"children": [
+		{"type": "link", "url": "https://doi.org/10.1093/nar/gkz1056",
+			"children": [{"text": "see https://dictybase.org/faq."}]},
+		{"type": "link", "url": "/stockcenter/order-info"},
+		{"text": "at http://dicty.example.org/a?b=c, or the same"},
+		{"text": "https://doi.org/10.1093/nar/gkz1056 (again)"},
+		{"type": "link", "href": "ftp://files.dictybase.org"},
+		{"text": "(see https://en.wikipedia.org/wiki/Foo_(bar))."},
+		{"text": "[https://dictybase.org/a_(b)_c]"}
+	]}`
+	assert.Equal(
+		ExternalURLs(body),
+		[]string{
+			"http://dicty.example.org/a?b=c",
+			"https://dictybase.org/a_(b)_c",
+			"https://dictybase.org/faq",
+			"https://doi.org/10.1093/nar/gkz1056",
+			"https://en.wikipedia.org/wiki/Foo_(bar)",
+		},
+		"should match the external urls",
+	)
+	assert.Empty(ExternalURLs("https://dictybase.org"), "expect no json")
+}
@@ -1,13 +1,41 @@
 package message
 
 import (
+	"context"
+
 	"github.com/dictyBase/go-genproto/dictybaseapis/content"
 )
 
 // Publisher manages publishing of message.
 type Publisher interface {
-	// Publis publishes the annotation object using the given subject
-	Publish(subject string, cont *content.Content) error
+	// Publis publishes the annotation object using the given subject, the
+	// trace context of ctx is sent along with the message
+	Publish(ctx context.Context, subject string, cont *content.Content) error
 	// Close closes the connection to the underlying messaging server
 	Close() error
 }
+
+// RequestHandler answers a request, both the request and the reply are
+// encoded in protocol buffers.
+type RequestHandler func(ctx context.Context, data []byte) ([]byte, error)
+
+// Replier answers the requests sent to a subject.
+type Replier interface {
+	// Reply subscribes the handler to the subject within the queue group, a
+	// request is answered by only one member of the group
+	Reply(subject, queue string, handler RequestHandler) error
+	// Close stops answering the requests
+	Close() error
+}
+
+// EventHandler handles a content event received from the messaging server.
+type EventHandler func(ctx context.Context, cont *content.Content)
+
+// Subscriber receives the content events published to a subject.
+type Subscriber interface {
+	// Subscribe passes every content published to the subject to the
+	// handler, unlike a queue group every subscriber gets all of them
+	Subscribe(subject string, handler EventHandler) error
+	// Close stops receiving the events
+	Close() error
+}
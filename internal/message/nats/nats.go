@@ -1,16 +1,23 @@
 package nats
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/dictyBase/go-genproto/dictybaseapis/content"
 	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/tracing"
 	gnats "github.com/nats-io/nats.go"
-	"github.com/nats-io/nats.go/encoders/protobuf"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
 )
 
 type natsPublisher struct {
-	econn *gnats.EncodedConn
+	conn *gnats.Conn
 }
 
 func NewPublisher(
@@ -26,19 +33,55 @@ func NewPublisher(
 			err,
 		)
 	}
-	ec, err := gnats.NewEncodedConn(ncr, protobuf.PROTOBUF_ENCODER)
-	if err != nil {
-		return &natsPublisher{}, fmt.Errorf("error in encoding %s", err)
-	}
 
-	return &natsPublisher{econn: ec}, nil
+	return &natsPublisher{conn: ncr}, nil
 }
 
+// Publish sends the content encoded in protocol buffers, the trace context
+// is injected into the message headers so that consumers could continue
+// the trace.
 func (n *natsPublisher) Publish(
+	ctx context.Context,
 	subj string,
 	cont *content.Content,
 ) error {
-	if err := n.econn.Publish(subj, cont); err != nil {
+	return n.publish(
+		ctx,
+		subj,
+		func() ([]byte, error) { return proto.Marshal(cont) },
+		attribute.Int64("content.id", cont.GetData().GetId()),
+	)
+}
+
+func (n *natsPublisher) publish(
+	ctx context.Context,
+	subj string,
+	encode func() ([]byte, error),
+	attrs ...attribute.KeyValue,
+) error {
+	ctx, span := tracing.Tracer().Start(
+		ctx,
+		fmt.Sprintf("%s publish", subj),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nats"),
+			semconv.MessagingDestinationName(subj),
+		),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+	data, err := encode()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return fmt.Errorf("error in encoding %s", err)
+	}
+	msg := gnats.NewMsg(subj)
+	msg.Data = data
+	otel.GetTextMapPropagator().Inject(ctx, HeaderCarrier(msg.Header))
+	if err := n.conn.PublishMsg(msg); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
 		return fmt.Errorf("error in publishing through nats %s", err)
 	}
 
@@ -46,7 +89,28 @@ func (n *natsPublisher) Publish(
 }
 
 func (n *natsPublisher) Close() error {
-	n.econn.Close()
+	n.conn.Close()
 
 	return nil
 }
+
+// HeaderCarrier adapts the headers of a nats message for propagating the
+// trace context, the keys are kept as is.
+type HeaderCarrier gnats.Header
+
+func (hdc HeaderCarrier) Get(key string) string {
+	return gnats.Header(hdc).Get(key)
+}
+
+func (hdc HeaderCarrier) Set(key, value string) {
+	gnats.Header(hdc).Set(key, value)
+}
+
+func (hdc HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(hdc))
+	for key := range hdc {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,33 @@
+package nats
+
+import (
+	"context"
+	"testing"
+
+	gnats "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeaderCarrier(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	tpr := sdktrace.NewTracerProvider()
+	ctx, span := tpr.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+	prop := propagation.TraceContext{}
+	msg := gnats.NewMsg("ContentService.Create")
+	prop.Inject(ctx, HeaderCarrier(msg.Header))
+	assert.NotEmpty(
+		msg.Header.Get("traceparent"),
+		"should inject the traceparent header",
+	)
+	rctx := prop.Extract(context.Background(), HeaderCarrier(msg.Header))
+	assert.Equal(
+		trace.SpanContextFromContext(rctx).TraceID(),
+		span.SpanContext().TraceID(),
+		"should continue the trace of the publisher",
+	)
+}
@@ -0,0 +1,115 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/tracing"
+	gnats "github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// headers of a failed reply, named after the convention of the nats
+	// micro services
+	ErrorHeader     = "Nats-Service-Error"
+	ErrorCodeHeader = "Nats-Service-Error-Code"
+)
+
+type natsReplier struct {
+	mutex sync.Mutex
+	conn  *gnats.Conn
+	subs  []*gnats.Subscription
+}
+
+// NewReplier answers requests over the connection of a publisher created by
+// NewPublisher.
+func NewReplier(pub message.Publisher) (message.Replier, error) {
+	npb, ok := pub.(*natsPublisher)
+	if !ok || npb.conn == nil {
+		return &natsReplier{}, fmt.Errorf("publisher is not connected to nats")
+	}
+
+	return &natsReplier{conn: npb.conn}, nil
+}
+
+func (n *natsReplier) Reply(
+	subj, queue string,
+	handler message.RequestHandler,
+) error {
+	sub, err := n.conn.QueueSubscribe(subj, queue, func(msg *gnats.Msg) {
+		reply := handleRequest(msg, handler)
+		if err := n.conn.PublishMsg(reply); err != nil {
+			log.Printf("error in replying to %s %s", subj, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("error in subscribing to %s %s", subj, err)
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.subs = append(n.subs, sub)
+
+	return nil
+}
+
+// Close unsubscribes from all the subjects, the connection is left to the
+// publisher.
+func (n *natsReplier) Close() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, sub := range n.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			return fmt.Errorf("error in unsubscribing %s", err)
+		}
+	}
+	n.subs = nil
+
+	return nil
+}
+
+// handleRequest runs the handler within the trace of the requester and
+// returns the reply, a failure is sent as an empty reply with the message
+// and the grpc code of the error in the headers.
+func handleRequest(
+	msg *gnats.Msg,
+	handler message.RequestHandler,
+) *gnats.Msg {
+	ctx := context.Background()
+	if msg.Header != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, HeaderCarrier(msg.Header))
+	}
+	ctx, span := tracing.Tracer().Start(
+		ctx,
+		fmt.Sprintf("%s process", msg.Subject),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nats"),
+			semconv.MessagingDestinationName(msg.Subject),
+		),
+	)
+	defer span.End()
+	reply := gnats.NewMsg(msg.Reply)
+	data, err := handler(ctx, msg.Data)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		reply.Header.Set(ErrorHeader, status.Convert(err).Message())
+		reply.Header.Set(
+			ErrorCodeHeader,
+			strconv.Itoa(int(status.Code(err))),
+		)
+
+		return reply
+	}
+	reply.Data = data
+
+	return reply
+}
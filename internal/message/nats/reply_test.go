@@ -0,0 +1,55 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	gnats "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHandleRequest(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	msg := gnats.NewMsg("ContentService.Get")
+	msg.Reply = "_INBOX.reply"
+	msg.Data = []byte("request")
+	reply := handleRequest(msg, func(_ context.Context, data []byte) ([]byte, error) {
+		return append([]byte("reply to "), data...), nil
+	})
+	assert.Equal(reply.Subject, "_INBOX.reply", "should send to the inbox")
+	assert.Equal(string(reply.Data), "reply to request", "should match reply")
+	assert.Empty(reply.Header.Get(ErrorHeader), "expect no error header")
+	reply = handleRequest(msg, func(context.Context, []byte) ([]byte, error) {
+		return nil, status.Error(codes.NotFound, "id 5 not found")
+	})
+	assert.Empty(reply.Data, "expect no data for failed request")
+	assert.Equal(
+		reply.Header.Get(ErrorHeader),
+		"id 5 not found",
+		"should match the error message",
+	)
+	assert.Equal(
+		reply.Header.Get(ErrorCodeHeader),
+		strconv.Itoa(int(codes.NotFound)),
+		"should match the grpc code",
+	)
+	reply = handleRequest(msg, func(context.Context, []byte) ([]byte, error) {
+		return nil, errors.New("no database")
+	})
+	assert.Equal(
+		reply.Header.Get(ErrorCodeHeader),
+		strconv.Itoa(int(codes.Unknown)),
+		"should be unknown for a plain error",
+	)
+}
+
+func TestNewReplier(t *testing.T) {
+	t.Parallel()
+	_, err := NewReplier(&natsPublisher{})
+	require.Error(t, err, "expect error for unconnected publisher")
+}
@@ -0,0 +1,99 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/message"
+	"github.com/dictyBase/modware-content/internal/tracing"
+	gnats "github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+type natsSubscriber struct {
+	mutex sync.Mutex
+	conn  *gnats.Conn
+	subs  []*gnats.Subscription
+}
+
+// NewSubscriber receives the content events over the connection of a
+// publisher created by NewPublisher.
+func NewSubscriber(pub message.Publisher) (message.Subscriber, error) {
+	npb, ok := pub.(*natsPublisher)
+	if !ok || npb.conn == nil {
+		return &natsSubscriber{}, fmt.Errorf(
+			"publisher is not connected to nats",
+		)
+	}
+
+	return &natsSubscriber{conn: npb.conn}, nil
+}
+
+func (n *natsSubscriber) Subscribe(
+	subj string,
+	handler message.EventHandler,
+) error {
+	sub, err := n.conn.Subscribe(subj, func(msg *gnats.Msg) {
+		if err := handleEvent(msg, handler); err != nil {
+			log.Printf("error in handling event of %s %s", subj, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("error in subscribing to %s %s", subj, err)
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.subs = append(n.subs, sub)
+
+	return nil
+}
+
+// Close unsubscribes from all the subjects, the connection is left to the
+// publisher.
+func (n *natsSubscriber) Close() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, sub := range n.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			return fmt.Errorf("error in unsubscribing %s", err)
+		}
+	}
+	n.subs = nil
+
+	return nil
+}
+
+// handleEvent decodes the content and runs the handler within the trace of
+// the publisher.
+func handleEvent(msg *gnats.Msg, handler message.EventHandler) error {
+	ctx := context.Background()
+	if msg.Header != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, HeaderCarrier(msg.Header))
+	}
+	ctx, span := tracing.Tracer().Start(
+		ctx,
+		fmt.Sprintf("%s process", msg.Subject),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nats"),
+			semconv.MessagingDestinationName(msg.Subject),
+		),
+	)
+	defer span.End()
+	cont := &content.Content{}
+	if err := proto.Unmarshal(msg.Data, cont); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return fmt.Errorf("error in decoding content %s", err)
+	}
+	handler(ctx, cont)
+
+	return nil
+}
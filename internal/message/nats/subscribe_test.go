@@ -0,0 +1,42 @@
+package nats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	gnats "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHandleEvent(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	data, err := proto.Marshal(&content.Content{
+		Data: &content.ContentData{
+			Id:         5,
+			Attributes: &content.ContentAttributes{Slug: "dsc-catalog"},
+		},
+	})
+	assert.NoErrorf(err, "expect no error from encoding content %s", err)
+	msg := gnats.NewMsg("ContentService.Update")
+	msg.Data = data
+	var slug string
+	err = handleEvent(msg, func(_ context.Context, cont *content.Content) {
+		slug = cont.GetData().GetAttributes().GetSlug()
+	})
+	assert.NoErrorf(err, "expect no error from handling event %s", err)
+	assert.Equal(slug, "dsc-catalog", "should match the decoded slug")
+	msg.Data = []byte("garbage")
+	err = handleEvent(msg, func(context.Context, *content.Content) {
+		t.Error("handler should not run for undecodable event")
+	})
+	assert.Error(err, "expect error for undecodable event")
+}
+
+func TestNewSubscriber(t *testing.T) {
+	t.Parallel()
+	_, err := NewSubscriber(&natsPublisher{})
+	require.Error(t, err, "expect error for unconnected publisher")
+}
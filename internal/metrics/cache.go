@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"github.com/dictyBase/modware-content/internal/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type cacheCollector struct {
+	stats     func() cache.Stats
+	requests  *prometheus.Desc
+	evictions *prometheus.Desc
+	entries   *prometheus.Desc
+}
+
+// NewCacheCollector reports the hits, misses and evictions of the content
+// cache along with the number of cached contents.
+func NewCacheCollector(stats func() cache.Stats) prometheus.Collector {
+	return &cacheCollector{
+		stats: stats,
+		requests: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "requests_total"),
+			"Number of content cache lookups by result.",
+			[]string{"result"},
+			nil,
+		),
+		evictions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "evictions_total"),
+			"Number of contents evicted from the cache for its size.",
+			nil,
+			nil,
+		),
+		entries: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "entries"),
+			"Number of cached contents.",
+			nil,
+			nil,
+		),
+	}
+}
+
+func (ccl *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ccl.requests
+	ch <- ccl.evictions
+	ch <- ccl.entries
+}
+
+func (ccl *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := ccl.stats()
+	ch <- prometheus.MustNewConstMetric(
+		ccl.requests,
+		prometheus.CounterValue,
+		float64(stats.Hits),
+		"hit",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		ccl.requests,
+		prometheus.CounterValue,
+		float64(stats.Misses),
+		"miss",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		ccl.evictions,
+		prometheus.CounterValue,
+		float64(stats.Evictions),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		ccl.entries,
+		prometheus.GaugeValue,
+		float64(stats.Entries),
+	)
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor counts and times every unary grpc request.
+func (mtr *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		mtr.grpcDuration.WithLabelValues(info.FullMethod).
+			Observe(time.Since(start).Seconds())
+		mtr.grpcRequests.WithLabelValues(
+			info.FullMethod,
+			status.Code(err).String(),
+		).Inc()
+
+		return resp, err
+	}
+}
@@ -0,0 +1,93 @@
+// Package metrics collects the prometheus metrics of the service, the grpc
+// requests, the repository operations, the published messages and the
+// number of contents in every namespace.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "modware_content"
+
+// Metrics holds the collectors of the service in its own registry.
+type Metrics struct {
+	registry      *prometheus.Registry
+	grpcRequests  *prometheus.CounterVec
+	grpcDuration  *prometheus.HistogramVec
+	repoDuration  *prometheus.HistogramVec
+	repoErrors    *prometheus.CounterVec
+	publishCounts *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	mtr := &Metrics{
+		registry: prometheus.NewRegistry(),
+		grpcRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "grpc_requests_total",
+				Help:      "Number of grpc requests by method and status code.",
+			},
+			[]string{"method", "code"},
+		),
+		grpcDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "grpc_request_duration_seconds",
+				Help:      "Latency of grpc requests by method.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"method"},
+		),
+		repoDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "repository_operation_duration_seconds",
+				Help:      "Duration of repository operations by backend.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"backend", "operation"},
+		),
+		repoErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "repository_errors_total",
+				Help:      "Number of failed repository operations by backend.",
+			},
+			[]string{"backend", "operation"},
+		),
+		publishCounts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "messages_published_total",
+				Help:      "Number of published messages by subject and status.",
+			},
+			[]string{"subject", "status"},
+		),
+	}
+	mtr.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		mtr.grpcRequests,
+		mtr.grpcDuration,
+		mtr.repoDuration,
+		mtr.repoErrors,
+		mtr.publishCounts,
+	)
+
+	return mtr
+}
+
+// Register adds an extra collector to the registry.
+func (mtr *Metrics) Register(clt prometheus.Collector) error {
+	return mtr.registry.Register(clt)
+}
+
+// Handler serves the collected metrics in the prometheus text format.
+func (mtr *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(mtr.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/cache"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type failingPublisher struct {
+	fail bool
+}
+
+func (fpb *failingPublisher) Publish(
+	context.Context,
+	string,
+	*content.Content,
+) error {
+	if fpb.fail {
+		return errors.New("no connection")
+	}
+
+	return nil
+}
+
+func (fpb *failingPublisher) Close() error {
+	return nil
+}
+
+func TestInstrumentRepository(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mtr := New()
+	repo := mtr.InstrumentRepository(memory.NewContentRepo(), "memory")
+	ctx := context.Background()
+	_, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	err = repo.DeleteContent(ctx, 5600000)
+	assert.Error(err, "expect error from deleting missing content")
+	assert.Equal(
+		testutil.ToFloat64(
+			mtr.repoErrors.WithLabelValues("memory", "delete_content"),
+		),
+		float64(1),
+		"should count the failed delete",
+	)
+	assert.Equal(
+		testutil.ToFloat64(
+			mtr.repoErrors.WithLabelValues("memory", "add_content"),
+		),
+		float64(0),
+		"should not count the successful create as error",
+	)
+	assert.Equal(
+		testutil.CollectAndCount(mtr.repoDuration),
+		2,
+		"should time both operations",
+	)
+}
+
+func TestInstrumentStores(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mtr := New()
+	ctx := context.Background()
+	nrepo := mtr.InstrumentNamespaceRepository(
+		memory.NewNamespaceRepo(),
+		"memory",
+	)
+	_, err := nrepo.GetNamespace(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from getting namespace %s", err)
+	lrepo := mtr.InstrumentLockRepository(memory.NewLockRepo(), "memory")
+	err = lrepo.ReleaseLock(ctx, 5600000, "packer@packer.com")
+	assert.Error(err, "expect error from releasing missing lock")
+	lnrepo := mtr.InstrumentLinkRepository(memory.NewLinkRepo(), "memory")
+	_, err = lnrepo.Backlinks(ctx, "catalog-dsc")
+	assert.NoErrorf(err, "expect no error from listing backlinks %s", err)
+	assert.Equal(
+		testutil.ToFloat64(
+			mtr.repoErrors.WithLabelValues("memory", "release_lock"),
+		),
+		float64(1),
+		"should count the failed release",
+	)
+	assert.Equal(
+		testutil.CollectAndCount(mtr.repoDuration),
+		3,
+		"should time the operations of every repository",
+	)
+}
+
+func TestInstrumentPublisher(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mtr := New()
+	fpb := &failingPublisher{}
+	pub := mtr.InstrumentPublisher(fpb)
+	assert.NoError(pub.Publish(context.Background(), "ContentService.Create", &content.Content{}))
+	fpb.fail = true
+	assert.Error(pub.Publish(context.Background(), "ContentService.Create", &content.Content{}))
+	for _, status := range []string{"success", "failure"} {
+		assert.Equal(
+			testutil.ToFloat64(
+				mtr.publishCounts.WithLabelValues(
+					"ContentService.Create",
+					status,
+				),
+			),
+			float64(1),
+			"should count publish with status %s",
+			status,
+		)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mtr := New()
+	intr := mtr.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/content.ContentService/GetContent"}
+	_, err := intr(
+		context.Background(),
+		nil,
+		info,
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "missing")
+		},
+	)
+	assert.Error(err, "expect error from the handler")
+	assert.Equal(
+		testutil.ToFloat64(
+			mtr.grpcRequests.WithLabelValues(info.FullMethod, "NotFound"),
+		),
+		float64(1),
+		"should count the request with its status code",
+	)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mtr := New()
+	repo := memory.NewContentRepo()
+	assert.NoError(mtr.Register(NewNamespaceCollector(repo)))
+	ctx := context.Background()
+	for _, name := range []string{"catalog", "price"} {
+		_, err := repo.AddContent(ctx, testutils.NewStoreContent(name, "dsc"))
+		assert.NoErrorf(err, "expect no error from creating content %s", err)
+	}
+	rec := httptest.NewRecorder()
+	mtr.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(rec.Code, 200, "should serve the metrics")
+	assert.True(
+		strings.Contains(
+			rec.Body.String(),
+			`modware_content_namespace_contents{namespace="dsc"} 2`,
+		),
+		"should report the number of contents in namespace",
+	)
+}
+
+func TestCacheCollector(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	mtr := New()
+	repo := cache.NewRepository(memory.NewContentRepo(), &cache.Params{})
+	assert.NoError(mtr.Register(NewCacheCollector(repo.CacheStats)))
+	ctx := context.Background()
+	mcont, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	for i := 0; i < 3; i++ {
+		_, err := repo.GetContentBySlug(ctx, mcont.Slug)
+		assert.NoErrorf(err, "expect no error from reading content %s", err)
+	}
+	rec := httptest.NewRecorder()
+	mtr.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, line := range []string{
+		`modware_content_cache_requests_total{result="hit"} 2`,
+		`modware_content_cache_requests_total{result="miss"} 1`,
+		`modware_content_cache_entries 1`,
+	} {
+		assert.Truef(
+			strings.Contains(rec.Body.String(), line),
+			"should report %s",
+			line,
+		)
+	}
+}
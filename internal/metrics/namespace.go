@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// how long a scrape waits for the contents to be counted.
+const countTimeout = 5 * time.Second
+
+type namespaceCollector struct {
+	repo repository.ContentRepository
+	desc *prometheus.Desc
+}
+
+// NewNamespaceCollector reports the number of contents in every namespace,
+// the repository is queried on every scrape.
+func NewNamespaceCollector(
+	repo repository.ContentRepository,
+) prometheus.Collector {
+	return &namespaceCollector{
+		repo: repo,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "namespace_contents"),
+			"Number of contents in a namespace.",
+			[]string{"namespace"},
+			nil,
+		),
+	}
+}
+
+func (nsc *namespaceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nsc.desc
+}
+
+func (nsc *namespaceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+	counts, err := nsc.repo.CountByNamespace(ctx)
+	if err != nil {
+		log.Printf("error in counting contents by namespace %s", err)
+
+		return
+	}
+	for name, total := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			nsc.desc,
+			prometheus.GaugeValue,
+			float64(total),
+			name,
+		)
+	}
+}
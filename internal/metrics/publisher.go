@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/message"
+)
+
+type publisher struct {
+	message.Publisher
+	metrics *Metrics
+}
+
+// InstrumentPublisher counts the successful and failed publishing of
+// messages by subject.
+func (mtr *Metrics) InstrumentPublisher(
+	pub message.Publisher,
+) message.Publisher {
+	return &publisher{Publisher: pub, metrics: mtr}
+}
+
+func (pub *publisher) Publish(
+	ctx context.Context,
+	subject string,
+	cont *content.Content,
+) error {
+	err := pub.Publisher.Publish(ctx, subject, cont)
+	pub.count(subject, err)
+
+	return err
+}
+
+func (pub *publisher) count(subject string, err error) {
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	pub.metrics.publishCounts.WithLabelValues(subject, status).Inc()
+}
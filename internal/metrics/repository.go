@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+// observer records the duration and the failure of the operations of a
+// repository of the backend.
+type observer struct {
+	metrics *Metrics
+	backend string
+}
+
+func (obs *observer) observe(
+	operation string,
+	start time.Time,
+	err error,
+) {
+	obs.metrics.repoDuration.WithLabelValues(obs.backend, operation).
+		Observe(time.Since(start).Seconds())
+	if err != nil {
+		obs.metrics.repoErrors.WithLabelValues(obs.backend, operation).Inc()
+	}
+}
+
+type contentRepository struct {
+	repository.ContentRepository
+	*observer
+}
+
+// InstrumentRepository times every operation of the content repository and
+// counts the failed ones.
+func (mtr *Metrics) InstrumentRepository(
+	repo repository.ContentRepository,
+	backend string,
+) repository.ContentRepository {
+	return &contentRepository{
+		ContentRepository: repo,
+		observer:          &observer{metrics: mtr, backend: backend},
+	}
+}
+
+func (crp *contentRepository) GetContentBySlug(
+	ctx context.Context,
+	slug string,
+) (*model.ContentDoc, error) {
+	start := time.Now()
+	cnt, err := crp.ContentRepository.GetContentBySlug(ctx, slug)
+	crp.observe("get_content_by_slug", start, err)
+
+	return cnt, err
+}
+
+func (crp *contentRepository) GetContent(
+	ctx context.Context,
+	cid int64,
+) (*model.ContentDoc, error) {
+	start := time.Now()
+	cnt, err := crp.ContentRepository.GetContent(ctx, cid)
+	crp.observe("get_content", start, err)
+
+	return cnt, err
+}
+
+func (crp *contentRepository) AddContent(
+	ctx context.Context,
+	cattr *content.NewContentAttributes,
+) (*model.ContentDoc, error) {
+	start := time.Now()
+	cnt, err := crp.ContentRepository.AddContent(ctx, cattr)
+	crp.observe("add_content", start, err)
+
+	return cnt, err
+}
+
+func (crp *contentRepository) EditContent(
+	ctx context.Context,
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	start := time.Now()
+	cnt, err := crp.ContentRepository.EditContent(ctx, cid, cattr)
+	crp.observe("edit_content", start, err)
+
+	return cnt, err
+}
+
+func (crp *contentRepository) DeleteContent(
+	ctx context.Context,
+	cid int64,
+) error {
+	start := time.Now()
+	err := crp.ContentRepository.DeleteContent(ctx, cid)
+	crp.observe("delete_content", start, err)
+
+	return err
+}
+
+func (crp *contentRepository) TransferContents(
+	ctx context.Context,
+	trn *model.ContentTransfer,
+) ([]*model.ContentDoc, error) {
+	start := time.Now()
+	cnts, err := crp.ContentRepository.TransferContents(ctx, trn)
+	crp.observe("transfer_contents", start, err)
+
+	return cnts, err
+}
+
+func (crp *contentRepository) ListContents(
+	ctx context.Context,
+	namespaces []string,
+) ([]*model.ContentDoc, error) {
+	start := time.Now()
+	cnts, err := crp.ContentRepository.ListContents(ctx, namespaces)
+	crp.observe("list_contents", start, err)
+
+	return cnts, err
+}
+
+func (crp *contentRepository) ImportContent(
+	ctx context.Context,
+	cnt *model.ContentDoc,
+	overwrite bool,
+) (*model.ContentDoc, bool, error) {
+	start := time.Now()
+	icnt, ok, err := crp.ContentRepository.ImportContent(ctx, cnt, overwrite)
+	crp.observe("import_content", start, err)
+
+	return icnt, ok, err
+}
+
+func (crp *contentRepository) BatchContents(
+	ctx context.Context,
+	ops []*model.BatchOperation,
+	atomic bool,
+) ([]*model.BatchResult, error) {
+	start := time.Now()
+	results, err := crp.ContentRepository.BatchContents(ctx, ops, atomic)
+	crp.observe("batch_contents", start, err)
+
+	return results, err
+}
+
+func (crp *contentRepository) CountByNamespace(
+	ctx context.Context,
+) (map[string]int64, error) {
+	start := time.Now()
+	counts, err := crp.ContentRepository.CountByNamespace(ctx)
+	crp.observe("count_by_namespace", start, err)
+
+	return counts, err
+}
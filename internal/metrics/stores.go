@@ -0,0 +1,309 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+type namespaceRepository struct {
+	repository.NamespaceRepository
+	*observer
+}
+
+// InstrumentNamespaceRepository times every operation of the namespace
+// repository and counts the failed ones.
+func (mtr *Metrics) InstrumentNamespaceRepository(
+	repo repository.NamespaceRepository,
+	backend string,
+) repository.NamespaceRepository {
+	return &namespaceRepository{
+		NamespaceRepository: repo,
+		observer:            &observer{metrics: mtr, backend: backend},
+	}
+}
+
+func (nrp *namespaceRepository) AddNamespace(
+	ctx context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	start := time.Now()
+	doc, err := nrp.NamespaceRepository.AddNamespace(ctx, nsp)
+	nrp.observe("add_namespace", start, err)
+
+	return doc, err
+}
+
+func (nrp *namespaceRepository) GetNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	start := time.Now()
+	doc, err := nrp.NamespaceRepository.GetNamespace(ctx, name)
+	nrp.observe("get_namespace", start, err)
+
+	return doc, err
+}
+
+func (nrp *namespaceRepository) ListNamespaces(
+	ctx context.Context,
+) ([]*model.NamespaceDoc, error) {
+	start := time.Now()
+	docs, err := nrp.NamespaceRepository.ListNamespaces(ctx)
+	nrp.observe("list_namespaces", start, err)
+
+	return docs, err
+}
+
+func (nrp *namespaceRepository) ArchiveNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	start := time.Now()
+	doc, err := nrp.NamespaceRepository.ArchiveNamespace(ctx, name)
+	nrp.observe("archive_namespace", start, err)
+
+	return doc, err
+}
+
+type auditRepository struct {
+	repository.AuditRepository
+	*observer
+}
+
+// InstrumentAuditRepository times every operation of the audit repository
+// and counts the failed ones.
+func (mtr *Metrics) InstrumentAuditRepository(
+	repo repository.AuditRepository,
+	backend string,
+) repository.AuditRepository {
+	return &auditRepository{
+		AuditRepository: repo,
+		observer:        &observer{metrics: mtr, backend: backend},
+	}
+}
+
+func (aud *auditRepository) AddEntry(
+	ctx context.Context,
+	ent *model.AuditEntry,
+) (*model.AuditEntry, error) {
+	start := time.Now()
+	doc, err := aud.AuditRepository.AddEntry(ctx, ent)
+	aud.observe("add_audit_entry", start, err)
+
+	return doc, err
+}
+
+func (aud *auditRepository) ListEntries(
+	ctx context.Context,
+	flt *model.AuditFilter,
+) ([]*model.AuditEntry, error) {
+	start := time.Now()
+	docs, err := aud.AuditRepository.ListEntries(ctx, flt)
+	aud.observe("list_audit_entries", start, err)
+
+	return docs, err
+}
+
+type lockRepository struct {
+	repository.LockRepository
+	*observer
+}
+
+// InstrumentLockRepository times every operation of the lock repository and
+// counts the failed ones.
+func (mtr *Metrics) InstrumentLockRepository(
+	repo repository.LockRepository,
+	backend string,
+) repository.LockRepository {
+	return &lockRepository{
+		LockRepository: repo,
+		observer:       &observer{metrics: mtr, backend: backend},
+	}
+}
+
+func (lrp *lockRepository) AcquireLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	start := time.Now()
+	lck, err := lrp.LockRepository.AcquireLock(ctx, cid, holder, ttl)
+	lrp.observe("acquire_lock", start, err)
+
+	return lck, err
+}
+
+func (lrp *lockRepository) RenewLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	start := time.Now()
+	lck, err := lrp.LockRepository.RenewLock(ctx, cid, holder, ttl)
+	lrp.observe("renew_lock", start, err)
+
+	return lck, err
+}
+
+func (lrp *lockRepository) ReleaseLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+) error {
+	start := time.Now()
+	err := lrp.LockRepository.ReleaseLock(ctx, cid, holder)
+	lrp.observe("release_lock", start, err)
+
+	return err
+}
+
+func (lrp *lockRepository) BreakLock(
+	ctx context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	start := time.Now()
+	lck, err := lrp.LockRepository.BreakLock(ctx, cid)
+	lrp.observe("break_lock", start, err)
+
+	return lck, err
+}
+
+func (lrp *lockRepository) GetLock(
+	ctx context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	start := time.Now()
+	lck, err := lrp.LockRepository.GetLock(ctx, cid)
+	lrp.observe("get_lock", start, err)
+
+	return lck, err
+}
+
+type assetRepository struct {
+	repository.AssetRepository
+	*observer
+}
+
+// InstrumentAssetRepository times every operation of the asset repository
+// and counts the failed ones.
+func (mtr *Metrics) InstrumentAssetRepository(
+	repo repository.AssetRepository,
+	backend string,
+) repository.AssetRepository {
+	return &assetRepository{
+		AssetRepository: repo,
+		observer:        &observer{metrics: mtr, backend: backend},
+	}
+}
+
+func (arp *assetRepository) AddAsset(
+	ctx context.Context,
+	ast *model.AssetDoc,
+) (*model.AssetDoc, error) {
+	start := time.Now()
+	doc, err := arp.AssetRepository.AddAsset(ctx, ast)
+	arp.observe("add_asset", start, err)
+
+	return doc, err
+}
+
+func (arp *assetRepository) GetAsset(
+	ctx context.Context,
+	aid int64,
+) (*model.AssetDoc, error) {
+	start := time.Now()
+	doc, err := arp.AssetRepository.GetAsset(ctx, aid)
+	arp.observe("get_asset", start, err)
+
+	return doc, err
+}
+
+func (arp *assetRepository) RemoveAsset(
+	ctx context.Context,
+	aid int64,
+) error {
+	start := time.Now()
+	err := arp.AssetRepository.RemoveAsset(ctx, aid)
+	arp.observe("remove_asset", start, err)
+
+	return err
+}
+
+func (arp *assetRepository) SetReferences(
+	ctx context.Context,
+	cid int64,
+	aids []int64,
+) error {
+	start := time.Now()
+	err := arp.AssetRepository.SetReferences(ctx, cid, aids)
+	arp.observe("set_asset_references", start, err)
+
+	return err
+}
+
+type linkRepository struct {
+	repository.LinkRepository
+	*observer
+}
+
+// InstrumentLinkRepository times every operation of the link repository and
+// counts the failed ones.
+func (mtr *Metrics) InstrumentLinkRepository(
+	repo repository.LinkRepository,
+	backend string,
+) repository.LinkRepository {
+	return &linkRepository{
+		LinkRepository: repo,
+		observer:       &observer{metrics: mtr, backend: backend},
+	}
+}
+
+func (lrp *linkRepository) SetLinks(
+	ctx context.Context,
+	src *model.LinkDoc,
+	targets []string,
+) error {
+	start := time.Now()
+	err := lrp.LinkRepository.SetLinks(ctx, src, targets)
+	lrp.observe("set_links", start, err)
+
+	return err
+}
+
+func (lrp *linkRepository) OutgoingLinks(
+	ctx context.Context,
+	cid int64,
+) ([]*model.LinkDoc, error) {
+	start := time.Now()
+	lnks, err := lrp.LinkRepository.OutgoingLinks(ctx, cid)
+	lrp.observe("outgoing_links", start, err)
+
+	return lnks, err
+}
+
+func (lrp *linkRepository) Backlinks(
+	ctx context.Context,
+	slug string,
+) ([]*model.LinkDoc, error) {
+	start := time.Now()
+	lnks, err := lrp.LinkRepository.Backlinks(ctx, slug)
+	lrp.observe("backlinks", start, err)
+
+	return lnks, err
+}
+
+func (lrp *linkRepository) ListLinks(
+	ctx context.Context,
+	namespace string,
+) ([]*model.LinkDoc, error) {
+	start := time.Now()
+	lnks, err := lrp.LinkRepository.ListLinks(ctx, namespace)
+	lrp.observe("list_links", start, err)
+
+	return lnks, err
+}
@@ -0,0 +1,234 @@
+// Package migrate moves contents from the legacy postgres backed
+// dictycontent database into the content repository.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+const (
+	StatusMigrated = "migrated"
+	StatusSkipped  = "skipped"
+	StatusFailed   = "failed"
+	StatusMismatch = "mismatch"
+)
+
+var tableReg = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+// LegacyContent is a row of the content table of dictycontent database.
+type LegacyContent struct {
+	ID        int64
+	Name      string
+	Slug      string
+	Namespace string
+	CreatedBy int64
+	UpdatedBy int64
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+}
+
+// UserEmails maps the legacy user ids to their email addresses, Default is
+// used for any id missing from the map.
+type UserEmails struct {
+	Emails  map[int64]string
+	Default string
+}
+
+func (usr *UserEmails) Email(id int64) (string, error) {
+	if email, ok := usr.Emails[id]; ok {
+		return email, nil
+	}
+	if len(usr.Default) == 0 {
+		return "", fmt.Errorf("no email for user id %d", id)
+	}
+
+	return usr.Default, nil
+}
+
+// ReadLegacy reads all rows from the legacy content table.
+func ReadLegacy(
+	ctx context.Context,
+	dbh *sql.DB,
+	table string,
+) ([]*LegacyContent, error) {
+	if !tableReg.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %s", table)
+	}
+	rows, err := dbh.QueryContext(
+		ctx,
+		fmt.Sprintf(`
+			SELECT content_id, name, slug, namespace, created_by, updated_by,
+				content, created_at, updated_at
+			FROM %s ORDER BY content_id
+		`, table),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in querying legacy contents %s", err)
+	}
+	defer rows.Close()
+	lcnts := make([]*LegacyContent, 0)
+	for rows.Next() {
+		lcnt := &LegacyContent{}
+		err := rows.Scan(
+			&lcnt.ID, &lcnt.Name, &lcnt.Slug, &lcnt.Namespace,
+			&lcnt.CreatedBy, &lcnt.UpdatedBy, &lcnt.Content,
+			&lcnt.CreatedAt, &lcnt.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error in reading legacy content %s", err)
+		}
+		lcnts = append(lcnts, lcnt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in iterating legacy contents %s", err)
+	}
+
+	return lcnts, nil
+}
+
+// ContentDoc maps a legacy row to the content model keeping its id as the
+// document key.
+func (lcnt *LegacyContent) ContentDoc(
+	usr *UserEmails,
+) (*model.ContentDoc, error) {
+	createdBy, err := usr.Email(lcnt.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	updatedBy, err := usr.Email(lcnt.UpdatedBy)
+	if err != nil {
+		return nil, err
+	}
+	updatedOn := lcnt.CreatedAt
+	if lcnt.UpdatedAt.Valid {
+		updatedOn = lcnt.UpdatedAt.Time
+	}
+
+	return &model.ContentDoc{
+		DocumentMeta: driver.DocumentMeta{
+			Key: strconv.FormatInt(lcnt.ID, 10),
+		},
+		Name:      lcnt.Name,
+		Slug:      lcnt.Slug,
+		Namespace: lcnt.Namespace,
+		CreatedBy: createdBy,
+		UpdatedBy: updatedBy,
+		Content:   lcnt.Content,
+		CreatedOn: lcnt.CreatedAt,
+		UpdatedOn: updatedOn,
+	}, nil
+}
+
+// ReportEntry is the outcome of migrating a single legacy row.
+type ReportEntry struct {
+	ID      int64  `json:"id"`
+	Slug    string `json:"slug"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report reconciles the legacy rows with the migrated contents.
+type Report struct {
+	Source   int            `json:"source"`
+	Migrated int            `json:"migrated"`
+	Skipped  int            `json:"skipped"`
+	Failed   int            `json:"failed"`
+	Mismatch int            `json:"mismatch"`
+	Entries  []*ReportEntry `json:"entries"`
+}
+
+func (rpt *Report) add(lcnt *LegacyContent, status, msg string) {
+	switch status {
+	case StatusMigrated:
+		rpt.Migrated++
+	case StatusSkipped:
+		rpt.Skipped++
+	case StatusFailed:
+		rpt.Failed++
+	case StatusMismatch:
+		rpt.Mismatch++
+	}
+	rpt.Entries = append(rpt.Entries, &ReportEntry{
+		ID:      lcnt.ID,
+		Slug:    lcnt.Slug,
+		Status:  status,
+		Message: msg,
+	})
+}
+
+// Migrate writes the legacy contents through the repository and verifies
+// every written content against its source row.
+func Migrate(
+	ctx context.Context,
+	repo repository.ContentRepository,
+	lcnts []*LegacyContent,
+	usr *UserEmails,
+	overwrite bool,
+) *Report {
+	rpt := &Report{Source: len(lcnts), Entries: make([]*ReportEntry, 0)}
+	for _, lcnt := range lcnts {
+		cnt, err := lcnt.ContentDoc(usr)
+		if err != nil {
+			rpt.add(lcnt, StatusFailed, err.Error())
+
+			continue
+		}
+		_, ok, err := repo.ImportContent(ctx, cnt, overwrite)
+		if err != nil {
+			rpt.add(lcnt, StatusFailed, err.Error())
+
+			continue
+		}
+		if !ok {
+			rpt.add(lcnt, StatusSkipped, "content with same id exists")
+
+			continue
+		}
+		stored, err := repo.GetContent(ctx, lcnt.ID)
+		if err != nil {
+			rpt.add(lcnt, StatusFailed, err.Error())
+
+			continue
+		}
+		if msg := compare(cnt, stored); len(msg) > 0 {
+			rpt.add(lcnt, StatusMismatch, msg)
+
+			continue
+		}
+		rpt.add(lcnt, StatusMigrated, "")
+	}
+
+	return rpt
+}
+
+func compare(src, dst *model.ContentDoc) string {
+	switch {
+	case dst.NotFound:
+		return "content not found after migration"
+	case src.Slug != dst.Slug:
+		return fmt.Sprintf("slug %s differs from %s", dst.Slug, src.Slug)
+	case src.Namespace != dst.Namespace:
+		return fmt.Sprintf(
+			"namespace %s differs from %s",
+			dst.Namespace, src.Namespace,
+		)
+	case src.Content != dst.Content:
+		return "content differs"
+	case !src.CreatedOn.Equal(dst.CreatedOn):
+		return "created_on differs"
+	case !src.UpdatedOn.Equal(dst.UpdatedOn):
+		return "updated_on differs"
+	}
+
+	return ""
+}
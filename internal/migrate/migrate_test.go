@@ -0,0 +1,117 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dictyBase/modware-content/internal/repository/memory"
+	"github.com/stretchr/testify/require"
+)
+
+var legacyColumns = []string{
+	"content_id", "name", "slug", "namespace", "created_by", "updated_by",
+	"content", "created_at", "updated_at",
+}
+
+func TestReadLegacy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	dbh, mock, err := sqlmock.New()
+	assert.NoError(err, "expect no error from creating sql mock")
+	defer dbh.Close()
+	created := time.Date(2018, time.June, 12, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM content ORDER BY content_id").
+		WillReturnRows(
+			sqlmock.NewRows(legacyColumns).
+				AddRow(
+					14, "catalog", "dsc-catalog", "dsc", 1, 2,
+					`{"paragraph":"catalog"}`, created, created.Add(time.Hour),
+				).
+				AddRow(
+					27, "about", "dictybase-about", "dictybase", 1, 1,
+					`{"paragraph":"about"}`, created, nil,
+				),
+		)
+	lcnts, err := ReadLegacy(ctx, dbh, "content")
+	assert.NoError(err, "expect no error from reading legacy contents")
+	assert.Len(lcnts, 2, "expect two legacy contents")
+	assert.Equal(lcnts[0].ID, int64(14), "id should match")
+	assert.Equal(lcnts[0].Slug, "dsc-catalog", "slug should match")
+	assert.True(lcnts[0].UpdatedAt.Valid, "expect updated_at to be set")
+	assert.False(lcnts[1].UpdatedAt.Valid, "expect updated_at to be null")
+	assert.NoError(mock.ExpectationsWereMet(), "expect query to be run")
+	_, err = ReadLegacy(ctx, dbh, "content; DROP TABLE content")
+	assert.Error(err, "expect error for invalid table name")
+}
+
+func TestContentDoc(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	created := time.Date(2018, time.June, 12, 8, 0, 0, 0, time.UTC)
+	lcnt := &LegacyContent{
+		ID:        14,
+		Name:      "catalog",
+		Slug:      "dsc-catalog",
+		Namespace: "dsc",
+		CreatedBy: 1,
+		UpdatedBy: 2,
+		Content:   `{"paragraph":"catalog"}`,
+		CreatedAt: created,
+	}
+	usr := &UserEmails{Emails: map[int64]string{1: "content@content.org"}}
+	_, err := lcnt.ContentDoc(usr)
+	assert.Error(err, "expect error for unmapped user id")
+	usr.Default = "curator@dictybase.org"
+	cnt, err := lcnt.ContentDoc(usr)
+	assert.NoError(err, "expect no error from mapping legacy content")
+	assert.Equal(cnt.Key, "14", "key should be the legacy id")
+	assert.Equal(cnt.CreatedBy, "content@content.org", "should match created_by")
+	assert.Equal(cnt.UpdatedBy, "curator@dictybase.org", "should match updated_by")
+	assert.True(cnt.CreatedOn.Equal(created), "created_on should match")
+	assert.True(
+		cnt.UpdatedOn.Equal(created),
+		"updated_on should fall back to created_on",
+	)
+	lcnt.UpdatedAt = sql.NullTime{Time: created.Add(time.Hour), Valid: true}
+	cnt, err = lcnt.ContentDoc(usr)
+	assert.NoError(err, "expect no error from mapping legacy content")
+	assert.True(
+		cnt.UpdatedOn.Equal(created.Add(time.Hour)),
+		"updated_on should match",
+	)
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	created := time.Date(2018, time.June, 12, 8, 0, 0, 0, time.UTC)
+	lcnts := []*LegacyContent{
+		{
+			ID: 14, Name: "catalog", Slug: "dsc-catalog", Namespace: "dsc",
+			CreatedBy: 1, UpdatedBy: 1, Content: `{"paragraph":"catalog"}`,
+			CreatedAt: created,
+		},
+		{
+			ID: 27, Name: "about", Slug: "dictybase-about", Namespace: "dictybase",
+			CreatedBy: 1, UpdatedBy: 9, Content: `{"paragraph":"about"}`,
+			CreatedAt: created,
+		},
+	}
+	usr := &UserEmails{Emails: map[int64]string{1: "content@content.org"}}
+	repo := memory.NewContentRepo()
+	rpt := Migrate(ctx, repo, lcnts, usr, false)
+	assert.Equal(rpt.Source, 2, "expect two source rows")
+	assert.Equal(rpt.Migrated, 1, "expect one migrated content")
+	assert.Equal(rpt.Failed, 1, "expect one failure for unmapped user")
+	cnt, err := repo.GetContent(ctx, 14)
+	assert.NoError(err, "expect no error from getting migrated content")
+	assert.Equal(cnt.Slug, "dsc-catalog", "slug should be preserved")
+	assert.True(cnt.CreatedOn.Equal(created), "created_on should be preserved")
+	rpt = Migrate(ctx, repo, lcnts[:1], usr, false)
+	assert.Equal(rpt.Skipped, 1, "expect migrated content to be skipped")
+}
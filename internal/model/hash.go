@@ -0,0 +1,78 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// ContentHash is the hash of the content body, the stored one is used when
+// it is present. It is empty for a missing content.
+func ContentHash(cnt *ContentDoc) string {
+	if cnt == nil || cnt.NotFound {
+		return ""
+	}
+	if len(cnt.Hash) > 0 {
+		return cnt.Hash
+	}
+
+	return HashContent(cnt.Content)
+}
+
+// HashContent is the hex encoded sha256 of the normalized content. A json
+// content is normalized by sorting the keys and dropping the insignificant
+// whitespace so that a reformatted document hashes the same, any other
+// content is only trimmed.
+func HashContent(body string) string {
+	sum := sha256.Sum256([]byte(normalizeContent(body)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeContent(body string) string {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var val interface{}
+	if err := dec.Decode(&val); err != nil || dec.More() {
+		return strings.TrimSpace(body)
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return strings.TrimSpace(body)
+	}
+
+	return string(data)
+}
+
+// Duplicate is a group of contents with the same hash.
+type Duplicate struct {
+	Hash     string        `json:"hash"`
+	Contents []*ContentDoc `json:"contents"`
+}
+
+// FindDuplicates groups the contents by their hash, only the groups of more
+// than one content are returned. The groups are in the order of their first
+// content.
+func FindDuplicates(cnts []*ContentDoc) []*Duplicate {
+	groups := make(map[string]*Duplicate)
+	order := make([]string, 0)
+	for _, cnt := range cnts {
+		hash := ContentHash(cnt)
+		dup, ok := groups[hash]
+		if !ok {
+			dup = &Duplicate{Hash: hash}
+			groups[hash] = dup
+			order = append(order, hash)
+		}
+		dup.Contents = append(dup.Contents, cnt)
+	}
+	dups := make([]*Duplicate, 0)
+	for _, hash := range order {
+		if len(groups[hash].Contents) > 1 {
+			dups = append(dups, groups[hash])
+		}
+	}
+
+	return dups
+}
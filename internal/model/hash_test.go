@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashContent(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	hash := HashContent(
+		`{"paragraph": "dicty", "text": {"id": 12345678901234567890}}`,
+	)
+	assert.Len(hash, 64, "expect hex encoded sha256")
+	for _, body := range []string{
+		`{"text":{"id":12345678901234567890},"paragraph":"dicty"}`,
+		"\n{\n  \"paragraph\" : \"dicty\",\n" +
+			"  \"text\": {\"id\": 12345678901234567890}\n}\n",
+	} {
+		assert.Equal(HashContent(body), hash, "expect same hash of reformat")
+	}
+	assert.NotEqual(
+		HashContent(
+			`{"paragraph": "dicty", "text": {"id": 12345678901234567891}}`,
+		),
+		hash,
+		"expect different hash for changed number",
+	)
+	assert.Equal(
+		HashContent("  plain text\n"),
+		HashContent("plain text"),
+		"expect trimmed plain text",
+	)
+	assert.NotEqual(
+		HashContent(`{"a": 1} {"a": 1}`),
+		HashContent(`{"a":1}`),
+		"should not normalize a stream of json values",
+	)
+	assert.Empty(ContentHash(&ContentDoc{NotFound: true}), "expect no hash")
+	assert.Equal(
+		ContentHash(&ContentDoc{Content: "text", Hash: "stored"}),
+		"stored",
+		"should use the stored hash",
+	)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	cnts := []*ContentDoc{
+		{Slug: "about-dsc", Namespace: "dsc", Content: `{"a": 1}`},
+		{Slug: "intro-dsc", Namespace: "dsc", Content: `{"b": 1}`},
+		{Slug: "about-dicty", Namespace: "dicty", Content: `{"a":1}`},
+		{Slug: "faq-dsc", Namespace: "dsc", Content: `{"c": 1}`},
+		{Slug: "intro-genome", Namespace: "genome", Content: `{"b": 1}`},
+		{Slug: "about-genome", Namespace: "genome", Content: `{ "a": 1 }`},
+	}
+	dups := FindDuplicates(cnts)
+	assert.Len(dups, 2, "expect two groups of duplicates")
+	assert.Equal(dups[0].Hash, HashContent(`{"a":1}`), "should match hash")
+	assert.Equal(
+		[]*ContentDoc{cnts[0], cnts[2], cnts[5]},
+		dups[0].Contents,
+		"should group the about pages",
+	)
+	assert.Equal(
+		[]*ContentDoc{cnts[1], cnts[4]},
+		dups[1].Contents,
+		"should group the intro pages",
+	)
+	assert.Empty(FindDuplicates(cnts[3:4]), "expect no duplicates")
+}
@@ -1,23 +1,29 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
 	driver "github.com/arangodb/go-driver"
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
 )
 
 var noncharReg = regexp.MustCompile("[^a-z0-9]+")
 
 type ContentDoc struct {
 	driver.DocumentMeta
-	Name      string    `json:"name"       validate:"required"`
-	Slug      string    `json:"slug"       validate:"required"`
-	Namespace string    `json:"namespace"  validate:"required"`
-	CreatedBy string    `json:"created_by" validate:"email"`
-	UpdatedBy string    `json:"updated_by" validate:"required,email"`
-	Content   string    `json:"content"    validate:"required"`
+	Name      string `json:"name"       validate:"required"`
+	Slug      string `json:"slug"       validate:"required"`
+	Namespace string `json:"namespace"  validate:"required"`
+	CreatedBy string `json:"created_by" validate:"email"`
+	UpdatedBy string `json:"updated_by" validate:"required,email"`
+	Content   string `json:"content"    validate:"required"`
+	// Hash is the HashContent of the content, it is empty for a content
+	// stored before the hash was introduced
+	Hash      string    `json:"hash"`
 	CreatedOn time.Time `json:"created_on"`
 	UpdatedOn time.Time `json:"updated_on"`
 	NotFound  bool
@@ -31,6 +37,7 @@ func Schema() []byte {
 		    "namespace": {"type": "string"},
 		    "slug": {"type": "string"},
 		    "content": {"type": "string"},
+		    "hash": {"type": "string"},
 		    "created_by": {"type": "string", "format": "email"},
 		    "updated_by": {"type": "string", "format": "email"},
 	 	    "created_on": {"type": "string", "format": "date-time"},
@@ -48,6 +55,274 @@ func Schema() []byte {
 	`)
 }
 
+type NamespaceDoc struct {
+	driver.DocumentMeta
+	Name          string    `json:"name"           validate:"required"`
+	DisplayName   string    `json:"display_name"   validate:"required"`
+	EditorsGroup  string    `json:"editors_group"`
+	ContentSchema string    `json:"content_schema"`
+	Archived      bool      `json:"archived"`
+	CreatedBy     string    `json:"created_by"     validate:"required,email"`
+	CreatedOn     time.Time `json:"created_on"`
+	UpdatedOn     time.Time `json:"updated_on"`
+	NotFound      bool
+}
+
+func NamespaceSchema() []byte {
+	return []byte(`{
+		  "type": "object",
+		  "properties": {
+		    "name": {"type": "string"},
+		    "display_name": {"type": "string"},
+		    "editors_group": {"type": "string"},
+		    "content_schema": {"type": "string"},
+		    "archived": {"type": "boolean"},
+		    "created_by": {"type": "string", "format": "email"},
+	 	    "created_on": {"type": "string", "format": "date-time"},
+	 	    "updated_on": {"type": "string", "format": "date-time"}
+		  },
+		  "required": [
+			"name",
+			"display_name",
+			"archived",
+			"created_by",
+			"created_on"
+		   ]
+		}
+	`)
+}
+
+// ContentTransfer describes a bulk copy or move of contents from one
+// namespace to another.
+type ContentTransfer struct {
+	From string `validate:"required"`
+	To   string `validate:"required,nefield=From"`
+	// Slugs restricts the transfer to the given slugs, all contents of the
+	// namespace are transferred when it is empty
+	Slugs []string
+	// SlugPattern is a regular expression whose matches in the slug are
+	// replaced by SlugReplacement
+	SlugPattern     string
+	SlugReplacement string
+	Move            bool
+	UpdatedBy       string `validate:"required,email"`
+}
+
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation is a single create, update or delete of a content within a
+// batch. The ID is used by update and delete operations.
+type BatchOperation struct {
+	Action string `validate:"required,oneof=create update delete"`
+	ID     int64  `validate:"required_unless=Action create"`
+	Create *content.NewContentAttributes
+	Update *content.ExistingContentAttributes
+}
+
+// BatchResult is the outcome of a single batch operation. For delete
+// operation the Content holds the removed document.
+type BatchResult struct {
+	Action  string
+	Content *ContentDoc
+	Err     error
+}
+
+const (
+	AuditStore   = "store"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRename  = "rename"
+	AuditRestore = "restore"
+)
+
+// AuditEntry records a single mutation of a content. The hashes are the
+// ContentHash of the content before and after the mutation, empty when
+// there is no such content. The from slug and namespace are those of a
+// content before a rename, empty for the other operations.
+type AuditEntry struct {
+	driver.DocumentMeta
+	Actor         string            `json:"actor"       validate:"required"`
+	Operation     string            `json:"operation"   validate:"required,oneof=store update delete rename restore"`
+	ContentID     int64             `json:"content_id"`
+	Slug          string            `json:"slug"`
+	Namespace     string            `json:"namespace"`
+	FromSlug      string            `json:"from_slug"`
+	FromNamespace string            `json:"from_namespace"`
+	BeforeHash    string            `json:"before_hash"`
+	AfterHash     string            `json:"after_hash"`
+	Client        map[string]string `json:"client"`
+	CreatedOn     time.Time         `json:"created_on"`
+}
+
+func AuditSchema() []byte {
+	return []byte(`{
+		  "type": "object",
+		  "properties": {
+		    "actor": {"type": "string"},
+		    "operation": {"type": "string"},
+		    "content_id": {"type": "integer"},
+		    "slug": {"type": "string"},
+		    "namespace": {"type": "string"},
+		    "from_slug": {"type": "string"},
+		    "from_namespace": {"type": "string"},
+		    "before_hash": {"type": "string"},
+		    "after_hash": {"type": "string"},
+		    "client": {"type": "object"},
+	 	    "created_on": {"type": "string", "format": "date-time"}
+		  },
+		  "required": [
+			"actor",
+			"operation",
+			"content_id",
+			"created_on"
+		   ]
+		}
+	`)
+}
+
+// AuditFilter selects the audit entries, an empty field matches every
+// entry and zero Limit returns all of them. A rename matches the namespace
+// it moved from as well as the one it moved to.
+type AuditFilter struct {
+	Actor     string
+	Namespace string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+// Match reports whether the entry is selected by the filter, the time range
+// includes both ends.
+func (flt *AuditFilter) Match(ent *AuditEntry) bool {
+	switch {
+	case len(flt.Actor) > 0 && ent.Actor != flt.Actor:
+		return false
+	case len(flt.Namespace) > 0 && ent.Namespace != flt.Namespace &&
+		ent.FromNamespace != flt.Namespace:
+		return false
+	case !flt.From.IsZero() && ent.CreatedOn.Before(flt.From):
+		return false
+	case !flt.To.IsZero() && ent.CreatedOn.After(flt.To):
+		return false
+	}
+
+	return true
+}
+
+const (
+	LockAcquire = "acquire"
+	LockRenew   = "renew"
+	LockRelease = "release"
+	LockBreak   = "break"
+)
+
+// LockDoc is the edit lease of a content, it lapses at ExpiresOn unless it
+// is renewed by the holder.
+type LockDoc struct {
+	driver.DocumentMeta
+	ContentID  int64     `json:"content_id"`
+	Holder     string    `json:"holder"`
+	AcquiredOn time.Time `json:"acquired_on"`
+	ExpiresOn  time.Time `json:"expires_on"`
+	NotFound   bool
+}
+
+// Lapsed reports whether the lease has run out by the given time.
+func (lck *LockDoc) Lapsed(now time.Time) bool {
+	return !now.Before(lck.ExpiresOn)
+}
+
+// LockEvent is published for every change of an edit lock, Actor is the
+// user who made the change which differs from the holder for a break.
+type LockEvent struct {
+	Action    string    `json:"action"`
+	ContentID int64     `json:"content_id"`
+	Holder    string    `json:"holder"`
+	Actor     string    `json:"actor"`
+	ExpiresOn time.Time `json:"expires_on"`
+}
+
+// Content wraps the event for publishing like any other content event, the
+// event is kept in json as the content and the actor as its updater.
+func (evt *LockEvent) Content() (*content.Content, error) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return nil, fmt.Errorf("error in encoding lock event %s", err)
+	}
+
+	return &content.Content{
+		Data: &content.ContentData{
+			Type: "contents",
+			Id:   evt.ContentID,
+			Attributes: &content.ContentAttributes{
+				Content:   string(data),
+				UpdatedBy: evt.Actor,
+			},
+		},
+	}, nil
+}
+
+// LockEventFromContent unwraps the lock event of a published content.
+func LockEventFromContent(cont *content.Content) (*LockEvent, error) {
+	evt := &LockEvent{}
+	err := json.Unmarshal(
+		[]byte(cont.GetData().GetAttributes().GetContent()),
+		evt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in decoding lock event %s", err)
+	}
+
+	return evt, nil
+}
+
+// AssetDoc is the metadata of an uploaded file, the file itself is kept in
+// the blob store under BlobKey.
+type AssetDoc struct {
+	driver.DocumentMeta
+	Name      string    `json:"name"       validate:"required"`
+	MimeType  string    `json:"mime_type"  validate:"required"`
+	Size      int64     `json:"size"`
+	Checksum  string    `json:"checksum"   validate:"required"`
+	BlobKey   string    `json:"blob_key"   validate:"required"`
+	CreatedBy string    `json:"created_by" validate:"required,email"`
+	CreatedOn time.Time `json:"created_on"`
+	// References are the ids of the contents that embed the asset
+	References []int64 `json:"references"`
+	NotFound   bool
+}
+
+// LinkDoc is an internal link of a content to the content of another slug,
+// the target might not exist.
+type LinkDoc struct {
+	driver.DocumentMeta
+	ContentID int64  `json:"content_id"`
+	Slug      string `json:"slug"`
+	Namespace string `json:"namespace"`
+	Target    string `json:"target"`
+}
+
+// SlugRewriter returns a function that replaces every match of the pattern
+// in a slug with the replacement. For an empty pattern the slug is returned
+// unchanged.
+func SlugRewriter(pattern, replacement string) (func(string) string, error) {
+	if len(pattern) == 0 {
+		return func(slug string) string { return slug }, nil
+	}
+	reg, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error in compiling slug pattern %s", err)
+	}
+
+	return func(slug string) string {
+		return reg.ReplaceAllString(slug, replacement)
+	}, nil
+}
+
 func Slugify(name string) string {
 	return strings.Trim(
 		noncharReg.ReplaceAllString(strings.ToLower(name), "-"),
@@ -79,10 +79,11 @@ func NewContentRepo(
 }
 
 func (arp *arangorepository) GetContentBySlug(
+	ctx context.Context,
 	slug string,
 ) (*model.ContentDoc, error) {
-	cntModel := &model.ContentDoc{}
-	resp, err := arp.database.GetRow(
+	cntModels, err := arp.queryContents(
+		ctx,
 		ContentFindBySlug,
 		map[string]interface{}{
 			"@content_collection": arp.content.Name(),
@@ -90,37 +91,34 @@ func (arp *arangorepository) GetContentBySlug(
 		},
 	)
 	if err != nil {
-		return cntModel, fmt.Errorf(
+		return &model.ContentDoc{}, fmt.Errorf(
 			"error in getting content by slug name %s",
 			err,
 		)
 	}
-	if resp.IsEmpty() {
-		cntModel.NotFound = true
-
-		return cntModel, nil
-	}
-	if err := resp.Read(cntModel); err != nil {
-		return cntModel, fmt.Errorf(
-			"error in reading response to struct %s",
-			err,
-		)
+	if len(cntModels) == 0 {
+		return &model.ContentDoc{NotFound: true}, nil
 	}
 
-	return cntModel, nil
+	return cntModels[0], nil
 }
 
-func (arp *arangorepository) GetContent(cid int64) (*model.ContentDoc, error) {
+func (arp *arangorepository) GetContent(
+	ctx context.Context,
+	cid int64,
+) (*model.ContentDoc, error) {
 	cntModel := &model.ContentDoc{}
-	cntCollection, err := arp.database.Collection(arp.content.Name())
-	if err != nil {
-		return cntModel, fmt.Errorf("error in getting collection %s", err)
-	}
-	meta, err := cntCollection.ReadDocument(
-		context.Background(),
+	ctx, span := startSpan(ctx, "ContentRead")
+	meta, err := arp.content.ReadDocument(
+		ctx,
 		strconv.Itoa(int(cid)),
 		cntModel,
 	)
+	if driver.IsNotFoundGeneral(err) {
+		endSpan(span, nil)
+	} else {
+		endSpan(span, err)
+	}
 	if err != nil {
 		if driver.IsNotFoundGeneral(err) {
 			cntModel.NotFound = true
@@ -136,15 +134,13 @@ func (arp *arangorepository) GetContent(cid int64) (*model.ContentDoc, error) {
 	return cntModel, nil
 }
 
-func (arp *arangorepository) DeleteContent(cid int64) error {
-	cntCollection, err := arp.database.Collection(arp.content.Name())
-	if err != nil {
-		return fmt.Errorf("error in getting collection %s", err)
-	}
-	_, err = cntCollection.RemoveDocument(
-		context.Background(),
-		strconv.Itoa(int(cid)),
-	)
+func (arp *arangorepository) DeleteContent(
+	ctx context.Context,
+	cid int64,
+) error {
+	ctx, span := startSpan(ctx, "ContentRemove")
+	_, err := arp.content.RemoveDocument(ctx, strconv.Itoa(int(cid)))
+	endSpan(span, err)
 	if err != nil {
 		errMsg := fmt.Sprintf("error in reading document %s", err)
 		if driver.IsNotFoundGeneral(err) {
@@ -158,10 +154,11 @@ func (arp *arangorepository) DeleteContent(cid int64) error {
 }
 
 func (arp *arangorepository) AddContent(
+	ctx context.Context,
 	cattr *content.NewContentAttributes,
 ) (*model.ContentDoc, error) {
-	cntModel := &model.ContentDoc{}
-	res, err := arp.database.DoRun(
+	cntModel, err := arp.queryContent(
+		ctx,
 		ContentInsert,
 		map[string]interface{}{
 			"name":                cattr.Name,
@@ -169,16 +166,14 @@ func (arp *arangorepository) AddContent(
 			"created_by":          cattr.CreatedBy,
 			"updated_by":          cattr.CreatedBy,
 			"content":             cattr.Content,
+			"hash":                model.HashContent(cattr.Content),
 			"slug":                cattr.Slug,
 			"@content_collection": arp.content.Name(),
 		},
 	)
 	if err != nil {
-		return cntModel, fmt.Errorf("error in creating new content %s", err)
-	}
-	if err := res.Read(cntModel); err != nil {
-		return cntModel, fmt.Errorf(
-			"error in reading the model to struct %s",
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in creating new content %s",
 			err,
 		)
 	}
@@ -187,25 +182,24 @@ func (arp *arangorepository) AddContent(
 }
 
 func (arp *arangorepository) EditContent(
+	ctx context.Context,
 	cid int64,
 	cattr *content.ExistingContentAttributes,
 ) (*model.ContentDoc, error) {
-	cntModel := &model.ContentDoc{}
-	res, err := arp.database.DoRun(
+	cntModel, err := arp.queryContent(
+		ctx,
 		ContentUpdate,
 		map[string]interface{}{
 			"key":                 strconv.FormatInt(cid, 10),
 			"updated_by":          cattr.UpdatedBy,
 			"content":             cattr.Content,
+			"hash":                model.HashContent(cattr.Content),
 			"@content_collection": arp.content.Name(),
 		},
 	)
 	if err != nil {
-		return cntModel, fmt.Errorf("error in updating content %s", err)
-	}
-	if err := res.Read(cntModel); err != nil {
-		return cntModel, fmt.Errorf(
-			"error in reading the model to struct %s",
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in updating content %s",
 			err,
 		)
 	}
@@ -213,6 +207,37 @@ func (arp *arangorepository) EditContent(
 	return cntModel, nil
 }
 
-func (arp *arangorepository) Dbh() *manager.Database {
-	return arp.database
+// Drop removes the database along with all of its collections.
+func (arp *arangorepository) Drop() error {
+	if err := arp.database.Drop(); err != nil {
+		return fmt.Errorf("error in dropping database %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database is reachable.
+func (arp *arangorepository) Ping(ctx context.Context) error {
+	if _, err := arp.database.Handler().Info(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close is a no-op as the connections are managed by the driver.
+func (arp *arangorepository) Close() error {
+	return nil
+}
+
+// Stats reports the number of documents in the content collection.
+func (arp *arangorepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	count, err := arp.content.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting documents %s", err)
+	}
+
+	return &repository.Stats{Backend: "arangodb", Records: count}, nil
 }
@@ -1,6 +1,7 @@
 package arangodb
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/dictyBase/go-genproto/dictybaseapis/content"
 	"github.com/dictyBase/modware-content/internal/model"
 	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/repository/conformance"
 	"github.com/dictyBase/modware-content/internal/testutils"
 	"github.com/stretchr/testify/require"
 )
@@ -42,14 +44,17 @@ func setUp(t *testing.T) (*require.Assertions, repository.ContentRepository) {
 }
 
 func tearDown(repo repository.ContentRepository) {
-	_ = repo.Dbh().Drop()
+	_ = repo.Drop()
 }
 
 func TestAddContent(t *testing.T) {
 	t.Parallel()
 	assert, repo := setUp(t)
 	defer tearDown(repo)
-	nct, err := repo.AddContent(testutils.NewStoreContent("catalog", "dsc"))
+	nct, err := repo.AddContent(
+		context.Background(),
+		testutils.NewStoreContent("catalog", "dsc"),
+	)
 	assert.NoErrorf(err, "expect no error from creating content %s", err)
 	assert.Equal(nct.Name, "catalog", "name should match")
 	assert.Equal(nct.Namespace, "dsc", "namespace should match")
@@ -78,20 +83,22 @@ func TestAddContent(t *testing.T) {
 
 func TestGetContentBySlug(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	assert, repo := setUp(t)
 	defer tearDown(repo)
-	nct, err := repo.AddContent(testutils.NewStoreContent("catalog", "dsc"))
+	nct, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
 	assert.NoErrorf(err, "expect no error from creating content %s", err)
-	sct, err := repo.GetContentBySlug(nct.Slug)
+	sct, err := repo.GetContentBySlug(ctx, nct.Slug)
 	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
 	testContentProperties(assert, sct, nct)
 }
 
 func TestGetContent(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	assert, repo := setUp(t)
 	defer tearDown(repo)
-	nct, err := repo.AddContent(testutils.NewStoreContent("catalog", "dsc"))
+	nct, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
 	assert.NoErrorf(err, "expect no error from creating content %s", err)
 	key, err := strconv.ParseInt(nct.Key, 10, 64)
 	assert.NoErrorf(
@@ -99,16 +106,17 @@ func TestGetContent(t *testing.T) {
 		"expect no error from string to int64 conversion of key %s",
 		err,
 	)
-	sct, err := repo.GetContent(key)
+	sct, err := repo.GetContent(ctx, key)
 	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
 	testContentProperties(assert, sct, nct)
 }
 
 func TestDeleteContent(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	assert, repo := setUp(t)
 	defer tearDown(repo)
-	nct, err := repo.AddContent(testutils.NewStoreContent("catalog", "dsc"))
+	nct, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
 	assert.NoErrorf(err, "expect no error from creating content %s", err)
 	key, err := strconv.ParseInt(nct.Key, 10, 64)
 	assert.NoErrorf(
@@ -116,22 +124,23 @@ func TestDeleteContent(t *testing.T) {
 		"expect no error from string to int64 conversion of key %s",
 		err,
 	)
-	err = repo.DeleteContent(key)
+	err = repo.DeleteContent(ctx, key)
 	assert.NoErrorf(
 		err,
 		"expect no error from deleting content by slug %s",
 		err,
 	)
-	ecnt, err := repo.GetContent(key)
+	ecnt, err := repo.GetContent(ctx, key)
 	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
 	assert.True(ecnt.NotFound, "expect no record to be found")
 }
 
 func TestEditContent(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	assert, repo := setUp(t)
 	defer tearDown(repo)
-	nct, err := repo.AddContent(testutils.NewStoreContent("catalog", "dsc"))
+	nct, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
 	assert.NoErrorf(err, "expect no error from creating content %s", err)
 	key, err := strconv.ParseInt(nct.Key, 10, 64)
 	assert.NoErrorf(
@@ -144,6 +153,7 @@ func TestEditContent(t *testing.T) {
 		Text:      "jack",
 	})
 	sct, err := repo.EditContent(
+		ctx,
 		key,
 		&content.ExistingContentAttributes{
 			UpdatedBy: "packer@packer.com",
@@ -169,15 +179,16 @@ func TestEditContent(t *testing.T) {
 
 func TestSchemaValidation(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	assert, repo := setUp(t)
 	defer tearDown(repo)
-	_, err := repo.AddContent(testutils.NewStoreContent("catalog", "dsc"))
+	_, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
 	assert.NoErrorf(err, "expect no error from creating content %s", err)
-	_, err = repo.AddContent(testutils.NewStoreContent("catalog", "dsc"))
+	_, err = repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
 	assert.Error(err, "expect schema validation error for duplicate slug")
 	ncnt := testutils.NewStoreContent("price", "dsc")
 	ncnt.CreatedBy = "yadayadayada"
-	_, err = repo.AddContent(ncnt)
+	_, err = repo.AddContent(ctx, ncnt)
 	assert.Error(
 		err,
 		"expect schema validation error for created by field does not have an email address",
@@ -206,3 +217,105 @@ func testContentProperties(
 	)
 	assert.Equal(sct.Content, nct.Content, "should match raw conent")
 }
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	conformance.RunAll(t, func(t *testing.T) *conformance.Repositories {
+		t.Helper()
+		assert := require.New(t)
+		tra, err := testarango.NewTestArangoFromEnv(true)
+		if err != nil {
+			t.Fatalf("unable to construct new TestArango instance %s", err)
+		}
+		connP := &manager.ConnectParams{
+			User:     tra.User,
+			Pass:     tra.Pass,
+			Database: tra.Database,
+			Host:     tra.Host,
+			Port:     tra.Port,
+			Istls:    false,
+		}
+		content := manager.RandomString(16, 19)
+		repo, err := NewContentRepo(connP, content)
+		assert.NoErrorf(err, "expect no error from content repository %s", err)
+		aud, err := NewAuditRepo(connP, manager.RandomString(16, 19))
+		assert.NoErrorf(err, "expect no error from audit repository %s", err)
+		lck, err := NewLockRepo(connP, manager.RandomString(16, 19))
+		assert.NoErrorf(err, "expect no error from lock repository %s", err)
+		ast, err := NewAssetRepo(connP, manager.RandomString(16, 19))
+		assert.NoErrorf(err, "expect no error from asset repository %s", err)
+		lnk, err := NewLinkRepo(connP, manager.RandomString(16, 19), content)
+		assert.NoErrorf(err, "expect no error from link repository %s", err)
+		t.Cleanup(func() {
+			for _, lcl := range []repository.Lifecycle{lnk, ast, lck, aud, repo} {
+				_ = lcl.Drop()
+			}
+		})
+
+		return &conformance.Repositories{
+			Content: repo,
+			Audit:   aud,
+			Locks:   lck,
+			Assets:  ast,
+			Links:   lnk,
+		}
+	})
+}
+
+func TestLinkEdges(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	tra, err := testarango.NewTestArangoFromEnv(true)
+	if err != nil {
+		t.Fatalf("unable to construct new TestArango instance %s", err)
+	}
+	connP := &manager.ConnectParams{
+		User:     tra.User,
+		Pass:     tra.Pass,
+		Database: tra.Database,
+		Host:     tra.Host,
+		Port:     tra.Port,
+		Istls:    false,
+	}
+	ctx := context.Background()
+	collection := manager.RandomString(16, 19)
+	repo, err := NewContentRepo(connP, collection)
+	assert.NoErrorf(err, "expect no error from content repository %s", err)
+	defer tearDown(repo)
+	lnk, err := NewLinkRepo(connP, manager.RandomString(16, 19), collection)
+	assert.NoErrorf(err, "expect no error from link repository %s", err)
+	lrp, ok := lnk.(*linkrepository)
+	assert.True(ok, "expect arangodb link repository")
+	edgeTo := func() string {
+		edges, err := queryDocuments[struct {
+			To string `json:"_to"`
+		}](
+			ctx,
+			lrp.database.Handler(),
+			"FOR lnk IN @@link_collection RETURN lnk",
+			map[string]interface{}{"@link_collection": lrp.link.Name()},
+		)
+		assert.NoErrorf(err, "expect no error from listing edges %s", err)
+		assert.Len(edges, 1, "expect a single edge")
+
+		return edges[0].To
+	}
+	err = lnk.SetLinks(
+		ctx,
+		&model.LinkDoc{ContentID: 1, Slug: "about-dsc", Namespace: "dsc"},
+		[]string{"order-dsc"},
+	)
+	assert.NoErrorf(err, "expect no error from setting links %s", err)
+	assert.Equal(edgeTo(), lrp.missing(), "expect edge to a missing target")
+	mcont, err := repo.AddContent(ctx, testutils.NewStoreContent("order", "dsc"))
+	assert.NoErrorf(err, "expect no error from adding content %s", err)
+	oid, _ := strconv.ParseInt(mcont.Key, 10, 64)
+	target := &model.LinkDoc{ContentID: oid, Slug: mcont.Slug, Namespace: "dsc"}
+	err = lnk.SetLinks(ctx, target, nil)
+	assert.NoErrorf(err, "expect no error from setting links %s", err)
+	assert.Equal(edgeTo(), lrp.handle(oid), "expect edge to the new target")
+	assert.NoError(repo.DeleteContent(ctx, oid), "expect no error from delete")
+	err = lnk.SetLinks(ctx, target, nil)
+	assert.NoErrorf(err, "expect no error from setting links %s", err)
+	assert.Equal(edgeTo(), lrp.missing(), "expect edge to a missing target")
+}
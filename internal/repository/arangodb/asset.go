@@ -0,0 +1,204 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	driver "github.com/arangodb/go-driver"
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type assetrepository struct {
+	sess     *manager.Session
+	database *manager.Database
+	asset    driver.Collection
+	validate *validator.Validate
+}
+
+// NewAssetRepo creates the asset repository. The ids of the embedding
+// contents are kept in the references array of every asset.
+func NewAssetRepo(
+	connP *manager.ConnectParams,
+	collection string,
+) (repository.AssetRepository, error) {
+	arp := &assetrepository{validate: validator.New()}
+	sess, dbs, err := manager.NewSessionDb(connP)
+	if err != nil {
+		return arp, fmt.Errorf("error in getting new session %s", err)
+	}
+	arp.sess = sess
+	arp.database = dbs
+	assetCollection, err := dbs.FindOrCreateCollection(
+		collection,
+		&driver.CreateCollectionOptions{},
+	)
+	if err != nil {
+		return arp, fmt.Errorf(
+			"error in finding or creating collection %s",
+			err,
+		)
+	}
+	arp.asset = assetCollection
+	_, _, err = dbs.EnsurePersistentIndex(
+		collection,
+		[]string{"references[*]"},
+		&driver.EnsurePersistentIndexOptions{
+			InBackground: true,
+			Name:         "asset_references_idx",
+		},
+	)
+	if err != nil {
+		return arp, fmt.Errorf(
+			"error in creating index for references field %s",
+			err,
+		)
+	}
+
+	return arp, nil
+}
+
+func (arp *assetrepository) AddAsset(
+	ctx context.Context,
+	ast *model.AssetDoc,
+) (*model.AssetDoc, error) {
+	if err := arp.validate.Struct(ast); err != nil {
+		return &model.AssetDoc{}, fmt.Errorf(
+			"error in validating asset %s",
+			err,
+		)
+	}
+	astModels, err := queryDocuments[model.AssetDoc](
+		ctx,
+		arp.database.Handler(),
+		AssetInsert,
+		map[string]interface{}{
+			"name":              ast.Name,
+			"mime_type":         ast.MimeType,
+			"size":              ast.Size,
+			"checksum":          ast.Checksum,
+			"blob_key":          ast.BlobKey,
+			"created_by":        ast.CreatedBy,
+			"@asset_collection": arp.asset.Name(),
+		},
+	)
+	if err != nil {
+		return &model.AssetDoc{}, fmt.Errorf("error in adding asset %s", err)
+	}
+
+	return astModels[0], nil
+}
+
+func (arp *assetrepository) GetAsset(
+	ctx context.Context,
+	aid int64,
+) (*model.AssetDoc, error) {
+	astModels, err := queryDocuments[model.AssetDoc](
+		ctx,
+		arp.database.Handler(),
+		AssetFind,
+		map[string]interface{}{
+			"key":               strconv.FormatInt(aid, 10),
+			"@asset_collection": arp.asset.Name(),
+		},
+	)
+	if err != nil {
+		return &model.AssetDoc{}, fmt.Errorf(
+			"error in finding asset %d %s",
+			aid,
+			err,
+		)
+	}
+	if len(astModels) == 0 {
+		return &model.AssetDoc{NotFound: true}, nil
+	}
+
+	return astModels[0], nil
+}
+
+func (arp *assetrepository) RemoveAsset(ctx context.Context, aid int64) error {
+	astModels, err := queryDocuments[model.AssetDoc](
+		ctx,
+		arp.database.Handler(),
+		AssetRemove,
+		map[string]interface{}{
+			"key":               strconv.FormatInt(aid, 10),
+			"@asset_collection": arp.asset.Name(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error in removing asset %d %s", aid, err)
+	}
+	if len(astModels) == 0 {
+		return fmt.Errorf("error in removing asset %d, not found", aid)
+	}
+
+	return nil
+}
+
+func (arp *assetrepository) SetReferences(
+	ctx context.Context,
+	cid int64,
+	aids []int64,
+) error {
+	if aids == nil {
+		aids = make([]int64, 0)
+	}
+	_, err := queryDocuments[model.AssetDoc](
+		ctx,
+		arp.database.Handler(),
+		AssetReference,
+		map[string]interface{}{
+			"content_id":        cid,
+			"aids":              aids,
+			"@asset_collection": arp.asset.Name(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error in setting asset references of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Drop removes the database along with all of its collections.
+func (arp *assetrepository) Drop() error {
+	if err := arp.database.Drop(); err != nil {
+		return fmt.Errorf("error in dropping database %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database is reachable.
+func (arp *assetrepository) Ping(ctx context.Context) error {
+	if _, err := arp.database.Handler().Info(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close is a no-op as the connections are managed by the driver.
+func (arp *assetrepository) Close() error {
+	return nil
+}
+
+// Stats reports the number of documents in the asset collection.
+func (arp *assetrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	count, err := arp.asset.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting documents %s", err)
+	}
+
+	return &repository.Stats{Backend: "arangodb", Records: count}, nil
+}
@@ -0,0 +1,185 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	driver "github.com/arangodb/go-driver"
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type auditrepository struct {
+	sess     *manager.Session
+	database *manager.Database
+	audit    driver.Collection
+	validate *validator.Validate
+}
+
+// NewAuditRepo creates the audit repository. The collection is only ever
+// inserted into, the indexes back the filters of ListEntries.
+func NewAuditRepo(
+	connP *manager.ConnectParams,
+	collection string,
+) (repository.AuditRepository, error) {
+	aud := &auditrepository{validate: validator.New()}
+	sess, dbs, err := manager.NewSessionDb(connP)
+	if err != nil {
+		return aud, fmt.Errorf("error in getting new session %s", err)
+	}
+	aud.sess = sess
+	aud.database = dbs
+	schemaOptions := &driver.CollectionSchemaOptions{}
+	if err := schemaOptions.LoadRule(model.AuditSchema()); err != nil {
+		return aud, fmt.Errorf("error in loading schema %s", err)
+	}
+	auditCollection, err := dbs.FindOrCreateCollection(
+		collection,
+		&driver.CreateCollectionOptions{Schema: schemaOptions},
+	)
+	if err != nil {
+		return aud, fmt.Errorf(
+			"error in finding or creating collection %s",
+			err,
+		)
+	}
+	aud.audit = auditCollection
+	for name, fields := range map[string][]string{
+		"audit_actor_idx":          {"actor", "created_on"},
+		"audit_namespace_idx":      {"namespace", "created_on"},
+		"audit_from_namespace_idx": {"from_namespace", "created_on"},
+	} {
+		_, _, err = dbs.EnsurePersistentIndex(
+			collection,
+			fields,
+			&driver.EnsurePersistentIndexOptions{
+				InBackground: true,
+				Name:         name,
+			},
+		)
+		if err != nil {
+			return aud, fmt.Errorf("error in creating index %s %s", name, err)
+		}
+	}
+
+	return aud, nil
+}
+
+func (aud *auditrepository) AddEntry(
+	ctx context.Context,
+	ent *model.AuditEntry,
+) (*model.AuditEntry, error) {
+	if err := aud.validate.Struct(ent); err != nil {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in adding audit entry %s",
+			err,
+		)
+	}
+	client := ent.Client
+	if client == nil {
+		client = make(map[string]string)
+	}
+	entModels, err := queryDocuments[model.AuditEntry](
+		ctx,
+		aud.database.Handler(),
+		AuditInsert,
+		map[string]interface{}{
+			"actor":             ent.Actor,
+			"operation":         ent.Operation,
+			"content_id":        ent.ContentID,
+			"slug":              ent.Slug,
+			"namespace":         ent.Namespace,
+			"from_slug":         ent.FromSlug,
+			"from_namespace":    ent.FromNamespace,
+			"before_hash":       ent.BeforeHash,
+			"after_hash":        ent.AfterHash,
+			"client":            client,
+			"@audit_collection": aud.audit.Name(),
+		},
+	)
+	if err != nil {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in adding audit entry %s",
+			err,
+		)
+	}
+	if len(entModels) == 0 {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in adding audit entry, no document returned",
+		)
+	}
+
+	return entModels[0], nil
+}
+
+func (aud *auditrepository) ListEntries(
+	ctx context.Context,
+	flt *model.AuditFilter,
+) ([]*model.AuditEntry, error) {
+	bindVars := map[string]interface{}{
+		"actor":             flt.Actor,
+		"namespace":         flt.Namespace,
+		"from":              nil,
+		"to":                nil,
+		"limit":             flt.Limit,
+		"@audit_collection": aud.audit.Name(),
+	}
+	if !flt.From.IsZero() {
+		bindVars["from"] = flt.From.UnixMilli()
+	}
+	if !flt.To.IsZero() {
+		bindVars["to"] = flt.To.UnixMilli()
+	}
+	if flt.Limit == 0 {
+		bindVars["limit"] = math.MaxInt32
+	}
+	entModels, err := queryDocuments[model.AuditEntry](
+		ctx,
+		aud.database.Handler(),
+		AuditList,
+		bindVars,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in listing audit entries %s", err)
+	}
+
+	return entModels, nil
+}
+
+// Drop removes the database along with all of its collections.
+func (aud *auditrepository) Drop() error {
+	if err := aud.database.Drop(); err != nil {
+		return fmt.Errorf("error in dropping database %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database is reachable.
+func (aud *auditrepository) Ping(ctx context.Context) error {
+	if _, err := aud.database.Handler().Info(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close is a no-op as the connections are managed by the driver.
+func (aud *auditrepository) Close() error {
+	return nil
+}
+
+// Stats reports the number of documents in the audit collection.
+func (aud *auditrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	count, err := aud.audit.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting documents %s", err)
+	}
+
+	return &repository.Stats{Backend: "arangodb", Records: count}, nil
+}
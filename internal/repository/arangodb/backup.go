@@ -0,0 +1,101 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+)
+
+// ListContents returns all contents of the given namespaces, or of the
+// entire collection when no namespace is given.
+func (arp *arangorepository) ListContents(
+	ctx context.Context,
+	namespaces []string,
+) ([]*model.ContentDoc, error) {
+	if namespaces == nil {
+		namespaces = make([]string, 0)
+	}
+
+	return arp.queryContents(
+		ctx,
+		ContentList,
+		map[string]interface{}{
+			"@content_collection": arp.content.Name(),
+			"namespaces":          namespaces,
+		},
+	)
+}
+
+// ImportContent stores the content with its key and timestamps intact. An
+// existing content with the same key is replaced only with overwrite, the
+// returned flag reports whether the content was written.
+func (arp *arangorepository) ImportContent(
+	ctx context.Context,
+	cnt *model.ContentDoc,
+	overwrite bool,
+) (*model.ContentDoc, bool, error) {
+	if !overwrite {
+		cid, err := strconv.ParseInt(cnt.Key, 10, 64)
+		if err != nil {
+			return cnt, false, fmt.Errorf("error in parsing key %s", err)
+		}
+		exist, err := arp.GetContent(ctx, cid)
+		if err != nil {
+			return cnt, false, err
+		}
+		if !exist.NotFound {
+			return exist, false, nil
+		}
+	}
+	cntModel, err := arp.queryContent(
+		ctx,
+		ContentUpsert,
+		map[string]interface{}{
+			"@content_collection": arp.content.Name(),
+			"key":                 cnt.Key,
+			"name":                cnt.Name,
+			"slug":                cnt.Slug,
+			"namespace":           cnt.Namespace,
+			"created_by":          cnt.CreatedBy,
+			"updated_by":          cnt.UpdatedBy,
+			"content":             cnt.Content,
+			"hash":                model.HashContent(cnt.Content),
+			"created_on":          cnt.CreatedOn.Format(time.RFC3339Nano),
+			"updated_on":          cnt.UpdatedOn.Format(time.RFC3339Nano),
+		},
+	)
+	if err != nil {
+		return cnt, false, fmt.Errorf("error in importing content %s", err)
+	}
+
+	return cntModel, true, nil
+}
+
+type namespaceCount struct {
+	Namespace string `json:"namespace"`
+	Total     int64  `json:"total"`
+}
+
+// CountByNamespace returns the number of contents in every namespace.
+func (arp *arangorepository) CountByNamespace(
+	ctx context.Context,
+) (map[string]int64, error) {
+	rows, err := queryDocuments[namespaceCount](
+		ctx,
+		arp.database.Handler(),
+		ContentCountByNamespace,
+		map[string]interface{}{"@content_collection": arp.content.Name()},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting contents %s", err)
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Namespace] = row.Total
+	}
+
+	return counts, nil
+}
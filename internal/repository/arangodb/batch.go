@@ -0,0 +1,128 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/dictyBase/modware-content/internal/model"
+)
+
+// BatchContents runs a list of create, update and delete operations. In
+// atomic mode all of them run in a single stream transaction and the first
+// failure rolls back the entire batch. Otherwise every operation is run on
+// its own and its failure is recorded in the corresponding result.
+func (arp *arangorepository) BatchContents(
+	ctx context.Context,
+	ops []*model.BatchOperation,
+	atomic bool,
+) ([]*model.BatchResult, error) {
+	if !atomic {
+		results := make([]*model.BatchResult, 0, len(ops))
+		for _, bop := range ops {
+			cntModel, err := arp.runOperation(ctx, bop)
+			results = append(results, &model.BatchResult{
+				Action:  bop.Action,
+				Content: cntModel,
+				Err:     err,
+			})
+		}
+
+		return results, nil
+	}
+	dbh := arp.database.Handler()
+	tid, err := dbh.BeginTransaction(
+		ctx,
+		driver.TransactionCollections{Write: []string{arp.content.Name()}},
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in starting transaction %s", err)
+	}
+	tctx := driver.WithTransactionID(ctx, tid)
+	results := make([]*model.BatchResult, 0, len(ops))
+	for idx, bop := range ops {
+		cntModel, err := arp.runOperation(tctx, bop)
+		if err != nil {
+			//nolint:errcheck
+			dbh.AbortTransaction(ctx, tid, nil)
+
+			return nil, fmt.Errorf("error in batch operation %d %s", idx, err)
+		}
+		results = append(results, &model.BatchResult{
+			Action:  bop.Action,
+			Content: cntModel,
+		})
+	}
+	if err := dbh.CommitTransaction(ctx, tid, nil); err != nil {
+		return nil, fmt.Errorf("error in committing transaction %s", err)
+	}
+
+	return results, nil
+}
+
+func (arp *arangorepository) runOperation(
+	ctx context.Context,
+	bop *model.BatchOperation,
+) (*model.ContentDoc, error) {
+	switch bop.Action {
+	case model.BatchCreate:
+		return arp.queryContent(ctx, ContentInsert, map[string]interface{}{
+			"name":                bop.Create.Name,
+			"namespace":           bop.Create.Namespace,
+			"created_by":          bop.Create.CreatedBy,
+			"updated_by":          bop.Create.CreatedBy,
+			"content":             bop.Create.Content,
+			"hash":                model.HashContent(bop.Create.Content),
+			"slug":                bop.Create.Slug,
+			"@content_collection": arp.content.Name(),
+		})
+	case model.BatchUpdate:
+		return arp.queryContent(ctx, ContentUpdate, map[string]interface{}{
+			"key":                 strconv.FormatInt(bop.ID, 10),
+			"updated_by":          bop.Update.UpdatedBy,
+			"content":             bop.Update.Content,
+			"hash":                model.HashContent(bop.Update.Content),
+			"@content_collection": arp.content.Name(),
+		})
+	case model.BatchDelete:
+		cntModel := &model.ContentDoc{}
+		ctx, span := startSpan(ctx, "ContentRemove")
+		_, err := arp.content.RemoveDocument(
+			driver.WithReturnOld(ctx, cntModel),
+			strconv.FormatInt(bop.ID, 10),
+		)
+		endSpan(span, err)
+		if err != nil {
+			if driver.IsNotFoundGeneral(err) {
+				return cntModel, fmt.Errorf(
+					"document with ID %d not found",
+					bop.ID,
+				)
+			}
+
+			return cntModel, fmt.Errorf("error in removing document %s", err)
+		}
+
+		return cntModel, nil
+	}
+
+	return nil, fmt.Errorf("unknown batch action %s", bop.Action)
+}
+
+func (arp *arangorepository) queryContent(
+	ctx context.Context,
+	query string,
+	bindVars map[string]interface{},
+) (*model.ContentDoc, error) {
+	cntModels, err := arp.queryContents(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	if len(cntModels) == 0 {
+		return nil, fmt.Errorf("no document returned from query")
+	}
+
+	return cntModels[0], nil
+}
@@ -0,0 +1,278 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+
+	driver "github.com/arangodb/go-driver"
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+type linkrepository struct {
+	sess     *manager.Session
+	database *manager.Database
+	link     driver.Collection
+	content  string
+}
+
+// NewLinkRepo creates the link repository. The links are the edges from
+// the documents of the content collection, the slug of the target is kept
+// in the edge so that a link to a missing content is not lost.
+func NewLinkRepo(
+	connP *manager.ConnectParams,
+	collection, contentCollection string,
+) (repository.LinkRepository, error) {
+	lrp := &linkrepository{content: contentCollection}
+	sess, dbs, err := manager.NewSessionDb(connP)
+	if err != nil {
+		return lrp, fmt.Errorf("error in getting new session %s", err)
+	}
+	lrp.sess = sess
+	lrp.database = dbs
+	linkCollection, err := dbs.FindOrCreateCollection(
+		collection,
+		&driver.CreateCollectionOptions{Type: driver.CollectionTypeEdge},
+	)
+	if err != nil {
+		return lrp, fmt.Errorf(
+			"error in finding or creating collection %s",
+			err,
+		)
+	}
+	props, err := linkCollection.Properties(context.Background())
+	if err != nil {
+		return lrp, fmt.Errorf("error in reading collection properties %s", err)
+	}
+	if props.Type != driver.CollectionTypeEdge {
+		return lrp, fmt.Errorf(
+			"link collection %s is not an edge collection, drop it and rebuild the links",
+			collection,
+		)
+	}
+	lrp.link = linkCollection
+	for field, name := range map[string]string{
+		"target":    "link_target_idx",
+		"namespace": "link_namespace_idx",
+	} {
+		_, _, err = dbs.EnsurePersistentIndex(
+			collection,
+			[]string{field},
+			&driver.EnsurePersistentIndexOptions{
+				InBackground: true,
+				Name:         name,
+			},
+		)
+		if err != nil {
+			return lrp, fmt.Errorf(
+				"error in creating index for %s field %s",
+				field,
+				err,
+			)
+		}
+	}
+
+	return lrp, nil
+}
+
+// SetLinks replaces the edges of the source and points the edges to its
+// slug, or still to its handle, at the contents they now reach, all within
+// a single stream transaction. It keeps the _to of every edge current as
+// the contents are stored, moved and deleted.
+func (lrp *linkrepository) SetLinks(
+	ctx context.Context,
+	src *model.LinkDoc,
+	targets []string,
+) error {
+	dbh := lrp.database.Handler()
+	tid, err := dbh.BeginTransaction(
+		ctx,
+		driver.TransactionCollections{
+			Read:  []string{lrp.content},
+			Write: []string{lrp.link.Name()},
+		},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("error in starting transaction %s", err)
+	}
+	tctx := driver.WithTransactionID(ctx, tid)
+	if err := lrp.setLinks(tctx, src, targets); err != nil {
+		//nolint:errcheck
+		dbh.AbortTransaction(ctx, tid, nil)
+
+		return err
+	}
+	if err := dbh.CommitTransaction(ctx, tid, nil); err != nil {
+		return fmt.Errorf("error in committing transaction %s", err)
+	}
+
+	return nil
+}
+
+func (lrp *linkrepository) setLinks(
+	ctx context.Context,
+	src *model.LinkDoc,
+	targets []string,
+) error {
+	from := lrp.handle(src.ContentID)
+	_, err := queryDocuments[model.LinkDoc](
+		ctx,
+		lrp.database.Handler(),
+		LinkRemove,
+		map[string]interface{}{
+			"from":             from,
+			"@link_collection": lrp.link.Name(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error in removing links of content %d %s",
+			src.ContentID,
+			err,
+		)
+	}
+	lnks := links.NewLinks(src, targets)
+	unique := make([]string, 0, len(lnks))
+	for _, lnk := range lnks {
+		unique = append(unique, lnk.Target)
+	}
+	if len(unique) > 0 {
+		_, err = queryDocuments[model.LinkDoc](
+			ctx,
+			lrp.database.Handler(),
+			LinkInsert,
+			map[string]interface{}{
+				"from":                from,
+				"targets":             unique,
+				"content_id":          src.ContentID,
+				"slug":                src.Slug,
+				"namespace":           src.Namespace,
+				"missing":             lrp.missing(),
+				"@content_collection": lrp.content,
+				"@link_collection":    lrp.link.Name(),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"error in adding links of content %d %s",
+				src.ContentID,
+				err,
+			)
+		}
+	}
+	_, err = queryDocuments[model.LinkDoc](
+		ctx,
+		lrp.database.Handler(),
+		LinkRetarget,
+		map[string]interface{}{
+			"to":                  from,
+			"target":              src.Slug,
+			"missing":             lrp.missing(),
+			"@content_collection": lrp.content,
+			"@link_collection":    lrp.link.Name(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error in retargeting links to content %d %s",
+			src.ContentID,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (lrp *linkrepository) OutgoingLinks(
+	ctx context.Context,
+	cid int64,
+) ([]*model.LinkDoc, error) {
+	return lrp.queryLinks(ctx, LinkOutgoing, map[string]interface{}{
+		"from": lrp.handle(cid),
+	})
+}
+
+func (lrp *linkrepository) Backlinks(
+	ctx context.Context,
+	slug string,
+) ([]*model.LinkDoc, error) {
+	return lrp.queryLinks(ctx, LinkBacklinks, map[string]interface{}{
+		"target": slug,
+	})
+}
+
+func (lrp *linkrepository) ListLinks(
+	ctx context.Context,
+	namespace string,
+) ([]*model.LinkDoc, error) {
+	return lrp.queryLinks(ctx, LinkList, map[string]interface{}{
+		"namespace": namespace,
+	})
+}
+
+func (lrp *linkrepository) queryLinks(
+	ctx context.Context,
+	query string,
+	bindVars map[string]interface{},
+) ([]*model.LinkDoc, error) {
+	bindVars["@link_collection"] = lrp.link.Name()
+	lnks, err := queryDocuments[model.LinkDoc](
+		ctx,
+		lrp.database.Handler(),
+		query,
+		bindVars,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in listing links %s", err)
+	}
+
+	return lnks, nil
+}
+
+// handle is the document handle of the content.
+func (lrp *linkrepository) handle(cid int64) string {
+	return fmt.Sprintf("%s/%d", lrp.content, cid)
+}
+
+// missing is the handle an edge goes to while its target does not exist.
+func (lrp *linkrepository) missing() string {
+	return lrp.content + "/missing"
+}
+
+// Drop removes the database along with all of its collections.
+func (lrp *linkrepository) Drop() error {
+	if err := lrp.database.Drop(); err != nil {
+		return fmt.Errorf("error in dropping database %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database is reachable.
+func (lrp *linkrepository) Ping(ctx context.Context) error {
+	if _, err := lrp.database.Handler().Info(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close is a no-op as the connections are managed by the driver.
+func (lrp *linkrepository) Close() error {
+	return nil
+}
+
+// Stats reports the number of edges in the link collection.
+func (lrp *linkrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	count, err := lrp.link.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting documents %s", err)
+	}
+
+	return &repository.Stats{Backend: "arangodb", Records: count}, nil
+}
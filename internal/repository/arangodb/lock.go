@@ -0,0 +1,237 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+type lockrepository struct {
+	sess     *manager.Session
+	database *manager.Database
+	lock     driver.Collection
+}
+
+// NewLockRepo creates the lock repository. The content id is the key of the
+// lock document and the ttl index removes the lapsed leases.
+func NewLockRepo(
+	connP *manager.ConnectParams,
+	collection string,
+) (repository.LockRepository, error) {
+	lrp := &lockrepository{}
+	sess, dbs, err := manager.NewSessionDb(connP)
+	if err != nil {
+		return lrp, fmt.Errorf("error in getting new session %s", err)
+	}
+	lrp.sess = sess
+	lrp.database = dbs
+	lockCollection, err := dbs.FindOrCreateCollection(
+		collection,
+		&driver.CreateCollectionOptions{},
+	)
+	if err != nil {
+		return lrp, fmt.Errorf(
+			"error in finding or creating collection %s",
+			err,
+		)
+	}
+	lrp.lock = lockCollection
+	_, _, err = lockCollection.EnsureTTLIndex(
+		context.Background(),
+		"expires_on",
+		0,
+		&driver.EnsureTTLIndexOptions{
+			InBackground: true,
+			Name:         "lock_expires_idx",
+		},
+	)
+	if err != nil {
+		return lrp, fmt.Errorf("error in creating ttl index %s", err)
+	}
+
+	return lrp, nil
+}
+
+func (lrp *lockrepository) AcquireLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lckModels, err := queryDocuments[model.LockDoc](
+		ctx,
+		lrp.database.Handler(),
+		LockAcquire,
+		map[string]interface{}{
+			"key":              strconv.FormatInt(cid, 10),
+			"content_id":       cid,
+			"holder":           holder,
+			"ttl":              ttl.Milliseconds(),
+			"@lock_collection": lrp.lock.Name(),
+		},
+	)
+	if err != nil {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in acquiring lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+	if len(lckModels) == 0 || lckModels[0].Holder != holder {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in acquiring lock of content %d %w",
+			cid,
+			repository.ErrLockHeld,
+		)
+	}
+
+	return lckModels[0], nil
+}
+
+func (lrp *lockrepository) RenewLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lckModels, err := queryDocuments[model.LockDoc](
+		ctx,
+		lrp.database.Handler(),
+		LockRenew,
+		map[string]interface{}{
+			"key":              strconv.FormatInt(cid, 10),
+			"holder":           holder,
+			"ttl":              ttl.Milliseconds(),
+			"@lock_collection": lrp.lock.Name(),
+		},
+	)
+	if err != nil {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in renewing lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+	if len(lckModels) == 0 {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in renewing lock of content %d %w",
+			cid,
+			repository.ErrLockNotHeld,
+		)
+	}
+
+	return lckModels[0], nil
+}
+
+func (lrp *lockrepository) ReleaseLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+) error {
+	lckModels, err := queryDocuments[model.LockDoc](
+		ctx,
+		lrp.database.Handler(),
+		LockRelease,
+		map[string]interface{}{
+			"key":              strconv.FormatInt(cid, 10),
+			"holder":           holder,
+			"@lock_collection": lrp.lock.Name(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error in releasing lock of content %d %s", cid, err)
+	}
+	if len(lckModels) == 0 {
+		return fmt.Errorf(
+			"error in releasing lock of content %d %w",
+			cid,
+			repository.ErrLockNotHeld,
+		)
+	}
+
+	return nil
+}
+
+func (lrp *lockrepository) BreakLock(
+	ctx context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	return lrp.findLock(ctx, LockBreak, cid)
+}
+
+func (lrp *lockrepository) GetLock(
+	ctx context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	return lrp.findLock(ctx, LockFind, cid)
+}
+
+func (lrp *lockrepository) findLock(
+	ctx context.Context,
+	query string,
+	cid int64,
+) (*model.LockDoc, error) {
+	lckModels, err := queryDocuments[model.LockDoc](
+		ctx,
+		lrp.database.Handler(),
+		query,
+		map[string]interface{}{
+			"key":              strconv.FormatInt(cid, 10),
+			"@lock_collection": lrp.lock.Name(),
+		},
+	)
+	if err != nil {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in finding lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+	if len(lckModels) == 0 {
+		return &model.LockDoc{NotFound: true}, nil
+	}
+
+	return lckModels[0], nil
+}
+
+// Drop removes the database along with all of its collections.
+func (lrp *lockrepository) Drop() error {
+	if err := lrp.database.Drop(); err != nil {
+		return fmt.Errorf("error in dropping database %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database is reachable.
+func (lrp *lockrepository) Ping(ctx context.Context) error {
+	if _, err := lrp.database.Handler().Info(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close is a no-op as the connections are managed by the driver.
+func (lrp *lockrepository) Close() error {
+	return nil
+}
+
+// Stats reports the number of documents in the lock collection, lapsed
+// leases are counted until the ttl index removes them.
+func (lrp *lockrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	count, err := lrp.lock.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting documents %s", err)
+	}
+
+	return &repository.Stats{Backend: "arangodb", Records: count}, nil
+}
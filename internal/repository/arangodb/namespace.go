@@ -0,0 +1,199 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+
+	driver "github.com/arangodb/go-driver"
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+type namespacerepository struct {
+	sess      *manager.Session
+	database  *manager.Database
+	namespace driver.Collection
+}
+
+func NewNamespaceRepo(
+	connP *manager.ConnectParams,
+	collection string,
+) (repository.NamespaceRepository, error) {
+	nrp := &namespacerepository{}
+	sess, dbs, err := manager.NewSessionDb(connP)
+	if err != nil {
+		return nrp, fmt.Errorf("error in getting new session %s", err)
+	}
+	nrp.sess = sess
+	nrp.database = dbs
+	schemaOptions := &driver.CollectionSchemaOptions{}
+	if err := schemaOptions.LoadRule(model.NamespaceSchema()); err != nil {
+		return nrp, fmt.Errorf("error in loading schema %s", err)
+	}
+	nspCollection, err := dbs.FindOrCreateCollection(
+		collection,
+		&driver.CreateCollectionOptions{Schema: schemaOptions},
+	)
+	if err != nil {
+		return nrp, fmt.Errorf(
+			"error in finding or creating collection %s",
+			err,
+		)
+	}
+	nrp.namespace = nspCollection
+	_, _, err = dbs.EnsurePersistentIndex(
+		collection,
+		[]string{"name"},
+		&driver.EnsurePersistentIndexOptions{
+			Unique:       true,
+			InBackground: true,
+			Name:         "namespace_name_idx",
+		},
+	)
+	if err != nil {
+		return nrp, fmt.Errorf(
+			"error in creating unique index for name field %s",
+			err,
+		)
+	}
+
+	return nrp, nil
+}
+
+func (nrp *namespacerepository) AddNamespace(
+	ctx context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	nspModels, err := nrp.queryNamespaces(
+		ctx,
+		NamespaceInsert,
+		map[string]interface{}{
+			"name":                  nsp.Name,
+			"display_name":          nsp.DisplayName,
+			"editors_group":         nsp.EditorsGroup,
+			"content_schema":        nsp.ContentSchema,
+			"created_by":            nsp.CreatedBy,
+			"@namespace_collection": nrp.namespace.Name(),
+		},
+	)
+	if err != nil {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in creating new namespace %s",
+			err,
+		)
+	}
+	if len(nspModels) == 0 {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in creating new namespace, no document returned",
+		)
+	}
+
+	return nspModels[0], nil
+}
+
+func (nrp *namespacerepository) GetNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	return nrp.findNamespace(ctx, NamespaceFind, name)
+}
+
+func (nrp *namespacerepository) ListNamespaces(
+	ctx context.Context,
+) ([]*model.NamespaceDoc, error) {
+	nspModels, err := nrp.queryNamespaces(
+		ctx,
+		NamespaceList,
+		map[string]interface{}{
+			"@namespace_collection": nrp.namespace.Name(),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in listing namespaces %s", err)
+	}
+
+	return nspModels, nil
+}
+
+func (nrp *namespacerepository) ArchiveNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	return nrp.findNamespace(ctx, NamespaceArchive, name)
+}
+
+// findNamespace runs a query that returns at most one namespace, NotFound
+// is set when there is none.
+func (nrp *namespacerepository) findNamespace(
+	ctx context.Context,
+	query, name string,
+) (*model.NamespaceDoc, error) {
+	nspModels, err := nrp.queryNamespaces(
+		ctx,
+		query,
+		map[string]interface{}{
+			"@namespace_collection": nrp.namespace.Name(),
+			"name":                  name,
+		},
+	)
+	if err != nil {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in getting namespace %s",
+			err,
+		)
+	}
+	if len(nspModels) == 0 {
+		return &model.NamespaceDoc{NotFound: true}, nil
+	}
+
+	return nspModels[0], nil
+}
+
+func (nrp *namespacerepository) queryNamespaces(
+	ctx context.Context,
+	query string,
+	bindVars map[string]interface{},
+) ([]*model.NamespaceDoc, error) {
+	return queryDocuments[model.NamespaceDoc](
+		ctx,
+		nrp.database.Handler(),
+		query,
+		bindVars,
+	)
+}
+
+// Drop removes the database along with all of its collections.
+func (nrp *namespacerepository) Drop() error {
+	if err := nrp.database.Drop(); err != nil {
+		return fmt.Errorf("error in dropping database %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database is reachable.
+func (nrp *namespacerepository) Ping(ctx context.Context) error {
+	if _, err := nrp.database.Handler().Info(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close is a no-op as the connections are managed by the driver.
+func (nrp *namespacerepository) Close() error {
+	return nil
+}
+
+// Stats reports the number of documents in the namespace collection.
+func (nrp *namespacerepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	count, err := nrp.namespace.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting documents %s", err)
+	}
+
+	return &repository.Stats{Backend: "arangodb", Records: count}, nil
+}
@@ -0,0 +1,114 @@
+package arangodb
+
+import (
+	"context"
+	"testing"
+
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/arangomanager/testarango"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpNamespace(
+	t *testing.T,
+) (*require.Assertions, repository.NamespaceRepository) {
+	t.Helper()
+	tra, err := testarango.NewTestArangoFromEnv(true)
+	if err != nil {
+		t.Fatalf("unable to construct new TestArango instance %s", err)
+	}
+	assert := require.New(t)
+	repo, err := NewNamespaceRepo(
+		&manager.ConnectParams{
+			User:     tra.User,
+			Pass:     tra.Pass,
+			Database: tra.Database,
+			Host:     tra.Host,
+			Port:     tra.Port,
+			Istls:    false,
+		}, manager.RandomString(16, 19),
+	)
+	assert.NoErrorf(
+		err,
+		"expect no error connecting to namespace repository, received %s",
+		err,
+	)
+	t.Cleanup(func() {
+		_ = repo.Drop()
+	})
+
+	return assert, repo
+}
+
+func newNamespace(name string) *model.NamespaceDoc {
+	return &model.NamespaceDoc{
+		Name:          name,
+		DisplayName:   "Dicty Stock Center",
+		EditorsGroup:  "curators",
+		ContentSchema: "slate",
+		CreatedBy:     "content@content.org",
+	}
+}
+
+func TestAddNamespace(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert, repo := setUpNamespace(t)
+	nsp, err := repo.AddNamespace(ctx, newNamespace("dsc"))
+	assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	assert.Equal(nsp.Name, "dsc", "name should match")
+	assert.Equal(nsp.DisplayName, "Dicty Stock Center", "display name should match")
+	assert.Equal(nsp.EditorsGroup, "curators", "editors group should match")
+	assert.Equal(nsp.ContentSchema, "slate", "content schema should match")
+	assert.False(nsp.Archived, "namespace should not be archived")
+	_, err = repo.AddNamespace(ctx, newNamespace("dsc"))
+	assert.Error(err, "expect error for duplicate namespace")
+}
+
+func TestGetNamespace(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert, repo := setUpNamespace(t)
+	_, err := repo.AddNamespace(ctx, newNamespace("dsc"))
+	assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	nsp, err := repo.GetNamespace(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from getting namespace %s", err)
+	assert.False(nsp.NotFound, "expect namespace to be found")
+	assert.Equal(nsp.Name, "dsc", "name should match")
+	enp, err := repo.GetNamespace(ctx, "DSC")
+	assert.NoErrorf(err, "expect no error from getting namespace %s", err)
+	assert.True(enp.NotFound, "expect namespace not to be found")
+}
+
+func TestListNamespaces(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert, repo := setUpNamespace(t)
+	nsps, err := repo.ListNamespaces(ctx)
+	assert.NoErrorf(err, "expect no error from listing namespaces %s", err)
+	assert.Len(nsps, 0, "expect no namespaces")
+	for _, name := range []string{"dsc", "dictybase", "genome"} {
+		_, err := repo.AddNamespace(ctx, newNamespace(name))
+		assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	}
+	nsps, err = repo.ListNamespaces(ctx)
+	assert.NoErrorf(err, "expect no error from listing namespaces %s", err)
+	assert.Len(nsps, 3, "expect three namespaces")
+	assert.Equal(nsps[0].Name, "dictybase", "should be sorted by name")
+}
+
+func TestArchiveNamespace(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert, repo := setUpNamespace(t)
+	_, err := repo.AddNamespace(ctx, newNamespace("dsc"))
+	assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	nsp, err := repo.ArchiveNamespace(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from archiving namespace %s", err)
+	assert.True(nsp.Archived, "namespace should be archived")
+	enp, err := repo.ArchiveNamespace(ctx, "genome")
+	assert.NoErrorf(err, "expect no error from archiving namespace %s", err)
+	assert.True(enp.NotFound, "expect namespace not to be found")
+}
@@ -16,6 +16,7 @@ const (
 			created_by: @created_by,
 			updated_by: @updated_by,
 			content: @content,
+			hash: @hash,
 			created_on : DATE_ISO8601(DATE_NOW()),
 			updated_on : DATE_ISO8601(DATE_NOW()),
 		} INTO @@content_collection RETURN NEW
@@ -26,7 +27,282 @@ const (
 			_key: @key, 
 			updated_by: @updated_by, 
 			updated_on : DATE_ISO8601(DATE_NOW()),
-			content: @content 
+			content: @content,
+			hash: @hash
 		} IN @@content_collection RETURN NEW
 	`
+
+	ContentListByNamespace = `
+		FOR cnt IN @@content_collection
+			FILTER cnt.namespace == @namespace
+			FILTER LENGTH(@slugs) == 0 OR cnt.slug IN @slugs
+			SORT cnt.slug
+			RETURN cnt
+	`
+
+	ContentList = `
+		FOR cnt IN @@content_collection
+			FILTER LENGTH(@namespaces) == 0 OR cnt.namespace IN @namespaces
+			SORT cnt.namespace, cnt.slug
+			RETURN cnt
+	`
+
+	ContentCountByNamespace = `
+		FOR cnt IN @@content_collection
+			COLLECT namespace = cnt.namespace WITH COUNT INTO total
+			RETURN { namespace, total }
+	`
+
+	ContentUpsert = `
+		UPSERT { _key: @key }
+		INSERT {
+			_key: @key,
+			name: @name,
+			slug: @slug,
+			namespace: @namespace,
+			created_by: @created_by,
+			updated_by: @updated_by,
+			content: @content,
+			hash: @hash,
+			created_on: @created_on,
+			updated_on: @updated_on
+		}
+		REPLACE {
+			name: @name,
+			slug: @slug,
+			namespace: @namespace,
+			created_by: @created_by,
+			updated_by: @updated_by,
+			content: @content,
+			hash: @hash,
+			created_on: @created_on,
+			updated_on: @updated_on
+		} IN @@content_collection RETURN NEW
+	`
+
+	ContentMove = `
+		UPDATE {
+			_key: @key,
+			slug: @slug,
+			namespace: @namespace,
+			updated_by: @updated_by,
+			updated_on : DATE_ISO8601(DATE_NOW())
+		} IN @@content_collection RETURN NEW
+	`
+)
+
+const (
+	NamespaceFind = `
+		FOR nsp IN @@namespace_collection
+			FILTER nsp.name == @name
+			LIMIT 1
+			RETURN nsp
+	`
+
+	NamespaceList = `
+		FOR nsp IN @@namespace_collection
+			SORT nsp.name
+			RETURN nsp
+	`
+
+	NamespaceInsert = `
+		INSERT {
+			name: @name,
+			display_name: @display_name,
+			editors_group: @editors_group,
+			content_schema: @content_schema,
+			archived: false,
+			created_by: @created_by,
+			created_on : DATE_ISO8601(DATE_NOW()),
+			updated_on : DATE_ISO8601(DATE_NOW()),
+		} INTO @@namespace_collection RETURN NEW
+	`
+
+	NamespaceArchive = `
+		FOR nsp IN @@namespace_collection
+			FILTER nsp.name == @name
+			UPDATE nsp WITH {
+				archived: true,
+				updated_on : DATE_ISO8601(DATE_NOW())
+			} IN @@namespace_collection RETURN NEW
+	`
+
+	AuditInsert = `
+		INSERT {
+			actor: @actor,
+			operation: @operation,
+			content_id: @content_id,
+			slug: @slug,
+			namespace: @namespace,
+			from_slug: @from_slug,
+			from_namespace: @from_namespace,
+			before_hash: @before_hash,
+			after_hash: @after_hash,
+			client: @client,
+			created_on : DATE_ISO8601(DATE_NOW())
+		} INTO @@audit_collection RETURN NEW
+	`
+
+	// the time range is given in milliseconds since epoch, null leaves it
+	// open
+	AuditList = `
+		FOR ent IN @@audit_collection
+			FILTER @actor == "" OR ent.actor == @actor
+			FILTER @namespace == "" OR ent.namespace == @namespace
+				OR ent.from_namespace == @namespace
+			FILTER @from == null OR DATE_TIMESTAMP(ent.created_on) >= @from
+			FILTER @to == null OR DATE_TIMESTAMP(ent.created_on) <= @to
+			SORT DATE_TIMESTAMP(ent.created_on), TO_NUMBER(ent._key)
+			LIMIT @limit
+			RETURN ent
+	`
+
+	// the lease is taken over when it has lapsed and extended when it is
+	// held by the same holder, any other holder leaves it untouched
+	LockAcquire = `
+		UPSERT { _key: @key }
+			INSERT {
+				_key: @key,
+				content_id: @content_id,
+				holder: @holder,
+				acquired_on: DATE_ISO8601(DATE_NOW()),
+				expires_on: DATE_ISO8601(DATE_NOW() + @ttl)
+			}
+			UPDATE DATE_TIMESTAMP(OLD.expires_on) <= DATE_NOW() ? {
+				holder: @holder,
+				acquired_on: DATE_ISO8601(DATE_NOW()),
+				expires_on: DATE_ISO8601(DATE_NOW() + @ttl)
+			} : (OLD.holder == @holder ? {
+				expires_on: DATE_ISO8601(DATE_NOW() + @ttl)
+			} : {})
+			IN @@lock_collection RETURN NEW
+	`
+
+	LockRenew = `
+		FOR lck IN @@lock_collection
+			FILTER lck._key == @key
+			FILTER lck.holder == @holder
+			FILTER DATE_TIMESTAMP(lck.expires_on) > DATE_NOW()
+			UPDATE lck WITH {
+				expires_on: DATE_ISO8601(DATE_NOW() + @ttl)
+			} IN @@lock_collection RETURN NEW
+	`
+
+	LockRelease = `
+		FOR lck IN @@lock_collection
+			FILTER lck._key == @key
+			FILTER lck.holder == @holder
+			REMOVE lck IN @@lock_collection RETURN OLD
+	`
+
+	LockBreak = `
+		FOR lck IN @@lock_collection
+			FILTER lck._key == @key
+			REMOVE lck IN @@lock_collection RETURN OLD
+	`
+
+	LockFind = `
+		FOR lck IN @@lock_collection
+			FILTER lck._key == @key
+			FILTER DATE_TIMESTAMP(lck.expires_on) > DATE_NOW()
+			RETURN lck
+	`
+
+	AssetInsert = `
+		INSERT {
+			name: @name,
+			mime_type: @mime_type,
+			size: @size,
+			checksum: @checksum,
+			blob_key: @blob_key,
+			created_by: @created_by,
+			created_on: DATE_ISO8601(DATE_NOW()),
+			references: []
+		} INTO @@asset_collection RETURN NEW
+	`
+
+	AssetFind = `
+		FOR ast IN @@asset_collection
+			FILTER ast._key == @key
+			RETURN MERGE(ast, { references: SORTED(ast.references) })
+	`
+
+	AssetRemove = `
+		FOR ast IN @@asset_collection
+			FILTER ast._key == @key
+			REMOVE ast IN @@asset_collection RETURN OLD
+	`
+
+	// the content is added to the references of the assets it embeds and
+	// removed from all the others, missing assets are never matched
+	AssetReference = `
+		FOR ast IN @@asset_collection
+			LET embeds = POSITION(@aids, TO_NUMBER(ast._key))
+			FILTER embeds OR POSITION(ast.references, @content_id)
+			UPDATE ast WITH {
+				references: embeds
+					? UNION_DISTINCT(ast.references, [@content_id])
+					: REMOVE_VALUE(ast.references, @content_id)
+			} IN @@asset_collection
+	`
+
+	LinkRemove = `
+		FOR lnk IN @@link_collection
+			FILTER lnk._from == @from
+			REMOVE lnk IN @@link_collection
+	`
+
+	// the edge goes to the target content found at the time of the save,
+	// a missing target is kept by its slug alone
+	LinkInsert = `
+		FOR target IN @targets
+			LET found = FIRST(
+				FOR cnt IN @@content_collection
+					FILTER cnt.slug == target
+					RETURN cnt._id
+			)
+			INSERT {
+				_from: @from,
+				_to: NOT_NULL(found, @missing),
+				content_id: @content_id,
+				slug: @slug,
+				namespace: @namespace,
+				target: target
+			} INTO @@link_collection
+	`
+
+	// the edges to the slug of a content or to its handle go to whichever
+	// content has their target slug now
+	LinkRetarget = `
+		FOR lnk IN @@link_collection
+			FILTER lnk.target == @target OR lnk._to == @to
+			LET found = FIRST(
+				FOR cnt IN @@content_collection
+					FILTER cnt.slug == lnk.target
+					RETURN cnt._id
+			)
+			UPDATE lnk WITH { _to: NOT_NULL(found, @missing) }
+				IN @@link_collection
+	`
+
+	LinkOutgoing = `
+		FOR lnk IN @@link_collection
+			FILTER lnk._from == @from
+			SORT lnk.target
+			RETURN lnk
+	`
+
+	LinkBacklinks = `
+		FOR lnk IN @@link_collection
+			FILTER lnk.target == @target
+			SORT lnk.content_id
+			RETURN lnk
+	`
+
+	LinkList = `
+		FOR lnk IN @@link_collection
+			FILTER @namespace == "" OR lnk.namespace == @namespace
+			SORT lnk.content_id, lnk.target
+			RETURN lnk
+	`
 )
@@ -0,0 +1,78 @@
+package arangodb
+
+import (
+	"context"
+
+	"github.com/dictyBase/modware-content/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// statementNames names the spans of the aql queries after their statement.
+var statementNames = map[string]string{
+	ContentFindBySlug:       "ContentFindBySlug",
+	ContentInsert:           "ContentInsert",
+	ContentUpdate:           "ContentUpdate",
+	ContentListByNamespace:  "ContentListByNamespace",
+	ContentList:             "ContentList",
+	ContentCountByNamespace: "ContentCountByNamespace",
+	ContentUpsert:           "ContentUpsert",
+	ContentMove:             "ContentMove",
+	NamespaceFind:           "NamespaceFind",
+	NamespaceList:           "NamespaceList",
+	NamespaceInsert:         "NamespaceInsert",
+	NamespaceArchive:        "NamespaceArchive",
+	AuditInsert:             "AuditInsert",
+	AuditList:               "AuditList",
+	LockAcquire:             "LockAcquire",
+	LockRenew:               "LockRenew",
+	LockRelease:             "LockRelease",
+	LockBreak:               "LockBreak",
+	LockFind:                "LockFind",
+	AssetInsert:             "AssetInsert",
+	AssetFind:               "AssetFind",
+	AssetRemove:             "AssetRemove",
+	AssetReference:          "AssetReference",
+	LinkRemove:              "LinkRemove",
+	LinkInsert:              "LinkInsert",
+	LinkRetarget:            "LinkRetarget",
+	LinkOutgoing:            "LinkOutgoing",
+	LinkBacklinks:           "LinkBacklinks",
+	LinkList:                "LinkList",
+}
+
+func startSpan(
+	ctx context.Context,
+	name string,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(
+		ctx,
+		name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemKey.String("arangodb")),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func startQuerySpan(
+	ctx context.Context,
+	query string,
+) (context.Context, trace.Span) {
+	name, ok := statementNames[query]
+	if !ok {
+		name = "aql query"
+	}
+
+	return startSpan(ctx, name, semconv.DBStatement(query))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
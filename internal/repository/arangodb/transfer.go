@@ -0,0 +1,143 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/dictyBase/modware-content/internal/model"
+)
+
+// TransferContents copies or moves the contents of a namespace within a
+// single stream transaction, either all of them are transferred or none.
+func (arp *arangorepository) TransferContents(
+	ctx context.Context,
+	trn *model.ContentTransfer,
+) ([]*model.ContentDoc, error) {
+	rewrite, err := model.SlugRewriter(trn.SlugPattern, trn.SlugReplacement)
+	if err != nil {
+		return nil, err
+	}
+	dbh := arp.database.Handler()
+	tid, err := dbh.BeginTransaction(
+		ctx,
+		driver.TransactionCollections{Write: []string{arp.content.Name()}},
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in starting transaction %s", err)
+	}
+	tctx := driver.WithTransactionID(ctx, tid)
+	cntModels, err := arp.transfer(tctx, trn, rewrite)
+	if err != nil {
+		//nolint:errcheck
+		dbh.AbortTransaction(ctx, tid, nil)
+
+		return nil, err
+	}
+	if err := dbh.CommitTransaction(ctx, tid, nil); err != nil {
+		return nil, fmt.Errorf("error in committing transaction %s", err)
+	}
+
+	return cntModels, nil
+}
+
+func (arp *arangorepository) transfer(
+	ctx context.Context,
+	trn *model.ContentTransfer,
+	rewrite func(string) string,
+) ([]*model.ContentDoc, error) {
+	slugs := trn.Slugs
+	if slugs == nil {
+		slugs = make([]string, 0)
+	}
+	existing, err := arp.queryContents(
+		ctx,
+		ContentListByNamespace,
+		map[string]interface{}{
+			"@content_collection": arp.content.Name(),
+			"namespace":           trn.From,
+			"slugs":               slugs,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	cntModels := make([]*model.ContentDoc, 0, len(existing))
+	for _, cnt := range existing {
+		query, bindVars := ContentInsert, map[string]interface{}{
+			"name":                cnt.Name,
+			"namespace":           trn.To,
+			"created_by":          cnt.CreatedBy,
+			"updated_by":          trn.UpdatedBy,
+			"content":             cnt.Content,
+			"hash":                model.HashContent(cnt.Content),
+			"slug":                rewrite(cnt.Slug),
+			"@content_collection": arp.content.Name(),
+		}
+		if trn.Move {
+			query, bindVars = ContentMove, map[string]interface{}{
+				"key":                 cnt.Key,
+				"namespace":           trn.To,
+				"updated_by":          trn.UpdatedBy,
+				"slug":                rewrite(cnt.Slug),
+				"@content_collection": arp.content.Name(),
+			}
+		}
+		rows, err := arp.queryContents(ctx, query, bindVars)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error in transferring content %s %s",
+				cnt.Slug,
+				err,
+			)
+		}
+		cntModels = append(cntModels, rows...)
+	}
+
+	return cntModels, nil
+}
+
+func (arp *arangorepository) queryContents(
+	ctx context.Context,
+	query string,
+	bindVars map[string]interface{},
+) ([]*model.ContentDoc, error) {
+	return queryDocuments[model.ContentDoc](
+		ctx,
+		arp.database.Handler(),
+		query,
+		bindVars,
+	)
+}
+
+// queryDocuments runs the query with the given context and reads all the
+// returned documents, the context-less helpers of arangomanager are not used
+// so that cancellation and deadlines reach the database.
+func queryDocuments[T any](
+	ctx context.Context,
+	dbh driver.Database,
+	query string,
+	bindVars map[string]interface{},
+) (docs []*T, err error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+	cursor, err := dbh.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, fmt.Errorf("error in running query %s", err)
+	}
+	defer cursor.Close()
+	docs = make([]*T, 0)
+	for cursor.HasMore() {
+		doc := new(T)
+		if _, err := cursor.ReadDocument(ctx, doc); err != nil {
+			return nil, fmt.Errorf(
+				"error in reading document to struct %s",
+				err,
+			)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
@@ -0,0 +1,285 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	assetBucket = []byte("asset")
+	// the ids of the assets embedded by every content, the assets keep the
+	// reverse in their references
+	embedBucket = []byte("asset_embed")
+)
+
+type assetrepository struct {
+	dbh      *bolt.DB
+	validate *validator.Validate
+}
+
+func NewAssetRepo(dbh *bolt.DB) (repository.AssetRepository, error) {
+	err := dbh.Update(func(txn *bolt.Tx) error {
+		return createBuckets(txn, assetBucket, embedBucket)
+	})
+	if err != nil {
+		return &assetrepository{}, err
+	}
+
+	return &assetrepository{dbh: dbh, validate: validator.New()}, nil
+}
+
+func (arp *assetrepository) AddAsset(
+	_ context.Context,
+	ast *model.AssetDoc,
+) (*model.AssetDoc, error) {
+	if err := arp.validate.Struct(ast); err != nil {
+		return &model.AssetDoc{}, fmt.Errorf(
+			"error in validating asset %s",
+			err,
+		)
+	}
+	doc := *ast
+	err := arp.dbh.Update(func(txn *bolt.Tx) error {
+		seq, err := txn.Bucket(assetBucket).NextSequence()
+		if err != nil {
+			return fmt.Errorf("error in generating id %s", err)
+		}
+		doc.Key = strconv.FormatUint(seq, 10)
+		doc.CreatedOn = time.Now().UTC()
+		doc.References = make([]int64, 0)
+
+		return writeAsset(txn.Bucket(assetBucket), &doc)
+	})
+	if err != nil {
+		return &model.AssetDoc{}, fmt.Errorf("error in adding asset %s", err)
+	}
+
+	return &doc, nil
+}
+
+func (arp *assetrepository) GetAsset(
+	_ context.Context,
+	aid int64,
+) (*model.AssetDoc, error) {
+	ast := &model.AssetDoc{}
+	err := arp.dbh.View(func(txn *bolt.Tx) error {
+		return readAsset(txn.Bucket(assetBucket), aid, ast)
+	})
+	if err != nil {
+		return &model.AssetDoc{}, fmt.Errorf(
+			"error in finding asset %d %s",
+			aid,
+			err,
+		)
+	}
+
+	return ast, nil
+}
+
+func (arp *assetrepository) RemoveAsset(_ context.Context, aid int64) error {
+	err := arp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(assetBucket)
+		ast := &model.AssetDoc{}
+		if err := readAsset(bkt, aid, ast); err != nil {
+			return err
+		}
+		if ast.NotFound {
+			return fmt.Errorf("asset %d not found", aid)
+		}
+		for _, cid := range ast.References {
+			aids, err := readEmbeds(txn, cid)
+			if err != nil {
+				return err
+			}
+			if err := writeEmbeds(txn, cid, without(aids, aid)); err != nil {
+				return err
+			}
+		}
+
+		return bkt.Delete(itob(aid))
+	})
+	if err != nil {
+		return fmt.Errorf("error in removing asset %d %s", aid, err)
+	}
+
+	return nil
+}
+
+func (arp *assetrepository) SetReferences(
+	_ context.Context,
+	cid int64,
+	aids []int64,
+) error {
+	err := arp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(assetBucket)
+		previous, err := readEmbeds(txn, cid)
+		if err != nil {
+			return err
+		}
+		for _, aid := range previous {
+			if err := updateReferences(bkt, aid, cid, false); err != nil {
+				return err
+			}
+		}
+		existing := make([]int64, 0, len(aids))
+		for _, aid := range aids {
+			ast := &model.AssetDoc{}
+			if err := readAsset(bkt, aid, ast); err != nil {
+				return err
+			}
+			if ast.NotFound {
+				continue
+			}
+			if err := updateReferences(bkt, aid, cid, true); err != nil {
+				return err
+			}
+			existing = append(existing, aid)
+		}
+
+		return writeEmbeds(txn, cid, existing)
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"error in setting asset references of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Drop removes all the stored assets, the database file is left as is.
+func (arp *assetrepository) Drop() error {
+	err := arp.dbh.Update(func(txn *bolt.Tx) error {
+		return recreateBuckets(txn, assetBucket, embedBucket)
+	})
+	if err != nil {
+		return fmt.Errorf("error in dropping assets %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database file is still open.
+func (arp *assetrepository) Ping(_ context.Context) error {
+	return arp.dbh.View(func(txn *bolt.Tx) error {
+		if txn.Bucket(assetBucket) == nil {
+			return fmt.Errorf("bucket %s is missing", assetBucket)
+		}
+
+		return nil
+	})
+}
+
+// Close closes the database file, it is shared by all the repositories of
+// the file so closing any one of them closes the others.
+func (arp *assetrepository) Close() error {
+	if err := arp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of stored assets.
+func (arp *assetrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	return countKeys(arp.dbh, assetBucket)
+}
+
+// updateReferences adds or removes the content from the references of the
+// asset, a missing asset is skipped.
+func updateReferences(
+	bkt *bolt.Bucket,
+	aid, cid int64,
+	add bool,
+) error {
+	ast := &model.AssetDoc{}
+	if err := readAsset(bkt, aid, ast); err != nil || ast.NotFound {
+		return err
+	}
+	ast.References = without(ast.References, cid)
+	if add {
+		ast.References = append(ast.References, cid)
+		sort.Slice(ast.References, func(i, j int) bool {
+			return ast.References[i] < ast.References[j]
+		})
+	}
+
+	return writeAsset(bkt, ast)
+}
+
+func readAsset(bkt *bolt.Bucket, aid int64, ast *model.AssetDoc) error {
+	data := bkt.Get(itob(aid))
+	if data == nil {
+		*ast = model.AssetDoc{NotFound: true}
+
+		return nil
+	}
+	if err := json.Unmarshal(data, ast); err != nil {
+		return fmt.Errorf("error in decoding asset %s", err)
+	}
+
+	return nil
+}
+
+func writeAsset(bkt *bolt.Bucket, ast *model.AssetDoc) error {
+	aid, err := strconv.ParseInt(ast.Key, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error in parsing key %s", err)
+	}
+	data, err := json.Marshal(ast)
+	if err != nil {
+		return fmt.Errorf("error in encoding asset %s", err)
+	}
+
+	return bkt.Put(itob(aid), data)
+}
+
+func readEmbeds(txn *bolt.Tx, cid int64) ([]int64, error) {
+	data := txn.Bucket(embedBucket).Get(itob(cid))
+	if data == nil {
+		return nil, nil
+	}
+	var aids []int64
+	if err := json.Unmarshal(data, &aids); err != nil {
+		return nil, fmt.Errorf("error in decoding embedded assets %s", err)
+	}
+
+	return aids, nil
+}
+
+func writeEmbeds(txn *bolt.Tx, cid int64, aids []int64) error {
+	bkt := txn.Bucket(embedBucket)
+	if len(aids) == 0 {
+		return bkt.Delete(itob(cid))
+	}
+	data, err := json.Marshal(aids)
+	if err != nil {
+		return fmt.Errorf("error in encoding embedded assets %s", err)
+	}
+
+	return bkt.Put(itob(cid), data)
+}
+
+func without(ids []int64, id int64) []int64 {
+	rest := make([]int64, 0, len(ids))
+	for _, val := range ids {
+		if val != id {
+			rest = append(rest, val)
+		}
+	}
+
+	return rest
+}
@@ -0,0 +1,140 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+	bolt "go.etcd.io/bbolt"
+)
+
+var auditBucket = []byte("audit")
+
+type auditrepository struct {
+	dbh      *bolt.DB
+	validate *validator.Validate
+}
+
+func NewAuditRepo(dbh *bolt.DB) (repository.AuditRepository, error) {
+	err := dbh.Update(func(txn *bolt.Tx) error {
+		return createBuckets(txn, auditBucket)
+	})
+	if err != nil {
+		return &auditrepository{}, err
+	}
+
+	return &auditrepository{dbh: dbh, validate: validator.New()}, nil
+}
+
+// AddEntry keys the entry by the sequence of the bucket, so the entries are
+// kept in the order they are added.
+func (aud *auditrepository) AddEntry(
+	_ context.Context,
+	ent *model.AuditEntry,
+) (*model.AuditEntry, error) {
+	if err := aud.validate.Struct(ent); err != nil {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in adding audit entry %s",
+			err,
+		)
+	}
+	doc := *ent
+	doc.CreatedOn = time.Now().UTC()
+	err := aud.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(auditBucket)
+		seq, err := bkt.NextSequence()
+		if err != nil {
+			return fmt.Errorf("error in generating id %s", err)
+		}
+		doc.Key = strconv.FormatUint(seq, 10)
+		data, err := json.Marshal(&doc)
+		if err != nil {
+			return fmt.Errorf("error in encoding audit entry %s", err)
+		}
+
+		return bkt.Put(itob(int64(seq)), data)
+	})
+	if err != nil {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in adding audit entry %s",
+			err,
+		)
+	}
+
+	return &doc, nil
+}
+
+func (aud *auditrepository) ListEntries(
+	_ context.Context,
+	flt *model.AuditFilter,
+) ([]*model.AuditEntry, error) {
+	ents := make([]*model.AuditEntry, 0)
+	err := aud.dbh.View(func(txn *bolt.Tx) error {
+		crs := txn.Bucket(auditBucket).Cursor()
+		for key, val := crs.First(); key != nil; key, val = crs.Next() {
+			ent := &model.AuditEntry{}
+			if err := json.Unmarshal(val, ent); err != nil {
+				return fmt.Errorf("error in decoding audit entry %s", err)
+			}
+			if !flt.Match(ent) {
+				continue
+			}
+			ents = append(ents, ent)
+			if flt.Limit > 0 && len(ents) == flt.Limit {
+				return nil
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ents, fmt.Errorf("error in listing audit entries %s", err)
+	}
+
+	return ents, nil
+}
+
+// Drop removes all the stored entries, the database file is left as is.
+func (aud *auditrepository) Drop() error {
+	err := aud.dbh.Update(func(txn *bolt.Tx) error {
+		return recreateBuckets(txn, auditBucket)
+	})
+	if err != nil {
+		return fmt.Errorf("error in dropping audit entries %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database file is still open.
+func (aud *auditrepository) Ping(_ context.Context) error {
+	return aud.dbh.View(func(txn *bolt.Tx) error {
+		if txn.Bucket(auditBucket) == nil {
+			return fmt.Errorf("bucket %s is missing", auditBucket)
+		}
+
+		return nil
+	})
+}
+
+// Close closes the database file, it is shared by all the repositories of
+// the file so closing any one of them closes the others.
+func (aud *auditrepository) Close() error {
+	if err := aud.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of stored entries.
+func (aud *auditrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	return countKeys(aud.dbh, auditBucket)
+}
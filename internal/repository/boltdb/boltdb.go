@@ -0,0 +1,617 @@
+// Package boltdb provides file backed implementations of the repositories
+// on top of bbolt, for running the service as a single binary. Every write
+// runs in a bbolt transaction that is synced to disk before it returns, so
+// a crash never leaves a partially written change behind.
+package boltdb
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	contentBucket = []byte("content")
+	slugBucket    = []byte("content_slug")
+)
+
+// Open opens or creates the database file, it gives up if the file is
+// locked by another process for longer than the timeout.
+func Open(path string, timeout time.Duration) (*bolt.DB, error) {
+	dbh, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("error in opening database file %s %s", path, err)
+	}
+
+	return dbh, nil
+}
+
+type boltrepository struct {
+	dbh      *bolt.DB
+	validate *validator.Validate
+}
+
+// NewContentRepo creates the content repository in an opened database, the
+// namespace repository could share the same database.
+func NewContentRepo(dbh *bolt.DB) (repository.ContentRepository, error) {
+	err := dbh.Update(func(txn *bolt.Tx) error {
+		return createBuckets(txn, contentBucket, slugBucket)
+	})
+	if err != nil {
+		return &boltrepository{}, err
+	}
+
+	return &boltrepository{dbh: dbh, validate: validator.New()}, nil
+}
+
+func (brp *boltrepository) GetContentBySlug(
+	_ context.Context,
+	slug string,
+) (*model.ContentDoc, error) {
+	cntModel := &model.ContentDoc{}
+	err := brp.dbh.View(func(txn *bolt.Tx) error {
+		key := txn.Bucket(slugBucket).Get([]byte(slug))
+		if key == nil {
+			cntModel.NotFound = true
+
+			return nil
+		}
+
+		return readContent(txn, key, cntModel)
+	})
+	if err != nil {
+		return cntModel, fmt.Errorf(
+			"error in getting content by slug name %s",
+			err,
+		)
+	}
+
+	return cntModel, nil
+}
+
+func (brp *boltrepository) GetContent(
+	_ context.Context,
+	cid int64,
+) (*model.ContentDoc, error) {
+	cntModel := &model.ContentDoc{}
+	err := brp.dbh.View(func(txn *bolt.Tx) error {
+		return readContent(txn, itob(cid), cntModel)
+	})
+	if err != nil {
+		return cntModel, fmt.Errorf("error in reading document %s", err)
+	}
+
+	return cntModel, nil
+}
+
+func (brp *boltrepository) AddContent(
+	_ context.Context,
+	cattr *content.NewContentAttributes,
+) (*model.ContentDoc, error) {
+	var cntModel *model.ContentDoc
+	err := brp.dbh.Update(func(txn *bolt.Tx) error {
+		cnt, err := brp.add(txn, cattr)
+		cntModel = cnt
+
+		return err
+	})
+
+	return cntModel, err
+}
+
+func (brp *boltrepository) EditContent(
+	_ context.Context,
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	var cntModel *model.ContentDoc
+	err := brp.dbh.Update(func(txn *bolt.Tx) error {
+		cnt, err := brp.edit(txn, cid, cattr)
+		cntModel = cnt
+
+		return err
+	})
+
+	return cntModel, err
+}
+
+func (brp *boltrepository) DeleteContent(
+	_ context.Context,
+	cid int64,
+) error {
+	return brp.dbh.Update(func(txn *bolt.Tx) error {
+		_, err := remove(txn, cid)
+
+		return err
+	})
+}
+
+func (brp *boltrepository) ListContents(
+	_ context.Context,
+	namespaces []string,
+) ([]*model.ContentDoc, error) {
+	var cntModels []*model.ContentDoc
+	err := brp.dbh.View(func(txn *bolt.Tx) error {
+		cnts, err := list(txn, namespaces, nil)
+		cntModels = cnts
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in listing contents %s", err)
+	}
+
+	return cntModels, nil
+}
+
+func (brp *boltrepository) ImportContent(
+	_ context.Context,
+	cnt *model.ContentDoc,
+	overwrite bool,
+) (*model.ContentDoc, bool, error) {
+	cid, err := strconv.ParseInt(cnt.Key, 10, 64)
+	if err != nil {
+		return cnt, false, fmt.Errorf("error in parsing key %s", err)
+	}
+	cntModel := &model.ContentDoc{}
+	var imported bool
+	err = brp.dbh.Update(func(txn *bolt.Tx) error {
+		if err := readContent(txn, itob(cid), cntModel); err != nil {
+			return err
+		}
+		if !cntModel.NotFound && !overwrite {
+			return nil
+		}
+		doc := *cnt
+		doc.NotFound = false
+		if err := brp.put(txn, &doc); err != nil {
+			return err
+		}
+		bkt := txn.Bucket(contentBucket)
+		if uint64(cid) > bkt.Sequence() {
+			if err := bkt.SetSequence(uint64(cid)); err != nil {
+				return fmt.Errorf("error in updating id sequence %s", err)
+			}
+		}
+		cntModel, imported = &doc, true
+
+		return nil
+	})
+	if err != nil {
+		return cnt, false, fmt.Errorf("error in importing content %s", err)
+	}
+
+	return cntModel, imported, nil
+}
+
+// CountByNamespace returns the number of contents in every namespace.
+func (brp *boltrepository) CountByNamespace(
+	_ context.Context,
+) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	err := brp.dbh.View(func(txn *bolt.Tx) error {
+		cnts, err := list(txn, nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, cnt := range cnts {
+			counts[cnt.Namespace]++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in counting contents %s", err)
+	}
+
+	return counts, nil
+}
+
+// TransferContents copies or moves the contents of a namespace within a
+// single transaction, either all of them are transferred or none.
+func (brp *boltrepository) TransferContents(
+	_ context.Context,
+	trn *model.ContentTransfer,
+) ([]*model.ContentDoc, error) {
+	rewrite, err := model.SlugRewriter(trn.SlugPattern, trn.SlugReplacement)
+	if err != nil {
+		return nil, err
+	}
+	var cntModels []*model.ContentDoc
+	err = brp.dbh.Update(func(txn *bolt.Tx) error {
+		existing, err := list(txn, []string{trn.From}, trn.Slugs)
+		if err != nil {
+			return err
+		}
+		cntModels = make([]*model.ContentDoc, 0, len(existing))
+		for _, cnt := range existing {
+			doc := *cnt
+			doc.Slug = rewrite(cnt.Slug)
+			doc.Namespace = trn.To
+			doc.UpdatedBy = trn.UpdatedBy
+			doc.UpdatedOn = time.Now().UTC()
+			if !trn.Move {
+				key, err := nextKey(txn)
+				if err != nil {
+					return err
+				}
+				doc.Key = key
+				doc.CreatedOn = doc.UpdatedOn
+			}
+			if err := brp.put(txn, &doc); err != nil {
+				return fmt.Errorf(
+					"error in transferring content %s %s",
+					cnt.Slug,
+					err,
+				)
+			}
+			cntModels = append(cntModels, &doc)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cntModels, nil
+}
+
+// BatchContents runs a list of create, update and delete operations. In
+// atomic mode all of them run in a single transaction and the first failure
+// rolls back the entire batch. Otherwise every operation is run on its own
+// and its failure is recorded in the corresponding result.
+func (brp *boltrepository) BatchContents(
+	_ context.Context,
+	ops []*model.BatchOperation,
+	atomic bool,
+) ([]*model.BatchResult, error) {
+	results := make([]*model.BatchResult, 0, len(ops))
+	if !atomic {
+		for _, bop := range ops {
+			var cntModel *model.ContentDoc
+			err := brp.dbh.Update(func(txn *bolt.Tx) error {
+				cnt, err := brp.runOperation(txn, bop)
+				cntModel = cnt
+
+				return err
+			})
+			results = append(results, &model.BatchResult{
+				Action:  bop.Action,
+				Content: cntModel,
+				Err:     err,
+			})
+		}
+
+		return results, nil
+	}
+	err := brp.dbh.Update(func(txn *bolt.Tx) error {
+		for idx, bop := range ops {
+			cntModel, err := brp.runOperation(txn, bop)
+			if err != nil {
+				return fmt.Errorf("error in batch operation %d %s", idx, err)
+			}
+			results = append(results, &model.BatchResult{
+				Action:  bop.Action,
+				Content: cntModel,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Drop removes all the stored contents, the database file is left as is.
+func (brp *boltrepository) Drop() error {
+	err := brp.dbh.Update(func(txn *bolt.Tx) error {
+		return recreateBuckets(txn, contentBucket, slugBucket)
+	})
+	if err != nil {
+		return fmt.Errorf("error in dropping contents %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database file is still open.
+func (brp *boltrepository) Ping(
+	_ context.Context,
+) error {
+	return brp.dbh.View(func(txn *bolt.Tx) error {
+		if txn.Bucket(contentBucket) == nil {
+			return fmt.Errorf("bucket %s is missing", contentBucket)
+		}
+
+		return nil
+	})
+}
+
+// Close closes the database file, it is shared by all the repositories of
+// the file so closing any one of them closes the others.
+func (brp *boltrepository) Close() error {
+	if err := brp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of stored contents.
+func (brp *boltrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	return countKeys(brp.dbh, contentBucket)
+}
+
+func (brp *boltrepository) runOperation(
+	txn *bolt.Tx,
+	bop *model.BatchOperation,
+) (*model.ContentDoc, error) {
+	switch bop.Action {
+	case model.BatchCreate:
+		return brp.add(txn, bop.Create)
+	case model.BatchUpdate:
+		return brp.edit(txn, bop.ID, bop.Update)
+	case model.BatchDelete:
+		return remove(txn, bop.ID)
+	}
+
+	return nil, fmt.Errorf("unknown batch action %s", bop.Action)
+}
+
+func (brp *boltrepository) add(
+	txn *bolt.Tx,
+	cattr *content.NewContentAttributes,
+) (*model.ContentDoc, error) {
+	now := time.Now().UTC()
+	doc := &model.ContentDoc{
+		Name:      cattr.Name,
+		Slug:      cattr.Slug,
+		Namespace: cattr.Namespace,
+		CreatedBy: cattr.CreatedBy,
+		UpdatedBy: cattr.CreatedBy,
+		Content:   cattr.Content,
+		CreatedOn: now,
+		UpdatedOn: now,
+	}
+	key, err := nextKey(txn)
+	if err != nil {
+		return &model.ContentDoc{}, err
+	}
+	doc.Key = key
+	if err := brp.put(txn, doc); err != nil {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in creating new content %s",
+			err,
+		)
+	}
+
+	return doc, nil
+}
+
+func (brp *boltrepository) edit(
+	txn *bolt.Tx,
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	doc := &model.ContentDoc{}
+	if err := readContent(txn, itob(cid), doc); err != nil {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in updating content %s",
+			err,
+		)
+	}
+	if doc.NotFound {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in updating content, document with ID %d not found",
+			cid,
+		)
+	}
+	doc.UpdatedBy = cattr.UpdatedBy
+	doc.Content = cattr.Content
+	doc.UpdatedOn = time.Now().UTC()
+	if err := brp.put(txn, doc); err != nil {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in updating content %s",
+			err,
+		)
+	}
+
+	return doc, nil
+}
+
+// put stores the document under its key along with the hash of its content,
+// a content already stored under the key is replaced and its slug is
+// released.
+func (brp *boltrepository) put(txn *bolt.Tx, doc *model.ContentDoc) error {
+	doc.Hash = model.HashContent(doc.Content)
+	cid, err := strconv.ParseInt(doc.Key, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error in parsing key %s", err)
+	}
+	key := itob(cid)
+	slugs := txn.Bucket(slugBucket)
+	if exist := slugs.Get([]byte(doc.Slug)); exist != nil &&
+		btoi(exist) != cid {
+		return fmt.Errorf("slug %s already exists", doc.Slug)
+	}
+	if err := brp.validate.Struct(doc); err != nil {
+		return fmt.Errorf("error in validating content %s", err)
+	}
+	exist := &model.ContentDoc{}
+	if err := readContent(txn, key, exist); err != nil {
+		return err
+	}
+	if !exist.NotFound {
+		if err := slugs.Delete([]byte(exist.Slug)); err != nil {
+			return fmt.Errorf("error in removing slug %s", err)
+		}
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("error in encoding content %s", err)
+	}
+	if err := txn.Bucket(contentBucket).Put(key, data); err != nil {
+		return fmt.Errorf("error in storing content %s", err)
+	}
+	if err := slugs.Put([]byte(doc.Slug), key); err != nil {
+		return fmt.Errorf("error in storing slug %s", err)
+	}
+
+	return nil
+}
+
+func remove(txn *bolt.Tx, cid int64) (*model.ContentDoc, error) {
+	key := itob(cid)
+	exist := &model.ContentDoc{}
+	if err := readContent(txn, key, exist); err != nil {
+		return exist, fmt.Errorf("error in removing document %s", err)
+	}
+	if exist.NotFound {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"document with ID %d not found",
+			cid,
+		)
+	}
+	if err := txn.Bucket(contentBucket).Delete(key); err != nil {
+		return exist, fmt.Errorf("error in removing document %s", err)
+	}
+	if err := txn.Bucket(slugBucket).Delete([]byte(exist.Slug)); err != nil {
+		return exist, fmt.Errorf("error in removing slug %s", err)
+	}
+
+	return exist, nil
+}
+
+func list(
+	txn *bolt.Tx,
+	namespaces, slugs []string,
+) ([]*model.ContentDoc, error) {
+	nsps := toSet(namespaces)
+	slgs := toSet(slugs)
+	cntModels := make([]*model.ContentDoc, 0)
+	err := txn.Bucket(contentBucket).ForEach(func(_, val []byte) error {
+		cnt := &model.ContentDoc{}
+		if err := json.Unmarshal(val, cnt); err != nil {
+			return fmt.Errorf("error in decoding content %s", err)
+		}
+		if len(nsps) > 0 && !nsps[cnt.Namespace] {
+			return nil
+		}
+		if len(slgs) > 0 && !slgs[cnt.Slug] {
+			return nil
+		}
+		cntModels = append(cntModels, cnt)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(cntModels, func(i, j int) bool {
+		if cntModels[i].Namespace != cntModels[j].Namespace {
+			return cntModels[i].Namespace < cntModels[j].Namespace
+		}
+
+		return cntModels[i].Slug < cntModels[j].Slug
+	})
+
+	return cntModels, nil
+}
+
+// readContent decodes the content stored under the key, NotFound is set
+// when there is none.
+func readContent(txn *bolt.Tx, key []byte, cnt *model.ContentDoc) error {
+	val := txn.Bucket(contentBucket).Get(key)
+	if val == nil {
+		cnt.NotFound = true
+
+		return nil
+	}
+	if err := json.Unmarshal(val, cnt); err != nil {
+		return fmt.Errorf("error in decoding content %s", err)
+	}
+
+	return nil
+}
+
+func nextKey(txn *bolt.Tx) (string, error) {
+	seq, err := txn.Bucket(contentBucket).NextSequence()
+	if err != nil {
+		return "", fmt.Errorf("error in generating id %s", err)
+	}
+
+	return strconv.FormatUint(seq, 10), nil
+}
+
+func countKeys(dbh *bolt.DB, name []byte) (*repository.Stats, error) {
+	stats := &repository.Stats{Backend: "bolt"}
+	err := dbh.View(func(txn *bolt.Tx) error {
+		stats.Records = int64(txn.Bucket(name).Stats().KeyN)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in counting keys %s", err)
+	}
+
+	return stats, nil
+}
+
+func createBuckets(txn *bolt.Tx, names ...[]byte) error {
+	for _, name := range names {
+		if _, err := txn.CreateBucketIfNotExists(name); err != nil {
+			return fmt.Errorf("error in creating bucket %s %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+func recreateBuckets(txn *bolt.Tx, names ...[]byte) error {
+	for _, name := range names {
+		if err := txn.DeleteBucket(name); err != nil &&
+			err != bolt.ErrBucketNotFound {
+			return fmt.Errorf("error in removing bucket %s %s", name, err)
+		}
+	}
+
+	return createBuckets(txn, names...)
+}
+
+// itob encodes the id in big endian so that the keys are kept in
+// numerical order.
+func itob(cid int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(cid))
+
+	return key
+}
+
+func btoi(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key))
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, val := range values {
+		set[val] = true
+	}
+
+	return set
+}
@@ -0,0 +1,130 @@
+package boltdb
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/conformance"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	conformance.RunAll(t, func(t *testing.T) *conformance.Repositories {
+		t.Helper()
+		assert := require.New(t)
+		dbh, err := Open(filepath.Join(t.TempDir(), "content.db"), time.Second)
+		assert.NoErrorf(err, "expect no error from opening database %s", err)
+		t.Cleanup(func() { dbh.Close() })
+		repo, err := NewContentRepo(dbh)
+		assert.NoErrorf(err, "expect no error from content repository %s", err)
+		aud, err := NewAuditRepo(dbh)
+		assert.NoErrorf(err, "expect no error from audit repository %s", err)
+		lck, err := NewLockRepo(dbh)
+		assert.NoErrorf(err, "expect no error from lock repository %s", err)
+		ast, err := NewAssetRepo(dbh)
+		assert.NoErrorf(err, "expect no error from asset repository %s", err)
+		lnk, err := NewLinkRepo(dbh)
+		assert.NoErrorf(err, "expect no error from link repository %s", err)
+
+		return &conformance.Repositories{
+			Content: repo,
+			Audit:   aud,
+			Locks:   lck,
+			Assets:  ast,
+			Links:   lnk,
+		}
+	})
+}
+
+func TestReopen(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	path := filepath.Join(t.TempDir(), "content.db")
+	dbh, err := Open(path, time.Second)
+	assert.NoErrorf(err, "expect no error from opening database %s", err)
+	repo, err := NewContentRepo(dbh)
+	assert.NoErrorf(err, "expect no error from content repository %s", err)
+	nsrepo, err := NewNamespaceRepo(dbh)
+	assert.NoErrorf(err, "expect no error from namespace repository %s", err)
+	nct, err := repo.AddContent(ctx, testutils.NewStoreContent("catalog", "dsc"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	_, err = nsrepo.AddNamespace(ctx, &model.NamespaceDoc{
+		Name:        "dsc",
+		DisplayName: "Dicty Stock Center",
+		CreatedBy:   "content@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	assert.NoError(dbh.Close(), "expect no error from closing database")
+
+	dbh, err = Open(path, time.Second)
+	assert.NoErrorf(err, "expect no error from reopening database %s", err)
+	defer dbh.Close()
+	repo, err = NewContentRepo(dbh)
+	assert.NoErrorf(err, "expect no error from content repository %s", err)
+	sct, err := repo.GetContentBySlug(ctx, "catalog-dsc")
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.False(sct.NotFound, "should find the stored content")
+	assert.Equal(sct.Key, nct.Key, "key should match")
+	nct2, err := repo.AddContent(ctx, testutils.NewStoreContent("price", "dsc"))
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	assert.NotEqual(nct2.Key, nct.Key, "should not reuse key")
+	nsrepo, err = NewNamespaceRepo(dbh)
+	assert.NoErrorf(err, "expect no error from namespace repository %s", err)
+	nsp, err := nsrepo.GetNamespace(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from getting namespace %s", err)
+	assert.False(nsp.NotFound, "should find the stored namespace")
+}
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	path := filepath.Join(t.TempDir(), "content.db")
+	dbh, err := Open(path, time.Second)
+	assert.NoErrorf(err, "expect no error from opening database %s", err)
+	repo, err := NewContentRepo(dbh)
+	assert.NoErrorf(err, "expect no error from content repository %s", err)
+	var keep *model.ContentDoc
+	for idx := 0; idx < 500; idx++ {
+		nct, err := repo.AddContent(
+			ctx,
+			testutils.NewStoreContent(fmt.Sprintf("page%d", idx), "dsc"),
+		)
+		assert.NoErrorf(err, "expect no error from creating content %s", err)
+		keep = nct
+	}
+	cnts, err := repo.ListContents(ctx, nil)
+	assert.NoErrorf(err, "expect no error from listing contents %s", err)
+	for _, cnt := range cnts[1:] {
+		if cnt.Key == keep.Key {
+			continue
+		}
+		cid, err := strconv.ParseInt(cnt.Key, 10, 64)
+		assert.NoErrorf(err, "expect no error from parsing key %s", err)
+		assert.NoErrorf(repo.DeleteContent(ctx, cid), "expect no error from delete")
+	}
+	assert.NoError(dbh.Close(), "expect no error from closing database")
+	stats, err := Compact(path, time.Second)
+	assert.NoErrorf(err, "expect no error from compaction %s", err)
+	assert.Less(stats.After, stats.Before, "should shrink the database file")
+
+	dbh, err = Open(path, time.Second)
+	assert.NoErrorf(err, "expect no error from reopening database %s", err)
+	defer dbh.Close()
+	repo, err = NewContentRepo(dbh)
+	assert.NoErrorf(err, "expect no error from content repository %s", err)
+	sct, err := repo.GetContentBySlug(ctx, keep.Slug)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.False(sct.NotFound, "should keep the content after compaction")
+	cnts, err = repo.ListContents(ctx, nil)
+	assert.NoErrorf(err, "expect no error from listing contents %s", err)
+	assert.Len(cnts, 2, "should have two contents after compaction")
+}
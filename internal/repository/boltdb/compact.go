@@ -0,0 +1,67 @@
+package boltdb
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// maximum size of a single transaction while copying during compaction.
+const compactTxSize = 64 * 1024 * 1024
+
+// CompactStats is the size of the database file before and after compaction.
+type CompactStats struct {
+	Before int64
+	After  int64
+}
+
+// Compact rewrites the database file without the free pages left behind by
+// updates and deletes. The data is copied to a temporary file next to the
+// database which then replaces it, an interrupted compaction leaves the
+// original file untouched. The database must not be in use by the server.
+func Compact(path string, timeout time.Duration) (*CompactStats, error) {
+	stats := &CompactStats{}
+	info, err := os.Stat(path)
+	if err != nil {
+		return stats, fmt.Errorf("error in reading database file %s", err)
+	}
+	stats.Before = info.Size()
+	src, err := bolt.Open(
+		path, 0o600, &bolt.Options{Timeout: timeout, ReadOnly: true},
+	)
+	if err != nil {
+		return stats, fmt.Errorf("error in opening database file %s", err)
+	}
+	defer src.Close()
+	tmpPath := path + ".compact"
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return stats, fmt.Errorf("error in removing stale file %s", err)
+	}
+	dst, err := Open(tmpPath, timeout)
+	if err != nil {
+		return stats, err
+	}
+	if err := bolt.Compact(dst, src, compactTxSize); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+
+		return stats, fmt.Errorf("error in compacting database %s", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+
+		return stats, fmt.Errorf("error in closing compacted database %s", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return stats, fmt.Errorf("error in replacing database file %s", err)
+	}
+	info, err = os.Stat(path)
+	if err != nil {
+		return stats, fmt.Errorf("error in reading database file %s", err)
+	}
+	stats.After = info.Size()
+
+	return stats, nil
+}
@@ -0,0 +1,172 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// the links of every content keyed by its id, ordered by target.
+var linkBucket = []byte("link")
+
+type linkrepository struct {
+	dbh *bolt.DB
+}
+
+func NewLinkRepo(dbh *bolt.DB) (repository.LinkRepository, error) {
+	err := dbh.Update(func(txn *bolt.Tx) error {
+		return createBuckets(txn, linkBucket)
+	})
+	if err != nil {
+		return &linkrepository{}, err
+	}
+
+	return &linkrepository{dbh: dbh}, nil
+}
+
+func (lrp *linkrepository) SetLinks(
+	_ context.Context,
+	src *model.LinkDoc,
+	targets []string,
+) error {
+	err := lrp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(linkBucket)
+		if len(targets) == 0 {
+			return bkt.Delete(itob(src.ContentID))
+		}
+		data, err := json.Marshal(links.NewLinks(src, targets))
+		if err != nil {
+			return fmt.Errorf("error in encoding links %s", err)
+		}
+
+		return bkt.Put(itob(src.ContentID), data)
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"error in setting links of content %d %s",
+			src.ContentID,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (lrp *linkrepository) OutgoingLinks(
+	_ context.Context,
+	cid int64,
+) ([]*model.LinkDoc, error) {
+	lnks := make([]*model.LinkDoc, 0)
+	err := lrp.dbh.View(func(txn *bolt.Tx) error {
+		data := txn.Bucket(linkBucket).Get(itob(cid))
+		if data == nil {
+			return nil
+		}
+
+		return json.Unmarshal(data, &lnks)
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error in reading links of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return lnks, nil
+}
+
+func (lrp *linkrepository) Backlinks(
+	_ context.Context,
+	slug string,
+) ([]*model.LinkDoc, error) {
+	return lrp.scan(func(lnk *model.LinkDoc) bool {
+		return lnk.Target == slug
+	})
+}
+
+func (lrp *linkrepository) ListLinks(
+	_ context.Context,
+	namespace string,
+) ([]*model.LinkDoc, error) {
+	return lrp.scan(func(lnk *model.LinkDoc) bool {
+		return len(namespace) == 0 || lnk.Namespace == namespace
+	})
+}
+
+// scan walks over the links of all the contents in the order of their ids.
+func (lrp *linkrepository) scan(
+	match func(*model.LinkDoc) bool,
+) ([]*model.LinkDoc, error) {
+	lnks := make([]*model.LinkDoc, 0)
+	err := lrp.dbh.View(func(txn *bolt.Tx) error {
+		return txn.Bucket(linkBucket).ForEach(func(_, data []byte) error {
+			var clnks []*model.LinkDoc
+			if err := json.Unmarshal(data, &clnks); err != nil {
+				return fmt.Errorf("error in decoding links %s", err)
+			}
+			for _, lnk := range clnks {
+				if match(lnk) {
+					lnks = append(lnks, lnk)
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in reading links %s", err)
+	}
+
+	return lnks, nil
+}
+
+// Drop removes all the stored links, the database file is left as is.
+func (lrp *linkrepository) Drop() error {
+	err := lrp.dbh.Update(func(txn *bolt.Tx) error {
+		return recreateBuckets(txn, linkBucket)
+	})
+	if err != nil {
+		return fmt.Errorf("error in dropping links %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database file is still open.
+func (lrp *linkrepository) Ping(_ context.Context) error {
+	return lrp.dbh.View(func(txn *bolt.Tx) error {
+		if txn.Bucket(linkBucket) == nil {
+			return fmt.Errorf("bucket %s is missing", linkBucket)
+		}
+
+		return nil
+	})
+}
+
+// Close closes the database file, it is shared by all the repositories of
+// the file so closing any one of them closes the others.
+func (lrp *linkrepository) Close() error {
+	if err := lrp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of stored links.
+func (lrp *linkrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	lnks, err := lrp.ListLinks(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Stats{Backend: "bolt", Records: int64(len(lnks))}, nil
+}
@@ -0,0 +1,247 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+var lockBucket = []byte("lock")
+
+type lockrepository struct {
+	dbh *bolt.DB
+}
+
+// NewLockRepo creates the lock repository, the locks are keyed by the
+// content id and a lapsed lease stays until it is taken over or released.
+func NewLockRepo(dbh *bolt.DB) (repository.LockRepository, error) {
+	err := dbh.Update(func(txn *bolt.Tx) error {
+		return createBuckets(txn, lockBucket)
+	})
+	if err != nil {
+		return &lockrepository{}, err
+	}
+
+	return &lockrepository{dbh: dbh}, nil
+}
+
+func (lrp *lockrepository) AcquireLock(
+	_ context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lck := &model.LockDoc{}
+	err := lrp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(lockBucket)
+		now := time.Now().UTC()
+		found, err := readLock(bkt, cid, lck)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !found || lck.Lapsed(now):
+			*lck = model.LockDoc{
+				ContentID:  cid,
+				Holder:     holder,
+				AcquiredOn: now,
+			}
+			lck.Key = strconv.FormatInt(cid, 10)
+		case lck.Holder != holder:
+			return repository.ErrLockHeld
+		}
+		lck.ExpiresOn = now.Add(ttl)
+
+		return writeLock(bkt, lck)
+	})
+	if err != nil {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in acquiring lock of content %d %w",
+			cid,
+			err,
+		)
+	}
+
+	return lck, nil
+}
+
+func (lrp *lockrepository) RenewLock(
+	_ context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lck := &model.LockDoc{}
+	err := lrp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(lockBucket)
+		now := time.Now().UTC()
+		found, err := readLock(bkt, cid, lck)
+		if err != nil {
+			return err
+		}
+		if !found || lck.Lapsed(now) || lck.Holder != holder {
+			return repository.ErrLockNotHeld
+		}
+		lck.ExpiresOn = now.Add(ttl)
+
+		return writeLock(bkt, lck)
+	})
+	if err != nil {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in renewing lock of content %d %w",
+			cid,
+			err,
+		)
+	}
+
+	return lck, nil
+}
+
+func (lrp *lockrepository) ReleaseLock(
+	_ context.Context,
+	cid int64,
+	holder string,
+) error {
+	err := lrp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(lockBucket)
+		lck := &model.LockDoc{}
+		found, err := readLock(bkt, cid, lck)
+		if err != nil {
+			return err
+		}
+		if !found || lck.Holder != holder {
+			return repository.ErrLockNotHeld
+		}
+
+		return bkt.Delete(itob(cid))
+	})
+	if err != nil {
+		return fmt.Errorf("error in releasing lock of content %d %w", cid, err)
+	}
+
+	return nil
+}
+
+func (lrp *lockrepository) BreakLock(
+	_ context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	lck := &model.LockDoc{}
+	err := lrp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(lockBucket)
+		found, err := readLock(bkt, cid, lck)
+		if err != nil {
+			return err
+		}
+		if !found {
+			lck.NotFound = true
+
+			return nil
+		}
+
+		return bkt.Delete(itob(cid))
+	})
+	if err != nil {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in breaking lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return lck, nil
+}
+
+func (lrp *lockrepository) GetLock(
+	_ context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	lck := &model.LockDoc{}
+	err := lrp.dbh.View(func(txn *bolt.Tx) error {
+		found, err := readLock(txn.Bucket(lockBucket), cid, lck)
+		if err != nil {
+			return err
+		}
+		if !found || lck.Lapsed(time.Now()) {
+			*lck = model.LockDoc{NotFound: true}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in finding lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return lck, nil
+}
+
+// Drop removes all the stored locks, the database file is left as is.
+func (lrp *lockrepository) Drop() error {
+	err := lrp.dbh.Update(func(txn *bolt.Tx) error {
+		return recreateBuckets(txn, lockBucket)
+	})
+	if err != nil {
+		return fmt.Errorf("error in dropping locks %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database file is still open.
+func (lrp *lockrepository) Ping(_ context.Context) error {
+	return lrp.dbh.View(func(txn *bolt.Tx) error {
+		if txn.Bucket(lockBucket) == nil {
+			return fmt.Errorf("bucket %s is missing", lockBucket)
+		}
+
+		return nil
+	})
+}
+
+// Close closes the database file, it is shared by all the repositories of
+// the file so closing any one of them closes the others.
+func (lrp *lockrepository) Close() error {
+	if err := lrp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of stored locks.
+func (lrp *lockrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	return countKeys(lrp.dbh, lockBucket)
+}
+
+func readLock(bkt *bolt.Bucket, cid int64, lck *model.LockDoc) (bool, error) {
+	data := bkt.Get(itob(cid))
+	if data == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, lck); err != nil {
+		return false, fmt.Errorf("error in decoding lock %s", err)
+	}
+
+	return true, nil
+}
+
+func writeLock(bkt *bolt.Bucket, lck *model.LockDoc) error {
+	data, err := json.Marshal(lck)
+	if err != nil {
+		return fmt.Errorf("error in encoding lock %s", err)
+	}
+
+	return bkt.Put(itob(lck.ContentID), data)
+}
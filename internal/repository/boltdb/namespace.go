@@ -0,0 +1,209 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+	bolt "go.etcd.io/bbolt"
+)
+
+var namespaceBucket = []byte("namespace")
+
+type namespacerepository struct {
+	dbh      *bolt.DB
+	validate *validator.Validate
+}
+
+func NewNamespaceRepo(dbh *bolt.DB) (repository.NamespaceRepository, error) {
+	err := dbh.Update(func(txn *bolt.Tx) error {
+		return createBuckets(txn, namespaceBucket)
+	})
+	if err != nil {
+		return &namespacerepository{}, err
+	}
+
+	return &namespacerepository{dbh: dbh, validate: validator.New()}, nil
+}
+
+func (nrp *namespacerepository) AddNamespace(
+	_ context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	if err := nrp.validate.Struct(nsp); err != nil {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in creating new namespace %s",
+			err,
+		)
+	}
+	now := time.Now().UTC()
+	doc := &model.NamespaceDoc{
+		Name:          nsp.Name,
+		DisplayName:   nsp.DisplayName,
+		EditorsGroup:  nsp.EditorsGroup,
+		ContentSchema: nsp.ContentSchema,
+		CreatedBy:     nsp.CreatedBy,
+		CreatedOn:     now,
+		UpdatedOn:     now,
+	}
+	err := nrp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(namespaceBucket)
+		if bkt.Get([]byte(nsp.Name)) != nil {
+			return fmt.Errorf("%s already exists", nsp.Name)
+		}
+		seq, err := bkt.NextSequence()
+		if err != nil {
+			return fmt.Errorf("error in generating id %s", err)
+		}
+		doc.Key = strconv.FormatUint(seq, 10)
+
+		return putNamespace(bkt, doc)
+	})
+	if err != nil {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in creating new namespace %s",
+			err,
+		)
+	}
+
+	return doc, nil
+}
+
+func (nrp *namespacerepository) GetNamespace(
+	_ context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nspModel := &model.NamespaceDoc{}
+	err := nrp.dbh.View(func(txn *bolt.Tx) error {
+		return readNamespace(txn.Bucket(namespaceBucket), name, nspModel)
+	})
+	if err != nil {
+		return nspModel, fmt.Errorf("error in getting namespace %s", err)
+	}
+
+	return nspModel, nil
+}
+
+func (nrp *namespacerepository) ListNamespaces(
+	_ context.Context,
+) ([]*model.NamespaceDoc, error) {
+	nspModels := make([]*model.NamespaceDoc, 0)
+	err := nrp.dbh.View(func(txn *bolt.Tx) error {
+		return txn.Bucket(namespaceBucket).ForEach(func(_, val []byte) error {
+			nspModel := &model.NamespaceDoc{}
+			if err := json.Unmarshal(val, nspModel); err != nil {
+				return fmt.Errorf("error in decoding namespace %s", err)
+			}
+			nspModels = append(nspModels, nspModel)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nspModels, fmt.Errorf("error in listing namespaces %s", err)
+	}
+
+	return nspModels, nil
+}
+
+func (nrp *namespacerepository) ArchiveNamespace(
+	_ context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nspModel := &model.NamespaceDoc{}
+	err := nrp.dbh.Update(func(txn *bolt.Tx) error {
+		bkt := txn.Bucket(namespaceBucket)
+		if err := readNamespace(bkt, name, nspModel); err != nil {
+			return err
+		}
+		if nspModel.NotFound {
+			return nil
+		}
+		nspModel.Archived = true
+		nspModel.UpdatedOn = time.Now().UTC()
+
+		return putNamespace(bkt, nspModel)
+	})
+	if err != nil {
+		return nspModel, fmt.Errorf("error in archiving namespace %s", err)
+	}
+
+	return nspModel, nil
+}
+
+// Drop removes all the stored namespaces, the database file is left as is.
+func (nrp *namespacerepository) Drop() error {
+	err := nrp.dbh.Update(func(txn *bolt.Tx) error {
+		return recreateBuckets(txn, namespaceBucket)
+	})
+	if err != nil {
+		return fmt.Errorf("error in dropping namespaces %s", err)
+	}
+
+	return nil
+}
+
+// Ping checks that the database file is still open.
+func (nrp *namespacerepository) Ping(
+	_ context.Context,
+) error {
+	return nrp.dbh.View(func(txn *bolt.Tx) error {
+		if txn.Bucket(namespaceBucket) == nil {
+			return fmt.Errorf("bucket %s is missing", namespaceBucket)
+		}
+
+		return nil
+	})
+}
+
+// Close closes the database file, it is shared by all the repositories of
+// the file so closing any one of them closes the others.
+func (nrp *namespacerepository) Close() error {
+	if err := nrp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of stored namespaces.
+func (nrp *namespacerepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	return countKeys(nrp.dbh, namespaceBucket)
+}
+
+func readNamespace(
+	bkt *bolt.Bucket,
+	name string,
+	nsp *model.NamespaceDoc,
+) error {
+	val := bkt.Get([]byte(name))
+	if val == nil {
+		nsp.NotFound = true
+
+		return nil
+	}
+	if err := json.Unmarshal(val, nsp); err != nil {
+		return fmt.Errorf("error in decoding namespace %s", err)
+	}
+
+	return nil
+}
+
+func putNamespace(bkt *bolt.Bucket, nsp *model.NamespaceDoc) error {
+	data, err := json.Marshal(nsp)
+	if err != nil {
+		return fmt.Errorf("error in encoding namespace %s", err)
+	}
+	if err := bkt.Put([]byte(nsp.Name), data); err != nil {
+		return fmt.Errorf("error in storing namespace %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package conformance
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+var assetCases = []suiteCase[repository.AssetRepository]{
+	{name: "AddAsset", fn: testAddAsset},
+	{name: "InvalidAsset", fn: testInvalidAsset},
+	{name: "AssetReferences", fn: testAssetReferences},
+	{name: "RemoveAsset", fn: testRemoveAsset},
+	{name: "AssetLifecycle", fn: testAssetLifecycle},
+}
+
+func addAsset(
+	assert *require.Assertions,
+	repo repository.AssetRepository,
+	name string,
+) int64 {
+	ast, err := repo.AddAsset(context.Background(), &model.AssetDoc{
+		Name:      name,
+		MimeType:  "image/png",
+		Size:      1024,
+		Checksum:  model.HashContent(name),
+		BlobKey:   "blob-" + name,
+		CreatedBy: holder,
+	})
+	assert.NoErrorf(err, "expect no error from adding asset %s", err)
+	aid, err := strconv.ParseInt(ast.Key, 10, 64)
+	assert.NoErrorf(err, "expect numeric key of asset %s", err)
+
+	return aid
+}
+
+func testAddAsset(
+	assert *require.Assertions,
+	repo repository.AssetRepository,
+) {
+	aid := addAsset(assert, repo, "strain.png")
+	ast, err := repo.GetAsset(context.Background(), aid)
+	assert.NoErrorf(err, "expect no error from fetching asset %s", err)
+	assert.False(ast.NotFound, "expect asset to be found")
+	assert.Equal(ast.Name, "strain.png", "name should match")
+	assert.Equal(ast.MimeType, "image/png", "mime type should match")
+	assert.Equal(ast.Size, int64(1024), "size should match")
+	assert.Equal(ast.Checksum, model.HashContent("strain.png"), "checksum")
+	assert.Equal(ast.BlobKey, "blob-strain.png", "blob key should match")
+	assert.Equal(ast.CreatedBy, holder, "created by should match")
+	assert.True(ast.CreatedOn.Before(time.Now()), "should have created on")
+	assert.Empty(ast.References, "expect no references")
+	ast, err = repo.GetAsset(context.Background(), aid+100)
+	assert.NoErrorf(err, "expect no error from fetching missing asset %s", err)
+	assert.True(ast.NotFound, "expect missing asset")
+}
+
+func testInvalidAsset(
+	assert *require.Assertions,
+	repo repository.AssetRepository,
+) {
+	_, err := repo.AddAsset(context.Background(), &model.AssetDoc{
+		Name:      "strain.png",
+		MimeType:  "image/png",
+		Checksum:  "abc",
+		BlobKey:   "blob",
+		CreatedBy: "curator",
+	})
+	assert.Error(err, "expect error for invalid email")
+}
+
+func testAssetReferences(
+	assert *require.Assertions,
+	repo repository.AssetRepository,
+) {
+	ctx := context.Background()
+	first := addAsset(assert, repo, "strain.png")
+	second := addAsset(assert, repo, "plasmid.png")
+	err := repo.SetReferences(ctx, 20, []int64{first, second, second + 100})
+	assert.NoErrorf(err, "expect no error from setting references %s", err)
+	err = repo.SetReferences(ctx, 10, []int64{first})
+	assert.NoErrorf(err, "expect no error from setting references %s", err)
+	ast, err := repo.GetAsset(ctx, first)
+	assert.NoErrorf(err, "expect no error from fetching asset %s", err)
+	assert.Equal(ast.References, []int64{10, 20}, "should match references")
+	err = repo.SetReferences(ctx, 20, []int64{second})
+	assert.NoErrorf(err, "expect no error from replacing references %s", err)
+	ast, err = repo.GetAsset(ctx, first)
+	assert.NoErrorf(err, "expect no error from fetching asset %s", err)
+	assert.Equal(ast.References, []int64{10}, "should drop replaced reference")
+	ast, err = repo.GetAsset(ctx, second)
+	assert.NoErrorf(err, "expect no error from fetching asset %s", err)
+	assert.Equal(ast.References, []int64{20}, "should keep the reference")
+	err = repo.SetReferences(ctx, 10, nil)
+	assert.NoErrorf(err, "expect no error from clearing references %s", err)
+	ast, err = repo.GetAsset(ctx, first)
+	assert.NoErrorf(err, "expect no error from fetching asset %s", err)
+	assert.Empty(ast.References, "expect references to be cleared")
+}
+
+func testRemoveAsset(
+	assert *require.Assertions,
+	repo repository.AssetRepository,
+) {
+	ctx := context.Background()
+	aid := addAsset(assert, repo, "strain.png")
+	err := repo.SetReferences(ctx, 10, []int64{aid})
+	assert.NoErrorf(err, "expect no error from setting references %s", err)
+	assert.NoError(repo.RemoveAsset(ctx, aid), "expect no error from removal")
+	ast, err := repo.GetAsset(ctx, aid)
+	assert.NoErrorf(err, "expect no error from fetching asset %s", err)
+	assert.True(ast.NotFound, "expect removed asset to be missing")
+	assert.Error(repo.RemoveAsset(ctx, aid), "expect error for missing asset")
+}
+
+func testAssetLifecycle(
+	assert *require.Assertions,
+	repo repository.AssetRepository,
+) {
+	ctx := context.Background()
+	assert.NoError(repo.Ping(ctx), "expect no error from ping")
+	addAsset(assert, repo, "strain.png")
+	addAsset(assert, repo, "plasmid.png")
+	stats, err := repo.Stats(ctx)
+	assert.NoErrorf(err, "expect no error from stats %s", err)
+	assert.Equal(stats.Records, int64(2), "expect two stored assets")
+}
@@ -0,0 +1,189 @@
+package conformance
+
+import (
+	"context"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+var auditCases = []suiteCase[repository.AuditRepository]{
+	{name: "AddEntry", fn: testAddEntry},
+	{name: "InvalidEntry", fn: testInvalidEntry},
+	{name: "ListEntries", fn: testListEntries},
+	{name: "RenameEntry", fn: testRenameEntry},
+	{name: "ListEntriesByTime", fn: testListEntriesByTime},
+	{name: "AuditLifecycle", fn: testAuditLifecycle},
+}
+
+func newEntry(actor, operation, namespace string) *model.AuditEntry {
+	return &model.AuditEntry{
+		Actor:      actor,
+		Operation:  operation,
+		ContentID:  10,
+		Slug:       "catalog-" + namespace,
+		Namespace:  namespace,
+		BeforeHash: "",
+		AfterHash:  model.ContentHash(&model.ContentDoc{Content: "catalog"}),
+		Client:     map[string]string{"peer": "127.0.0.1:4000"},
+	}
+}
+
+func addEntries(
+	assert *require.Assertions,
+	repo repository.AuditRepository,
+	ents ...*model.AuditEntry,
+) {
+	for _, ent := range ents {
+		_, err := repo.AddEntry(context.Background(), ent)
+		assert.NoErrorf(err, "expect no error from adding entry %s", err)
+	}
+}
+
+func testAddEntry(
+	assert *require.Assertions,
+	repo repository.AuditRepository,
+) {
+	ent, err := repo.AddEntry(
+		context.Background(),
+		newEntry("curator@content.org", model.AuditStore, "dsc"),
+	)
+	assert.NoErrorf(err, "expect no error from adding entry %s", err)
+	assert.NotEmpty(ent.Key, "expect entry to have a key")
+	assert.Equal(ent.Actor, "curator@content.org", "actor should match")
+	assert.Equal(ent.Operation, model.AuditStore, "operation should match")
+	assert.Equal(ent.ContentID, int64(10), "content id should match")
+	assert.Equal(ent.Slug, "catalog-dsc", "slug should match")
+	assert.Empty(ent.BeforeHash, "expect no before hash")
+	assert.Len(ent.AfterHash, 64, "expect hex encoded sha256 after hash")
+	assert.Equal(
+		ent.Client,
+		map[string]string{"peer": "127.0.0.1:4000"},
+		"client metadata should match",
+	)
+	assert.True(
+		ent.CreatedOn.Before(time.Now()),
+		"should have created before the current time",
+	)
+}
+
+func testInvalidEntry(
+	assert *require.Assertions,
+	repo repository.AuditRepository,
+) {
+	ctx := context.Background()
+	_, err := repo.AddEntry(ctx, newEntry("", model.AuditStore, "dsc"))
+	assert.Error(err, "expect error for missing actor")
+	_, err = repo.AddEntry(ctx, newEntry("curator@content.org", "copy", "dsc"))
+	assert.Error(err, "expect error for unknown operation")
+}
+
+func testListEntries(
+	assert *require.Assertions,
+	repo repository.AuditRepository,
+) {
+	ctx := context.Background()
+	addEntries(
+		assert, repo,
+		newEntry("curator@content.org", model.AuditStore, "dsc"),
+		newEntry("curator@content.org", model.AuditUpdate, "dsc"),
+		newEntry("editor@content.org", model.AuditStore, "dictybase"),
+		newEntry("curator@content.org", model.AuditDelete, "dictybase"),
+	)
+	ents, err := repo.ListEntries(ctx, &model.AuditFilter{})
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 4, "expect all entries")
+	assert.Equal(ents[0].Operation, model.AuditStore, "should be oldest first")
+	assert.Equal(ents[3].Operation, model.AuditDelete, "should be oldest first")
+	ents, err = repo.ListEntries(
+		ctx,
+		&model.AuditFilter{Actor: "curator@content.org"},
+	)
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 3, "expect entries of the actor")
+	ents, err = repo.ListEntries(
+		ctx,
+		&model.AuditFilter{
+			Actor:     "curator@content.org",
+			Namespace: "dictybase",
+		},
+	)
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 1, "expect entries of the actor in the namespace")
+	assert.Equal(ents[0].Operation, model.AuditDelete, "operation should match")
+	ents, err = repo.ListEntries(ctx, &model.AuditFilter{Limit: 2})
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 2, "expect entries up to the limit")
+	assert.Equal(ents[1].Operation, model.AuditUpdate, "should be oldest first")
+}
+
+func testRenameEntry(
+	assert *require.Assertions,
+	repo repository.AuditRepository,
+) {
+	ctx := context.Background()
+	ent := newEntry("curator@content.org", model.AuditRename, "dictybase")
+	ent.FromSlug = "catalog-dsc"
+	ent.FromNamespace = "dsc"
+	addEntries(
+		assert, repo,
+		newEntry("curator@content.org", model.AuditStore, "dsc"),
+		ent,
+	)
+	for _, namespace := range []string{"dsc", "dictybase"} {
+		ents, err := repo.ListEntries(
+			ctx,
+			&model.AuditFilter{Namespace: namespace},
+		)
+		assert.NoErrorf(err, "expect no error from listing entries %s", err)
+		rnm := ents[len(ents)-1]
+		assert.Equal(rnm.Operation, model.AuditRename, "expect the rename")
+		assert.Equal(rnm.FromSlug, "catalog-dsc", "from slug should match")
+		assert.Equal(rnm.FromNamespace, "dsc", "from namespace should match")
+		assert.Equal(rnm.Namespace, "dictybase", "namespace should match")
+	}
+}
+
+func testListEntriesByTime(
+	assert *require.Assertions,
+	repo repository.AuditRepository,
+) {
+	ctx := context.Background()
+	addEntries(assert, repo, newEntry("curator@content.org", model.AuditStore, "dsc"))
+	time.Sleep(50 * time.Millisecond)
+	mark := time.Now()
+	time.Sleep(50 * time.Millisecond)
+	addEntries(assert, repo, newEntry("curator@content.org", model.AuditUpdate, "dsc"))
+	ents, err := repo.ListEntries(ctx, &model.AuditFilter{From: mark})
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 1, "expect entries after the mark")
+	assert.Equal(ents[0].Operation, model.AuditUpdate, "operation should match")
+	ents, err = repo.ListEntries(ctx, &model.AuditFilter{To: mark})
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 1, "expect entries before the mark")
+	assert.Equal(ents[0].Operation, model.AuditStore, "operation should match")
+	ents, err = repo.ListEntries(
+		ctx,
+		&model.AuditFilter{From: mark, To: mark.Add(time.Hour)},
+	)
+	assert.NoErrorf(err, "expect no error from listing entries %s", err)
+	assert.Len(ents, 1, "expect entries within the range")
+}
+
+func testAuditLifecycle(
+	assert *require.Assertions,
+	repo repository.AuditRepository,
+) {
+	ctx := context.Background()
+	assert.NoError(repo.Ping(ctx), "expect no error from ping")
+	addEntries(
+		assert, repo,
+		newEntry("curator@content.org", model.AuditStore, "dsc"),
+		newEntry("curator@content.org", model.AuditUpdate, "dsc"),
+	)
+	stats, err := repo.Stats(ctx)
+	assert.NoErrorf(err, "expect no error from stats %s", err)
+	assert.Equal(stats.Records, int64(2), "expect two stored entries")
+}
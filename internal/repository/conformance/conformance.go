@@ -0,0 +1,499 @@
+// Package conformance is a test suite that the repositories of every
+// backend are expected to pass, a backend runs all the suites with RunAll.
+// It defines the shared behavior of the content repositories,
+//
+//   - missing contents are reported through NotFound with no error.
+//   - the body of a content is kept verbatim, json or not.
+//   - a duplicate slug or an invalid email is an error.
+//   - created_on and updated_on are identical for a new content and
+//     updated_on moves forward on every update.
+//   - update and delete of a missing content is an error.
+//   - failure of an atomic batch or a transfer leaves no trace.
+//   - the storage answers ping and stats count the stored contents.
+//
+// The audit suite lists the entries oldest first and the time range of a
+// filter includes both ends. The lock suite treats a lapsed lease as no
+// lease.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+var testCases = []suiteCase[repository.ContentRepository]{
+	{name: "AddContent", fn: testAddContent},
+	{name: "DuplicateSlug", fn: testDuplicateSlug},
+	{name: "InvalidEmail", fn: testInvalidEmail},
+	{name: "GetContentBySlug", fn: testGetContentBySlug},
+	{name: "GetContent", fn: testGetContent},
+	{name: "NotFound", fn: testNotFound},
+	{name: "VerbatimContent", fn: testVerbatimContent},
+	{name: "EditContent", fn: testEditContent},
+	{name: "EditMissingContent", fn: testEditMissingContent},
+	{name: "ContentHash", fn: testContentHash},
+	{name: "DeleteContent", fn: testDeleteContent},
+	{name: "ListContents", fn: testListContents},
+	{name: "ImportContent", fn: testImportContent},
+	{name: "TransferContents", fn: testTransferContents},
+	{name: "TransferRollback", fn: testTransferRollback},
+	{name: "BatchContents", fn: testBatchContents},
+	{name: "BatchRollback", fn: testBatchRollback},
+	{name: "Lifecycle", fn: testLifecycle},
+	{name: "CountByNamespace", fn: testCountByNamespace},
+}
+
+func addContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+	name, namespace string,
+) (*model.ContentDoc, int64) {
+	nct, err := repo.AddContent(
+		context.Background(),
+		testutils.NewStoreContent(name, namespace),
+	)
+	assert.NoErrorf(err, "expect no error from creating content %s", err)
+	key, err := strconv.ParseInt(nct.Key, 10, 64)
+	assert.NoErrorf(
+		err,
+		"expect no error from string to int64 conversion of key %s",
+		err,
+	)
+
+	return nct, key
+}
+
+func testAddContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	nct, _ := addContent(assert, repo, "catalog", "dsc")
+	assert.Equal(nct.Name, "catalog", "name should match")
+	assert.Equal(nct.Namespace, "dsc", "namespace should match")
+	assert.Equal(nct.Slug, "catalog-dsc", "slug should match")
+	assert.Equal(nct.CreatedBy, "content@content.org", "should match created_by")
+	assert.Equal(nct.UpdatedBy, "content@content.org", "should match updated_by")
+	assert.False(nct.NotFound, "new content should be found")
+	assert.True(
+		nct.CreatedOn.Equal(nct.UpdatedOn),
+		"created_on should match updated_on",
+	)
+	assert.True(
+		nct.CreatedOn.Before(time.Now()),
+		"should have created before the current time",
+	)
+	ctnt, err := testutils.ContentFromStore(nct.Content)
+	assert.NoError(err, "should not have any error with json unmarshaling")
+	assert.Equal(
+		ctnt,
+		&testutils.ContentJSON{Paragraph: "paragraph", Text: "text"},
+		"should match the content",
+	)
+}
+
+func testDuplicateSlug(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	addContent(assert, repo, "catalog", "dsc")
+	_, err := repo.AddContent(
+		context.Background(),
+		testutils.NewStoreContent("catalog", "dsc"),
+	)
+	assert.Error(err, "expect error for duplicate slug")
+}
+
+func testInvalidEmail(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ncnt := testutils.NewStoreContent("price", "dsc")
+	ncnt.CreatedBy = "yadayadayada"
+	_, err := repo.AddContent(context.Background(), ncnt)
+	assert.Error(err, "expect error for created_by without email address")
+}
+
+func testGetContentBySlug(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	nct, _ := addContent(assert, repo, "catalog", "dsc")
+	sct, err := repo.GetContentBySlug(context.Background(), nct.Slug)
+	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
+	testContentProperties(assert, sct, nct)
+}
+
+func testGetContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	nct, key := addContent(assert, repo, "catalog", "dsc")
+	sct, err := repo.GetContent(context.Background(), key)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	testContentProperties(assert, sct, nct)
+}
+
+func testNotFound(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	sct, err := repo.GetContentBySlug(ctx, "blog")
+	assert.NoErrorf(err, "expect no error for missing slug %s", err)
+	assert.True(sct.NotFound, "expect missing slug to be not found")
+	ict, err := repo.GetContent(ctx, int64(5600000))
+	assert.NoErrorf(err, "expect no error for missing id %s", err)
+	assert.True(ict.NotFound, "expect missing id to be not found")
+}
+
+func testVerbatimContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	for idx, body := range []string{
+		"{\n  \"text\":  \"jack\",\n  \"paragraph\": \"clompous\"\n}",
+		"plain text that is not json",
+	} {
+		cattr := testutils.NewStoreContent(fmt.Sprintf("page%d", idx), "dsc")
+		cattr.Content = body
+		nct, err := repo.AddContent(ctx, cattr)
+		assert.NoErrorf(err, "expect no error from creating content %s", err)
+		assert.Equal(nct.Content, body, "should keep the content as is")
+		sct, err := repo.GetContentBySlug(ctx, cattr.Slug)
+		assert.NoErrorf(err, "expect no error from getting content %s", err)
+		assert.Equal(sct.Content, body, "should read the content as is")
+	}
+}
+
+func testEditContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	nct, key := addContent(assert, repo, "catalog", "dsc")
+	cdata, _ := json.Marshal(&testutils.ContentJSON{
+		Paragraph: "clompous",
+		Text:      "jack",
+	})
+	sct, err := repo.EditContent(
+		context.Background(),
+		key,
+		&content.ExistingContentAttributes{
+			UpdatedBy: "packer@packer.com",
+			Content:   string(cdata),
+		},
+	)
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	assert.Equal(sct.UpdatedBy, "packer@packer.com", "should match updated by")
+	assert.Equal([]byte(sct.Content), cdata, "should match updated content")
+	assert.True(
+		sct.UpdatedOn.After(sct.CreatedOn),
+		"should have correct updated timestamp",
+	)
+	assert.True(sct.CreatedOn.Equal(nct.CreatedOn), "created_on should match")
+	assert.Equal(sct.Name, nct.Name, "name should match")
+	assert.Equal(sct.Namespace, nct.Namespace, "namespace should match")
+	assert.Equal(sct.Slug, nct.Slug, "slug should match")
+	assert.Equal(sct.CreatedBy, nct.CreatedBy, "should match created_by")
+}
+
+func testContentHash(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	nct, key := addContent(assert, repo, "catalog", "dsc")
+	assert.Equal(
+		nct.Hash,
+		model.HashContent(nct.Content),
+		"should store the hash of new content",
+	)
+	sct, err := repo.GetContent(context.Background(), key)
+	assert.NoErrorf(err, "expect no error from fetching content %s", err)
+	assert.Equal(sct.Hash, nct.Hash, "should read back the stored hash")
+	ect, err := repo.EditContent(
+		context.Background(),
+		key,
+		&content.ExistingContentAttributes{
+			UpdatedBy: "packer@packer.com",
+			Content:   `{"paragraph": "edited", "text": "text"}`,
+		},
+	)
+	assert.NoErrorf(err, "expect no error from updating content %s", err)
+	assert.Equal(
+		ect.Hash,
+		model.HashContent(`{"text":"text","paragraph":"edited"}`),
+		"should store the hash of the normalized update",
+	)
+	assert.NotEqual(ect.Hash, nct.Hash, "expect the hash to change")
+}
+
+func testEditMissingContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	_, err := repo.EditContent(
+		context.Background(),
+		int64(5600000),
+		&content.ExistingContentAttributes{
+			UpdatedBy: "packer@packer.com",
+			Content:   "{}",
+		},
+	)
+	assert.Error(err, "expect error from updating missing content")
+}
+
+func testDeleteContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	nct, key := addContent(assert, repo, "catalog", "dsc")
+	assert.NoError(repo.DeleteContent(ctx, key), "expect no error from deleting")
+	ect, err := repo.GetContent(ctx, key)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.True(ect.NotFound, "expect no record to be found")
+	sct, err := repo.GetContentBySlug(ctx, nct.Slug)
+	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
+	assert.True(sct.NotFound, "expect no record to be found by slug")
+	assert.Error(repo.DeleteContent(ctx, key), "expect error from deleting again")
+	addContent(assert, repo, "catalog", "dsc")
+}
+
+func testListContents(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	empty, err := repo.ListContents(ctx, nil)
+	assert.NoErrorf(err, "expect no error from listing contents %s", err)
+	assert.Len(empty, 0, "expect no contents")
+	addContent(assert, repo, "price", "dsc")
+	addContent(assert, repo, "catalog", "dsc")
+	addContent(assert, repo, "about", "dictybase")
+	all, err := repo.ListContents(ctx, nil)
+	assert.NoErrorf(err, "expect no error from listing contents %s", err)
+	assert.Len(all, 3, "expect all contents")
+	assert.Equal(all[0].Slug, "about-dictybase", "should be sorted by namespace")
+	dsc, err := repo.ListContents(ctx, []string{"dsc"})
+	assert.NoErrorf(err, "expect no error from listing contents %s", err)
+	assert.Len(dsc, 2, "expect contents of a single namespace")
+	assert.Equal(dsc[0].Slug, "catalog-dsc", "should be sorted by slug")
+}
+
+func testImportContent(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	icnt := &model.ContentDoc{
+		Name:      "order",
+		Slug:      "order-dsc",
+		Namespace: "dsc",
+		CreatedBy: "content@content.org",
+		UpdatedBy: "content@content.org",
+		Content:   `{"paragraph":"order","text":"text"}`,
+		CreatedOn: time.Date(2019, time.March, 4, 10, 0, 0, 0, time.UTC),
+		UpdatedOn: time.Date(2019, time.March, 5, 10, 0, 0, 0, time.UTC),
+	}
+	icnt.Key = "990000"
+	nct, ok, err := repo.ImportContent(ctx, icnt, false)
+	assert.NoErrorf(err, "expect no error from importing content %s", err)
+	assert.True(ok, "expect content to be written")
+	assert.Equal(nct.Key, "990000", "key should be preserved")
+	assert.True(nct.CreatedOn.Equal(icnt.CreatedOn), "created_on should be preserved")
+	assert.True(nct.UpdatedOn.Equal(icnt.UpdatedOn), "updated_on should be preserved")
+	sct, err := repo.GetContent(ctx, 990000)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.Equal(sct.Slug, "order-dsc", "should find imported content")
+	icnt.UpdatedBy = "packer@packer.com"
+	_, ok, err = repo.ImportContent(ctx, icnt, false)
+	assert.NoErrorf(err, "expect no error from importing content %s", err)
+	assert.False(ok, "expect existing content to be skipped")
+	uct, ok, err := repo.ImportContent(ctx, icnt, true)
+	assert.NoErrorf(err, "expect no error from importing content %s", err)
+	assert.True(ok, "expect existing content to be replaced")
+	assert.Equal(uct.UpdatedBy, "packer@packer.com", "should match updated by")
+	nct, _ = addContent(assert, repo, "catalog", "dsc")
+	assert.NotEqual(nct.Key, "990000", "new content should get a new key")
+}
+
+func testTransferContents(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	for _, name := range []string{"catalog", "order", "price"} {
+		addContent(assert, repo, name, "dsc")
+	}
+	copied, err := repo.TransferContents(ctx, &model.ContentTransfer{
+		From:            "dsc",
+		To:              "dictybase",
+		Slugs:           []string{"catalog-dsc", "order-dsc"},
+		SlugPattern:     "-dsc$",
+		SlugReplacement: "-dictybase",
+		UpdatedBy:       "packer@packer.com",
+	})
+	assert.NoErrorf(err, "expect no error from copying contents %s", err)
+	assert.Len(copied, 2, "expect two copied contents")
+	assert.Equal(copied[0].Slug, "catalog-dictybase", "slug should be rewritten")
+	assert.Equal(copied[0].Namespace, "dictybase", "namespace should match")
+	assert.Equal(copied[0].UpdatedBy, "packer@packer.com", "should match updated_by")
+	ocnt, err := repo.GetContentBySlug(ctx, "catalog-dsc")
+	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
+	assert.False(ocnt.NotFound, "expect original content to be kept")
+	moved, err := repo.TransferContents(ctx, &model.ContentTransfer{
+		From:            "dsc",
+		To:              "staging",
+		SlugPattern:     "-dsc$",
+		SlugReplacement: "-staging",
+		Move:            true,
+		UpdatedBy:       "packer@packer.com",
+	})
+	assert.NoErrorf(err, "expect no error from moving contents %s", err)
+	assert.Len(moved, 3, "expect all contents of namespace to be moved")
+	mcnt, err := repo.GetContentBySlug(ctx, "price-staging")
+	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
+	assert.Equal(mcnt.Namespace, "staging", "namespace should match")
+	ecnt, err := repo.GetContentBySlug(ctx, "price-dsc")
+	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
+	assert.True(ecnt.NotFound, "expect moved content to be absent")
+}
+
+func testTransferRollback(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	addContent(assert, repo, "catalog", "dsc")
+	addContent(assert, repo, "order", "dsc")
+	addContent(assert, repo, "order", "dictybase")
+	_, err := repo.TransferContents(ctx, &model.ContentTransfer{
+		From:            "dsc",
+		To:              "dictybase",
+		SlugPattern:     "-dsc$",
+		SlugReplacement: "-dictybase",
+		Move:            true,
+		UpdatedBy:       "packer@packer.com",
+	})
+	assert.Error(err, "expect error from transferring to existing slug")
+	cnts, err := repo.ListContents(ctx, []string{"dsc"})
+	assert.NoErrorf(err, "expect no error from listing contents %s", err)
+	assert.Len(cnts, 2, "expect transfer to be rolled back")
+	ecnt, err := repo.GetContentBySlug(ctx, "catalog-dictybase")
+	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
+	assert.True(ecnt.NotFound, "expect no partial transfer")
+}
+
+func testBatchContents(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	nct, key := addContent(assert, repo, "catalog", "dsc")
+	results, err := repo.BatchContents(ctx, []*model.BatchOperation{
+		{
+			Action: model.BatchCreate,
+			Create: testutils.NewStoreContent("order", "dsc"),
+		},
+		{
+			Action: model.BatchUpdate,
+			ID:     key,
+			Update: &content.ExistingContentAttributes{
+				UpdatedBy: "packer@packer.com",
+				Content:   nct.Content,
+			},
+		},
+		{Action: model.BatchDelete, ID: int64(5600000)},
+	}, false)
+	assert.NoErrorf(err, "expect no error from best effort batch %s", err)
+	assert.Len(results, 3, "expect result for every operation")
+	assert.NoError(results[0].Err, "expect content to be created")
+	assert.Equal(results[0].Content.Slug, "order-dsc", "slug should match")
+	assert.NoError(results[1].Err, "expect content to be updated")
+	assert.Equal(
+		results[1].Content.UpdatedBy,
+		"packer@packer.com",
+		"should match updated by",
+	)
+	assert.Error(results[2].Err, "expect error from deleting missing content")
+	dres, err := repo.BatchContents(ctx, []*model.BatchOperation{
+		{Action: model.BatchDelete, ID: key},
+	}, true)
+	assert.NoErrorf(err, "expect no error from atomic batch %s", err)
+	assert.Equal(dres[0].Content.Slug, nct.Slug, "should return deleted content")
+	ecnt, err := repo.GetContent(ctx, key)
+	assert.NoErrorf(err, "expect no error from getting content %s", err)
+	assert.True(ecnt.NotFound, "expect content to be deleted")
+}
+
+func testBatchRollback(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	_, err := repo.BatchContents(ctx, []*model.BatchOperation{
+		{
+			Action: model.BatchCreate,
+			Create: testutils.NewStoreContent("order", "dsc"),
+		},
+		{Action: model.BatchDelete, ID: int64(5600000)},
+	}, true)
+	assert.Error(err, "expect error from deleting missing content")
+	ocnt, err := repo.GetContentBySlug(ctx, "order-dsc")
+	assert.NoErrorf(err, "expect no error from getting content by slug %s", err)
+	assert.True(ocnt.NotFound, "expect created content to be rolled back")
+}
+
+func testContentProperties(
+	assert *require.Assertions,
+	sct, nct *model.ContentDoc,
+) {
+	assert.False(sct.NotFound, "expect content to be found")
+	assert.Equal(sct.Key, nct.Key, "key should match")
+	assert.Equal(sct.Name, nct.Name, "name should match")
+	assert.Equal(sct.Namespace, nct.Namespace, "namespace should match")
+	assert.Equal(sct.Slug, nct.Slug, "slug should match")
+	assert.Equal(sct.CreatedBy, nct.CreatedBy, "should match created_by")
+	assert.True(sct.CreatedOn.Equal(nct.CreatedOn), "created_on should match")
+	assert.True(sct.UpdatedOn.Equal(nct.UpdatedOn), "updated_on should match")
+	assert.Equal(sct.Content, nct.Content, "should match raw content")
+}
+
+func testLifecycle(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	ctx := context.Background()
+	assert.NoError(repo.Ping(ctx), "expect no error from ping")
+	addContent(assert, repo, "catalog", "dsc")
+	addContent(assert, repo, "price", "dsc")
+	stats, err := repo.Stats(ctx)
+	assert.NoErrorf(err, "expect no error from stats %s", err)
+	assert.NotEmpty(stats.Backend, "should have the backend name")
+	assert.Equal(stats.Records, int64(2), "should count two contents")
+}
+
+func testCountByNamespace(
+	assert *require.Assertions,
+	repo repository.ContentRepository,
+) {
+	addContent(assert, repo, "catalog", "dsc")
+	addContent(assert, repo, "price", "dsc")
+	addContent(assert, repo, "about", "dictybase")
+	counts, err := repo.CountByNamespace(context.Background())
+	assert.NoErrorf(err, "expect no error from counting contents %s", err)
+	assert.Equal(
+		counts,
+		map[string]int64{"dsc": 2, "dictybase": 1},
+		"should match the counts per namespace",
+	)
+}
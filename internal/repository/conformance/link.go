@@ -0,0 +1,142 @@
+package conformance
+
+import (
+	"context"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+var linkCases = []suiteCase[repository.LinkRepository]{
+	{name: "OutgoingLinks", fn: testOutgoingLinks},
+	{name: "Backlinks", fn: testBacklinks},
+	{name: "ReplaceLinks", fn: testReplaceLinks},
+	{name: "ListLinks", fn: testListLinks},
+	{name: "LinkLifecycle", fn: testLinkLifecycle},
+}
+
+func setLinks(
+	assert *require.Assertions,
+	repo repository.LinkRepository,
+	cid int64,
+	slug, namespace string,
+	targets ...string,
+) {
+	err := repo.SetLinks(
+		context.Background(),
+		&model.LinkDoc{ContentID: cid, Slug: slug, Namespace: namespace},
+		targets,
+	)
+	assert.NoErrorf(err, "expect no error from setting links %s", err)
+}
+
+func linkTargets(lnks []*model.LinkDoc) []string {
+	targets := make([]string, 0, len(lnks))
+	for _, lnk := range lnks {
+		targets = append(targets, lnk.Target)
+	}
+
+	return targets
+}
+
+func linkSources(lnks []*model.LinkDoc) []int64 {
+	cids := make([]int64, 0, len(lnks))
+	for _, lnk := range lnks {
+		cids = append(cids, lnk.ContentID)
+	}
+
+	return cids
+}
+
+func testOutgoingLinks(
+	assert *require.Assertions,
+	repo repository.LinkRepository,
+) {
+	ctx := context.Background()
+	setLinks(assert, repo, 1, "home", "dsc", "order", "about", "order")
+	lnks, err := repo.OutgoingLinks(ctx, 1)
+	assert.NoErrorf(err, "expect no error from outgoing links %s", err)
+	assert.Equal(
+		linkTargets(lnks),
+		[]string{"about", "order"},
+		"should match the targets without repeats",
+	)
+	assert.Equal(lnks[0].Slug, "home", "slug should match")
+	assert.Equal(lnks[0].Namespace, "dsc", "namespace should match")
+	assert.Equal(lnks[0].ContentID, int64(1), "content id should match")
+	lnks, err = repo.OutgoingLinks(ctx, 2)
+	assert.NoErrorf(err, "expect no error from outgoing links %s", err)
+	assert.Empty(lnks, "expect no links of unknown content")
+}
+
+func testBacklinks(
+	assert *require.Assertions,
+	repo repository.LinkRepository,
+) {
+	ctx := context.Background()
+	setLinks(assert, repo, 3, "faq", "dsc", "order")
+	setLinks(assert, repo, 1, "home", "dsc", "order", "about")
+	setLinks(assert, repo, 2, "news", "dictybase", "about")
+	lnks, err := repo.Backlinks(ctx, "order")
+	assert.NoErrorf(err, "expect no error from backlinks %s", err)
+	assert.Equal(linkSources(lnks), []int64{1, 3}, "should match the sources")
+	assert.Equal(lnks[1].Slug, "faq", "slug should match")
+	lnks, err = repo.Backlinks(ctx, "missing")
+	assert.NoErrorf(err, "expect no error from backlinks %s", err)
+	assert.Empty(lnks, "expect no backlinks")
+}
+
+func testReplaceLinks(
+	assert *require.Assertions,
+	repo repository.LinkRepository,
+) {
+	ctx := context.Background()
+	setLinks(assert, repo, 1, "home", "dsc", "order", "about")
+	setLinks(assert, repo, 1, "home", "dsc", "faq", "about")
+	lnks, err := repo.OutgoingLinks(ctx, 1)
+	assert.NoErrorf(err, "expect no error from outgoing links %s", err)
+	assert.Equal(
+		linkTargets(lnks),
+		[]string{"about", "faq"},
+		"should replace the targets",
+	)
+	lnks, err = repo.Backlinks(ctx, "order")
+	assert.NoErrorf(err, "expect no error from backlinks %s", err)
+	assert.Empty(lnks, "expect the replaced link to be gone")
+	setLinks(assert, repo, 1, "home", "dsc")
+	lnks, err = repo.OutgoingLinks(ctx, 1)
+	assert.NoErrorf(err, "expect no error from outgoing links %s", err)
+	assert.Empty(lnks, "expect the links to be cleared")
+}
+
+func testListLinks(
+	assert *require.Assertions,
+	repo repository.LinkRepository,
+) {
+	ctx := context.Background()
+	setLinks(assert, repo, 2, "news", "dictybase", "about")
+	setLinks(assert, repo, 1, "home", "dsc", "order", "about")
+	lnks, err := repo.ListLinks(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from listing links %s", err)
+	assert.Equal(
+		linkTargets(lnks),
+		[]string{"about", "order"},
+		"should match the links of the namespace",
+	)
+	lnks, err = repo.ListLinks(ctx, "")
+	assert.NoErrorf(err, "expect no error from listing links %s", err)
+	assert.Equal(linkSources(lnks), []int64{1, 1, 2}, "should list all")
+}
+
+func testLinkLifecycle(
+	assert *require.Assertions,
+	repo repository.LinkRepository,
+) {
+	ctx := context.Background()
+	assert.NoError(repo.Ping(ctx), "expect no error from ping")
+	setLinks(assert, repo, 1, "home", "dsc", "order", "about")
+	stats, err := repo.Stats(ctx)
+	assert.NoErrorf(err, "expect no error from stats %s", err)
+	assert.Equal(stats.Records, int64(2), "expect two stored links")
+}
@@ -0,0 +1,138 @@
+package conformance
+
+import (
+	"context"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	holder = "curator@content.org"
+	rival  = "editor@content.org"
+	lease  = time.Minute
+)
+
+var lockCases = []suiteCase[repository.LockRepository]{
+	{name: "AcquireLock", fn: testAcquireLock},
+	{name: "LapsedLock", fn: testLapsedLock},
+	{name: "RenewLock", fn: testRenewLock},
+	{name: "ReleaseLock", fn: testReleaseLock},
+	{name: "BreakLock", fn: testBreakLock},
+	{name: "LockLifecycle", fn: testLockLifecycle},
+}
+
+func testAcquireLock(
+	assert *require.Assertions,
+	repo repository.LockRepository,
+) {
+	ctx := context.Background()
+	lck, err := repo.AcquireLock(ctx, 10, holder, lease)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	assert.Equal(lck.ContentID, int64(10), "content id should match")
+	assert.Equal(lck.Holder, holder, "holder should match")
+	assert.True(lck.ExpiresOn.After(time.Now()), "should expire later")
+	_, err = repo.AcquireLock(ctx, 10, rival, lease)
+	assert.ErrorIs(err, repository.ErrLockHeld, "expect lock held error")
+	_, err = repo.AcquireLock(ctx, 11, rival, lease)
+	assert.NoErrorf(err, "expect no error for lock of another content %s", err)
+	time.Sleep(10 * time.Millisecond)
+	rlck, err := repo.AcquireLock(ctx, 10, holder, lease)
+	assert.NoErrorf(err, "expect no error from acquiring again %s", err)
+	assert.True(
+		rlck.AcquiredOn.Equal(lck.AcquiredOn),
+		"should keep the time of acquiring",
+	)
+	assert.True(rlck.ExpiresOn.After(lck.ExpiresOn), "should extend the lease")
+	glck, err := repo.GetLock(ctx, 10)
+	assert.NoErrorf(err, "expect no error from getting lock %s", err)
+	assert.False(glck.NotFound, "expect lock to be found")
+	assert.Equal(glck.Holder, holder, "holder should match")
+}
+
+func testLapsedLock(
+	assert *require.Assertions,
+	repo repository.LockRepository,
+) {
+	ctx := context.Background()
+	_, err := repo.AcquireLock(ctx, 10, holder, 100*time.Millisecond)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	time.Sleep(300 * time.Millisecond)
+	glck, err := repo.GetLock(ctx, 10)
+	assert.NoErrorf(err, "expect no error from getting lock %s", err)
+	assert.True(glck.NotFound, "expect lapsed lock not to be found")
+	_, err = repo.RenewLock(ctx, 10, holder, lease)
+	assert.ErrorIs(err, repository.ErrLockNotHeld, "expect lock not held error")
+	lck, err := repo.AcquireLock(ctx, 10, rival, lease)
+	assert.NoErrorf(err, "expect no error from taking lapsed lock %s", err)
+	assert.Equal(lck.Holder, rival, "should pass on to the new holder")
+}
+
+func testRenewLock(
+	assert *require.Assertions,
+	repo repository.LockRepository,
+) {
+	ctx := context.Background()
+	_, err := repo.RenewLock(ctx, 10, holder, lease)
+	assert.ErrorIs(err, repository.ErrLockNotHeld, "expect lock not held error")
+	lck, err := repo.AcquireLock(ctx, 10, holder, lease)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	time.Sleep(10 * time.Millisecond)
+	rlck, err := repo.RenewLock(ctx, 10, holder, lease)
+	assert.NoErrorf(err, "expect no error from renewing lock %s", err)
+	assert.True(rlck.ExpiresOn.After(lck.ExpiresOn), "should extend the lease")
+	_, err = repo.RenewLock(ctx, 10, rival, lease)
+	assert.ErrorIs(err, repository.ErrLockNotHeld, "expect lock not held error")
+}
+
+func testReleaseLock(
+	assert *require.Assertions,
+	repo repository.LockRepository,
+) {
+	ctx := context.Background()
+	_, err := repo.AcquireLock(ctx, 10, holder, lease)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	err = repo.ReleaseLock(ctx, 10, rival)
+	assert.ErrorIs(err, repository.ErrLockNotHeld, "expect lock not held error")
+	err = repo.ReleaseLock(ctx, 10, holder)
+	assert.NoErrorf(err, "expect no error from releasing lock %s", err)
+	glck, err := repo.GetLock(ctx, 10)
+	assert.NoErrorf(err, "expect no error from getting lock %s", err)
+	assert.True(glck.NotFound, "expect released lock not to be found")
+	err = repo.ReleaseLock(ctx, 10, holder)
+	assert.ErrorIs(err, repository.ErrLockNotHeld, "expect lock not held error")
+}
+
+func testBreakLock(
+	assert *require.Assertions,
+	repo repository.LockRepository,
+) {
+	ctx := context.Background()
+	blck, err := repo.BreakLock(ctx, 10)
+	assert.NoErrorf(err, "expect no error from breaking missing lock %s", err)
+	assert.True(blck.NotFound, "expect missing lock not to be found")
+	_, err = repo.AcquireLock(ctx, 10, holder, lease)
+	assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	blck, err = repo.BreakLock(ctx, 10)
+	assert.NoErrorf(err, "expect no error from breaking lock %s", err)
+	assert.Equal(blck.Holder, holder, "should return the broken lock")
+	lck, err := repo.AcquireLock(ctx, 10, rival, lease)
+	assert.NoErrorf(err, "expect no error from acquiring broken lock %s", err)
+	assert.Equal(lck.Holder, rival, "should pass on to the new holder")
+}
+
+func testLockLifecycle(
+	assert *require.Assertions,
+	repo repository.LockRepository,
+) {
+	ctx := context.Background()
+	assert.NoError(repo.Ping(ctx), "expect no error from ping")
+	for _, cid := range []int64{10, 11} {
+		_, err := repo.AcquireLock(ctx, cid, holder, lease)
+		assert.NoErrorf(err, "expect no error from acquiring lock %s", err)
+	}
+	stats, err := repo.Stats(ctx)
+	assert.NoErrorf(err, "expect no error from stats %s", err)
+	assert.Equal(stats.Records, int64(2), "expect two stored locks")
+}
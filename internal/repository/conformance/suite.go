@@ -0,0 +1,76 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories are the repositories of a backend under test, they could
+// share the same storage.
+type Repositories struct {
+	Content repository.ContentRepository
+	Audit   repository.AuditRepository
+	Locks   repository.LockRepository
+	Assets  repository.AssetRepository
+	Links   repository.LinkRepository
+}
+
+// Factory returns new and empty repositories for every test, their cleanup
+// should be registered with the test.
+type Factory func(t *testing.T) *Repositories
+
+type suiteCase[R any] struct {
+	name string
+	fn   func(*require.Assertions, R)
+}
+
+// RunAll runs every suite against the repositories created by the factory.
+func RunAll(t *testing.T, factory Factory) {
+	t.Helper()
+	runSuite(t, "Content", testCases,
+		func(t *testing.T) repository.ContentRepository {
+			return factory(t).Content
+		},
+	)
+	runSuite(t, "Audit", auditCases,
+		func(t *testing.T) repository.AuditRepository {
+			return factory(t).Audit
+		},
+	)
+	runSuite(t, "Lock", lockCases,
+		func(t *testing.T) repository.LockRepository {
+			return factory(t).Locks
+		},
+	)
+	runSuite(t, "Asset", assetCases,
+		func(t *testing.T) repository.AssetRepository {
+			return factory(t).Assets
+		},
+	)
+	runSuite(t, "Link", linkCases,
+		func(t *testing.T) repository.LinkRepository {
+			return factory(t).Links
+		},
+	)
+}
+
+func runSuite[R any](
+	t *testing.T,
+	name string,
+	cases []suiteCase[R],
+	repo func(*testing.T) R,
+) {
+	t.Helper()
+	t.Run(name, func(t *testing.T) {
+		t.Parallel()
+		for _, tcs := range cases {
+			tcs := tcs
+			t.Run(tcs.name, func(t *testing.T) {
+				t.Parallel()
+				tcs.fn(require.New(t), repo(t))
+			})
+		}
+	})
+}
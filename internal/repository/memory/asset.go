@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type assetrepository struct {
+	mutex    sync.Mutex
+	validate *validator.Validate
+	assets   map[int64]model.AssetDoc
+	// embeds are the ids of the assets embedded by every content
+	embeds  map[int64][]int64
+	lastKey int64
+}
+
+func NewAssetRepo() repository.AssetRepository {
+	return &assetrepository{
+		validate: validator.New(),
+		assets:   make(map[int64]model.AssetDoc),
+		embeds:   make(map[int64][]int64),
+	}
+}
+
+func (arp *assetrepository) AddAsset(
+	_ context.Context,
+	ast *model.AssetDoc,
+) (*model.AssetDoc, error) {
+	if err := arp.validate.Struct(ast); err != nil {
+		return &model.AssetDoc{}, fmt.Errorf(
+			"error in validating asset %s",
+			err,
+		)
+	}
+	arp.mutex.Lock()
+	defer arp.mutex.Unlock()
+	arp.lastKey++
+	doc := *ast
+	doc.Key = strconv.FormatInt(arp.lastKey, 10)
+	doc.CreatedOn = time.Now().UTC()
+	doc.References = make([]int64, 0)
+	arp.assets[arp.lastKey] = doc
+
+	return &doc, nil
+}
+
+func (arp *assetrepository) GetAsset(
+	_ context.Context,
+	aid int64,
+) (*model.AssetDoc, error) {
+	arp.mutex.Lock()
+	defer arp.mutex.Unlock()
+	doc, ok := arp.assets[aid]
+	if !ok {
+		return &model.AssetDoc{NotFound: true}, nil
+	}
+	doc.References = make([]int64, 0)
+	for cid, aids := range arp.embeds {
+		for _, embedded := range aids {
+			if embedded == aid {
+				doc.References = append(doc.References, cid)
+			}
+		}
+	}
+	sort.Slice(doc.References, func(i, j int) bool {
+		return doc.References[i] < doc.References[j]
+	})
+
+	return &doc, nil
+}
+
+func (arp *assetrepository) RemoveAsset(_ context.Context, aid int64) error {
+	arp.mutex.Lock()
+	defer arp.mutex.Unlock()
+	if _, ok := arp.assets[aid]; !ok {
+		return fmt.Errorf("asset %d not found", aid)
+	}
+	delete(arp.assets, aid)
+	for cid, aids := range arp.embeds {
+		arp.embeds[cid] = arp.existing(aids)
+	}
+
+	return nil
+}
+
+func (arp *assetrepository) SetReferences(
+	_ context.Context,
+	cid int64,
+	aids []int64,
+) error {
+	arp.mutex.Lock()
+	defer arp.mutex.Unlock()
+	existing := arp.existing(aids)
+	if len(existing) == 0 {
+		delete(arp.embeds, cid)
+
+		return nil
+	}
+	arp.embeds[cid] = existing
+
+	return nil
+}
+
+// existing filters out the ids of missing assets, the lock should be held.
+func (arp *assetrepository) existing(aids []int64) []int64 {
+	existing := make([]int64, 0, len(aids))
+	for _, aid := range aids {
+		if _, ok := arp.assets[aid]; ok {
+			existing = append(existing, aid)
+		}
+	}
+
+	return existing
+}
+
+// Drop removes all the stored assets.
+func (arp *assetrepository) Drop() error {
+	arp.mutex.Lock()
+	defer arp.mutex.Unlock()
+	arp.assets = make(map[int64]model.AssetDoc)
+	arp.embeds = make(map[int64][]int64)
+
+	return nil
+}
+
+// Ping always succeeds as there is no storage to reach.
+func (arp *assetrepository) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close is a no-op, the stored assets are kept until Drop.
+func (arp *assetrepository) Close() error {
+	return nil
+}
+
+func (arp *assetrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	arp.mutex.Lock()
+	defer arp.mutex.Unlock()
+
+	return &repository.Stats{
+		Backend: "memory",
+		Records: int64(len(arp.assets)),
+	}, nil
+}
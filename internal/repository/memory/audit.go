@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type auditrepository struct {
+	mutex    sync.RWMutex
+	validate *validator.Validate
+	entries  []model.AuditEntry
+}
+
+func NewAuditRepo() repository.AuditRepository {
+	return &auditrepository{
+		validate: validator.New(),
+		entries:  make([]model.AuditEntry, 0),
+	}
+}
+
+func (aud *auditrepository) AddEntry(
+	_ context.Context,
+	ent *model.AuditEntry,
+) (*model.AuditEntry, error) {
+	if err := aud.validate.Struct(ent); err != nil {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in adding audit entry %s",
+			err,
+		)
+	}
+	aud.mutex.Lock()
+	defer aud.mutex.Unlock()
+	doc := *ent
+	doc.Key = strconv.Itoa(len(aud.entries) + 1)
+	doc.Client = copyClient(ent.Client)
+	doc.CreatedOn = time.Now().UTC()
+	aud.entries = append(aud.entries, doc)
+
+	return &doc, nil
+}
+
+func (aud *auditrepository) ListEntries(
+	_ context.Context,
+	flt *model.AuditFilter,
+) ([]*model.AuditEntry, error) {
+	aud.mutex.RLock()
+	defer aud.mutex.RUnlock()
+	ents := make([]*model.AuditEntry, 0)
+	for _, ent := range aud.entries {
+		if !flt.Match(&ent) {
+			continue
+		}
+		doc := ent
+		ents = append(ents, &doc)
+		if flt.Limit > 0 && len(ents) == flt.Limit {
+			break
+		}
+	}
+
+	return ents, nil
+}
+
+// Drop removes all the stored entries.
+func (aud *auditrepository) Drop() error {
+	aud.mutex.Lock()
+	defer aud.mutex.Unlock()
+	aud.entries = make([]model.AuditEntry, 0)
+
+	return nil
+}
+
+// Ping always succeeds as there is no storage to reach.
+func (aud *auditrepository) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close is a no-op, the stored entries are kept until Drop.
+func (aud *auditrepository) Close() error {
+	return nil
+}
+
+func (aud *auditrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	aud.mutex.RLock()
+	defer aud.mutex.RUnlock()
+
+	return &repository.Stats{
+		Backend: "memory",
+		Records: int64(len(aud.entries)),
+	}, nil
+}
+
+func copyClient(client map[string]string) map[string]string {
+	cpy := make(map[string]string, len(client))
+	for key, val := range client {
+		cpy[key] = val
+	}
+
+	return cpy
+}
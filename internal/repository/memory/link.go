@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+type linkrepository struct {
+	mutex sync.Mutex
+	// links are the links of every content ordered by target
+	links map[int64][]*model.LinkDoc
+}
+
+func NewLinkRepo() repository.LinkRepository {
+	return &linkrepository{links: make(map[int64][]*model.LinkDoc)}
+}
+
+func (lrp *linkrepository) SetLinks(
+	_ context.Context,
+	src *model.LinkDoc,
+	targets []string,
+) error {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	if len(targets) == 0 {
+		delete(lrp.links, src.ContentID)
+
+		return nil
+	}
+	lrp.links[src.ContentID] = links.NewLinks(src, targets)
+
+	return nil
+}
+
+func (lrp *linkrepository) OutgoingLinks(
+	_ context.Context,
+	cid int64,
+) ([]*model.LinkDoc, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+
+	return lrp.filter(func(lnk *model.LinkDoc) bool {
+		return lnk.ContentID == cid
+	}), nil
+}
+
+func (lrp *linkrepository) Backlinks(
+	_ context.Context,
+	slug string,
+) ([]*model.LinkDoc, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+
+	return lrp.filter(func(lnk *model.LinkDoc) bool {
+		return lnk.Target == slug
+	}), nil
+}
+
+func (lrp *linkrepository) ListLinks(
+	_ context.Context,
+	namespace string,
+) ([]*model.LinkDoc, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+
+	return lrp.filter(func(lnk *model.LinkDoc) bool {
+		return len(namespace) == 0 || lnk.Namespace == namespace
+	}), nil
+}
+
+// filter returns the matching links ordered by content and target, the
+// lock should be held.
+func (lrp *linkrepository) filter(
+	match func(*model.LinkDoc) bool,
+) []*model.LinkDoc {
+	cids := make([]int64, 0, len(lrp.links))
+	for cid := range lrp.links {
+		cids = append(cids, cid)
+	}
+	sort.Slice(cids, func(i, j int) bool { return cids[i] < cids[j] })
+	lnks := make([]*model.LinkDoc, 0)
+	for _, cid := range cids {
+		for _, lnk := range lrp.links[cid] {
+			if match(lnk) {
+				doc := *lnk
+				lnks = append(lnks, &doc)
+			}
+		}
+	}
+
+	return lnks
+}
+
+// Drop removes all the stored links.
+func (lrp *linkrepository) Drop() error {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	lrp.links = make(map[int64][]*model.LinkDoc)
+
+	return nil
+}
+
+// Ping always succeeds as there is no storage to reach.
+func (lrp *linkrepository) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close is a no-op, the stored links are kept until Drop.
+func (lrp *linkrepository) Close() error {
+	return nil
+}
+
+func (lrp *linkrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	var count int64
+	for _, lnks := range lrp.links {
+		count += int64(len(lnks))
+	}
+
+	return &repository.Stats{Backend: "memory", Records: count}, nil
+}
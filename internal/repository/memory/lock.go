@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+type lockrepository struct {
+	mutex sync.Mutex
+	locks map[int64]model.LockDoc
+}
+
+func NewLockRepo() repository.LockRepository {
+	return &lockrepository{locks: make(map[int64]model.LockDoc)}
+}
+
+func (lrp *lockrepository) AcquireLock(
+	_ context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	now := time.Now().UTC()
+	lck, ok := lrp.locks[cid]
+	switch {
+	case !ok || lck.Lapsed(now):
+		lck = model.LockDoc{
+			ContentID:  cid,
+			Holder:     holder,
+			AcquiredOn: now,
+		}
+		lck.Key = strconv.FormatInt(cid, 10)
+	case lck.Holder != holder:
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in acquiring lock of content %d %w",
+			cid,
+			repository.ErrLockHeld,
+		)
+	}
+	lck.ExpiresOn = now.Add(ttl)
+	lrp.locks[cid] = lck
+
+	return &lck, nil
+}
+
+func (lrp *lockrepository) RenewLock(
+	_ context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	now := time.Now().UTC()
+	lck, ok := lrp.locks[cid]
+	if !ok || lck.Lapsed(now) || lck.Holder != holder {
+		return &model.LockDoc{}, fmt.Errorf(
+			"error in renewing lock of content %d %w",
+			cid,
+			repository.ErrLockNotHeld,
+		)
+	}
+	lck.ExpiresOn = now.Add(ttl)
+	lrp.locks[cid] = lck
+
+	return &lck, nil
+}
+
+func (lrp *lockrepository) ReleaseLock(
+	_ context.Context,
+	cid int64,
+	holder string,
+) error {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	lck, ok := lrp.locks[cid]
+	if !ok || lck.Holder != holder {
+		return fmt.Errorf(
+			"error in releasing lock of content %d %w",
+			cid,
+			repository.ErrLockNotHeld,
+		)
+	}
+	delete(lrp.locks, cid)
+
+	return nil
+}
+
+func (lrp *lockrepository) BreakLock(
+	_ context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	lck, ok := lrp.locks[cid]
+	if !ok {
+		return &model.LockDoc{NotFound: true}, nil
+	}
+	delete(lrp.locks, cid)
+
+	return &lck, nil
+}
+
+func (lrp *lockrepository) GetLock(
+	_ context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	lck, ok := lrp.locks[cid]
+	if !ok || lck.Lapsed(time.Now()) {
+		return &model.LockDoc{NotFound: true}, nil
+	}
+
+	return &lck, nil
+}
+
+// Drop removes all the stored locks.
+func (lrp *lockrepository) Drop() error {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+	lrp.locks = make(map[int64]model.LockDoc)
+
+	return nil
+}
+
+// Ping always succeeds as there is no storage to reach.
+func (lrp *lockrepository) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close is a no-op, the stored locks are kept until Drop.
+func (lrp *lockrepository) Close() error {
+	return nil
+}
+
+func (lrp *lockrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	lrp.mutex.Lock()
+	defer lrp.mutex.Unlock()
+
+	return &repository.Stats{
+		Backend: "memory",
+		Records: int64(len(lrp.locks)),
+	}, nil
+}
@@ -0,0 +1,410 @@
+// Package memory provides in-memory implementations of the repositories
+// for tests and local development. Nothing is persisted, everything is lost
+// once the process exits.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type memoryrepository struct {
+	mutex    sync.RWMutex
+	validate *validator.Validate
+	lastKey  int64
+	contents map[string]model.ContentDoc
+	slugs    map[string]string
+}
+
+func NewContentRepo() repository.ContentRepository {
+	return &memoryrepository{
+		validate: validator.New(),
+		contents: make(map[string]model.ContentDoc),
+		slugs:    make(map[string]string),
+	}
+}
+
+func (mrp *memoryrepository) GetContentBySlug(
+	_ context.Context,
+	slug string,
+) (*model.ContentDoc, error) {
+	mrp.mutex.RLock()
+	defer mrp.mutex.RUnlock()
+	key, ok := mrp.slugs[slug]
+	if !ok {
+		return &model.ContentDoc{NotFound: true}, nil
+	}
+	cnt := mrp.contents[key]
+
+	return &cnt, nil
+}
+
+func (mrp *memoryrepository) GetContent(
+	_ context.Context,
+	cid int64,
+) (*model.ContentDoc, error) {
+	mrp.mutex.RLock()
+	defer mrp.mutex.RUnlock()
+	cnt, ok := mrp.contents[strconv.FormatInt(cid, 10)]
+	if !ok {
+		return &model.ContentDoc{NotFound: true}, nil
+	}
+
+	return &cnt, nil
+}
+
+func (mrp *memoryrepository) AddContent(
+	_ context.Context,
+	cattr *content.NewContentAttributes,
+) (*model.ContentDoc, error) {
+	mrp.mutex.Lock()
+	defer mrp.mutex.Unlock()
+
+	return mrp.add(cattr)
+}
+
+func (mrp *memoryrepository) EditContent(
+	_ context.Context,
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	mrp.mutex.Lock()
+	defer mrp.mutex.Unlock()
+
+	return mrp.edit(cid, cattr)
+}
+
+func (mrp *memoryrepository) DeleteContent(
+	_ context.Context,
+	cid int64,
+) error {
+	mrp.mutex.Lock()
+	defer mrp.mutex.Unlock()
+	_, err := mrp.remove(cid)
+
+	return err
+}
+
+func (mrp *memoryrepository) ListContents(
+	_ context.Context,
+	namespaces []string,
+) ([]*model.ContentDoc, error) {
+	mrp.mutex.RLock()
+	defer mrp.mutex.RUnlock()
+
+	return mrp.list(namespaces, nil), nil
+}
+
+func (mrp *memoryrepository) ImportContent(
+	_ context.Context,
+	cnt *model.ContentDoc,
+	overwrite bool,
+) (*model.ContentDoc, bool, error) {
+	mrp.mutex.Lock()
+	defer mrp.mutex.Unlock()
+	if exist, ok := mrp.contents[cnt.Key]; ok && !overwrite {
+		return &exist, false, nil
+	}
+	doc := *cnt
+	doc.NotFound = false
+	if err := mrp.put(&doc); err != nil {
+		return cnt, false, err
+	}
+	if cid, err := strconv.ParseInt(cnt.Key, 10, 64); err == nil &&
+		cid > mrp.lastKey {
+		mrp.lastKey = cid
+	}
+
+	return &doc, true, nil
+}
+
+func (mrp *memoryrepository) CountByNamespace(
+	_ context.Context,
+) (map[string]int64, error) {
+	mrp.mutex.RLock()
+	defer mrp.mutex.RUnlock()
+	counts := make(map[string]int64)
+	for _, cnt := range mrp.contents {
+		counts[cnt.Namespace]++
+	}
+
+	return counts, nil
+}
+
+func (mrp *memoryrepository) TransferContents(
+	_ context.Context,
+	trn *model.ContentTransfer,
+) ([]*model.ContentDoc, error) {
+	rewrite, err := model.SlugRewriter(trn.SlugPattern, trn.SlugReplacement)
+	if err != nil {
+		return nil, err
+	}
+	mrp.mutex.Lock()
+	defer mrp.mutex.Unlock()
+	snap := mrp.snapshot()
+	cntModels := make([]*model.ContentDoc, 0)
+	for _, cnt := range mrp.list([]string{trn.From}, trn.Slugs) {
+		doc := *cnt
+		doc.Slug = rewrite(cnt.Slug)
+		doc.Namespace = trn.To
+		doc.UpdatedBy = trn.UpdatedBy
+		doc.UpdatedOn = time.Now().UTC()
+		if !trn.Move {
+			doc.Key = mrp.nextKey()
+			doc.CreatedOn = doc.UpdatedOn
+		}
+		if err := mrp.put(&doc); err != nil {
+			mrp.restore(snap)
+
+			return nil, fmt.Errorf(
+				"error in transferring content %s %s",
+				cnt.Slug,
+				err,
+			)
+		}
+		cntModels = append(cntModels, &doc)
+	}
+
+	return cntModels, nil
+}
+
+func (mrp *memoryrepository) BatchContents(
+	_ context.Context,
+	ops []*model.BatchOperation,
+	atomic bool,
+) ([]*model.BatchResult, error) {
+	mrp.mutex.Lock()
+	defer mrp.mutex.Unlock()
+	snap := mrp.snapshot()
+	results := make([]*model.BatchResult, 0, len(ops))
+	for idx, bop := range ops {
+		cnt, err := mrp.runOperation(bop)
+		if err != nil && atomic {
+			mrp.restore(snap)
+
+			return nil, fmt.Errorf("error in batch operation %d %s", idx, err)
+		}
+		results = append(results, &model.BatchResult{
+			Action:  bop.Action,
+			Content: cnt,
+			Err:     err,
+		})
+	}
+
+	return results, nil
+}
+
+// Drop removes all the stored contents.
+func (mrp *memoryrepository) Drop() error {
+	mrp.mutex.Lock()
+	defer mrp.mutex.Unlock()
+	mrp.lastKey = 0
+	mrp.contents = make(map[string]model.ContentDoc)
+	mrp.slugs = make(map[string]string)
+
+	return nil
+}
+
+// Ping always succeeds as there is no storage to reach.
+func (mrp *memoryrepository) Ping(
+	_ context.Context,
+) error {
+	return nil
+}
+
+// Close is a no-op, the stored contents are kept until Drop.
+func (mrp *memoryrepository) Close() error {
+	return nil
+}
+
+func (mrp *memoryrepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	mrp.mutex.RLock()
+	defer mrp.mutex.RUnlock()
+
+	return &repository.Stats{
+		Backend: "memory",
+		Records: int64(len(mrp.contents)),
+	}, nil
+}
+
+func (mrp *memoryrepository) runOperation(
+	bop *model.BatchOperation,
+) (*model.ContentDoc, error) {
+	switch bop.Action {
+	case model.BatchCreate:
+		return mrp.add(bop.Create)
+	case model.BatchUpdate:
+		return mrp.edit(bop.ID, bop.Update)
+	case model.BatchDelete:
+		return mrp.remove(bop.ID)
+	}
+
+	return nil, fmt.Errorf("unknown batch action %s", bop.Action)
+}
+
+func (mrp *memoryrepository) add(
+	cattr *content.NewContentAttributes,
+) (*model.ContentDoc, error) {
+	now := time.Now().UTC()
+	doc := &model.ContentDoc{
+		Name:      cattr.Name,
+		Slug:      cattr.Slug,
+		Namespace: cattr.Namespace,
+		CreatedBy: cattr.CreatedBy,
+		UpdatedBy: cattr.CreatedBy,
+		Content:   cattr.Content,
+		CreatedOn: now,
+		UpdatedOn: now,
+	}
+	doc.Key = mrp.nextKey()
+	if err := mrp.put(doc); err != nil {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in creating new content %s",
+			err,
+		)
+	}
+
+	return doc, nil
+}
+
+func (mrp *memoryrepository) edit(
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	key := strconv.FormatInt(cid, 10)
+	exist, ok := mrp.contents[key]
+	if !ok {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in updating content, document with ID %d not found",
+			cid,
+		)
+	}
+	doc := exist
+	doc.UpdatedBy = cattr.UpdatedBy
+	doc.Content = cattr.Content
+	doc.UpdatedOn = time.Now().UTC()
+	if err := mrp.put(&doc); err != nil {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in updating content %s",
+			err,
+		)
+	}
+
+	return &doc, nil
+}
+
+func (mrp *memoryrepository) remove(cid int64) (*model.ContentDoc, error) {
+	key := strconv.FormatInt(cid, 10)
+	exist, ok := mrp.contents[key]
+	if !ok {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"document with ID %d not found",
+			cid,
+		)
+	}
+	delete(mrp.contents, key)
+	delete(mrp.slugs, exist.Slug)
+
+	return &exist, nil
+}
+
+// put stores the document under its key along with the hash of its content,
+// a content already stored under the key is replaced and its slug is
+// released.
+func (mrp *memoryrepository) put(doc *model.ContentDoc) error {
+	doc.Hash = model.HashContent(doc.Content)
+	if key, ok := mrp.slugs[doc.Slug]; ok && key != doc.Key {
+		return fmt.Errorf("slug %s already exists", doc.Slug)
+	}
+	if err := mrp.validate.Struct(doc); err != nil {
+		return fmt.Errorf("error in validating content %s", err)
+	}
+	if exist, ok := mrp.contents[doc.Key]; ok {
+		delete(mrp.slugs, exist.Slug)
+	}
+	mrp.contents[doc.Key] = *doc
+	mrp.slugs[doc.Slug] = doc.Key
+
+	return nil
+}
+
+func (mrp *memoryrepository) list(
+	namespaces, slugs []string,
+) []*model.ContentDoc {
+	nsps := toSet(namespaces)
+	slgs := toSet(slugs)
+	cntModels := make([]*model.ContentDoc, 0)
+	for _, cnt := range mrp.contents {
+		if len(nsps) > 0 && !nsps[cnt.Namespace] {
+			continue
+		}
+		if len(slgs) > 0 && !slgs[cnt.Slug] {
+			continue
+		}
+		doc := cnt
+		cntModels = append(cntModels, &doc)
+	}
+	sort.Slice(cntModels, func(i, j int) bool {
+		if cntModels[i].Namespace != cntModels[j].Namespace {
+			return cntModels[i].Namespace < cntModels[j].Namespace
+		}
+
+		return cntModels[i].Slug < cntModels[j].Slug
+	})
+
+	return cntModels
+}
+
+func (mrp *memoryrepository) nextKey() string {
+	mrp.lastKey++
+
+	return strconv.FormatInt(mrp.lastKey, 10)
+}
+
+type snapshot struct {
+	lastKey  int64
+	contents map[string]model.ContentDoc
+	slugs    map[string]string
+}
+
+func (mrp *memoryrepository) snapshot() *snapshot {
+	snap := &snapshot{
+		lastKey:  mrp.lastKey,
+		contents: make(map[string]model.ContentDoc, len(mrp.contents)),
+		slugs:    make(map[string]string, len(mrp.slugs)),
+	}
+	for key, cnt := range mrp.contents {
+		snap.contents[key] = cnt
+	}
+	for slug, key := range mrp.slugs {
+		snap.slugs[slug] = key
+	}
+
+	return snap
+}
+
+func (mrp *memoryrepository) restore(snap *snapshot) {
+	mrp.lastKey = snap.lastKey
+	mrp.contents = snap.contents
+	mrp.slugs = snap.slugs
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, val := range values {
+		set[val] = true
+	}
+
+	return set
+}
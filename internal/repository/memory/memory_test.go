@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dictyBase/modware-content/internal/repository/conformance"
+	"github.com/dictyBase/modware-content/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	conformance.RunAll(t, func(t *testing.T) *conformance.Repositories {
+		t.Helper()
+
+		return &conformance.Repositories{
+			Content: NewContentRepo(),
+			Audit:   NewAuditRepo(),
+			Locks:   NewLockRepo(),
+			Assets:  NewAssetRepo(),
+			Links:   NewLinkRepo(),
+		}
+	})
+}
+
+func TestConcurrentAddContent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := NewContentRepo()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			_, err := repo.AddContent(
+				ctx,
+				testutils.NewStoreContent(fmt.Sprintf("page%d", idx), "dsc"),
+			)
+			assert.NoError(err, "expect no error from concurrent creation")
+		}(i)
+	}
+	wg.Wait()
+	cnts, err := repo.ListContents(ctx, []string{"dsc"})
+	assert.NoErrorf(err, "expect no error from listing contents %s", err)
+	assert.Len(cnts, 50, "expect every content to be stored")
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type namespacerepository struct {
+	mutex      sync.RWMutex
+	validate   *validator.Validate
+	lastKey    int64
+	namespaces map[string]model.NamespaceDoc
+}
+
+func NewNamespaceRepo() repository.NamespaceRepository {
+	return &namespacerepository{
+		validate:   validator.New(),
+		namespaces: make(map[string]model.NamespaceDoc),
+	}
+}
+
+func (nrp *namespacerepository) AddNamespace(
+	_ context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	nrp.mutex.Lock()
+	defer nrp.mutex.Unlock()
+	if _, ok := nrp.namespaces[nsp.Name]; ok {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in creating new namespace, %s already exists",
+			nsp.Name,
+		)
+	}
+	if err := nrp.validate.Struct(nsp); err != nil {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in creating new namespace %s",
+			err,
+		)
+	}
+	now := time.Now().UTC()
+	nrp.lastKey++
+	doc := model.NamespaceDoc{
+		Name:          nsp.Name,
+		DisplayName:   nsp.DisplayName,
+		EditorsGroup:  nsp.EditorsGroup,
+		ContentSchema: nsp.ContentSchema,
+		CreatedBy:     nsp.CreatedBy,
+		CreatedOn:     now,
+		UpdatedOn:     now,
+	}
+	doc.Key = strconv.FormatInt(nrp.lastKey, 10)
+	nrp.namespaces[doc.Name] = doc
+
+	return &doc, nil
+}
+
+func (nrp *namespacerepository) GetNamespace(
+	_ context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nrp.mutex.RLock()
+	defer nrp.mutex.RUnlock()
+	nsp, ok := nrp.namespaces[name]
+	if !ok {
+		return &model.NamespaceDoc{NotFound: true}, nil
+	}
+
+	return &nsp, nil
+}
+
+func (nrp *namespacerepository) ListNamespaces(
+	_ context.Context,
+) ([]*model.NamespaceDoc, error) {
+	nrp.mutex.RLock()
+	defer nrp.mutex.RUnlock()
+	nsps := make([]*model.NamespaceDoc, 0, len(nrp.namespaces))
+	for _, nsp := range nrp.namespaces {
+		doc := nsp
+		nsps = append(nsps, &doc)
+	}
+	sort.Slice(nsps, func(i, j int) bool {
+		return nsps[i].Name < nsps[j].Name
+	})
+
+	return nsps, nil
+}
+
+func (nrp *namespacerepository) ArchiveNamespace(
+	_ context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nrp.mutex.Lock()
+	defer nrp.mutex.Unlock()
+	nsp, ok := nrp.namespaces[name]
+	if !ok {
+		return &model.NamespaceDoc{NotFound: true}, nil
+	}
+	nsp.Archived = true
+	nsp.UpdatedOn = time.Now().UTC()
+	nrp.namespaces[name] = nsp
+
+	return &nsp, nil
+}
+
+// Drop removes all the stored namespaces.
+func (nrp *namespacerepository) Drop() error {
+	nrp.mutex.Lock()
+	defer nrp.mutex.Unlock()
+	nrp.lastKey = 0
+	nrp.namespaces = make(map[string]model.NamespaceDoc)
+
+	return nil
+}
+
+// Ping always succeeds as there is no storage to reach.
+func (nrp *namespacerepository) Ping(
+	_ context.Context,
+) error {
+	return nil
+}
+
+// Close is a no-op, the stored namespaces are kept until Drop.
+func (nrp *namespacerepository) Close() error {
+	return nil
+}
+
+func (nrp *namespacerepository) Stats(
+	_ context.Context,
+) (*repository.Stats, error) {
+	nrp.mutex.RLock()
+	defer nrp.mutex.RUnlock()
+
+	return &repository.Stats{
+		Backend: "memory",
+		Records: int64(len(nrp.namespaces)),
+	}, nil
+}
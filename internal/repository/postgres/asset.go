@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+type assetrepository struct {
+	dbh      *sql.DB
+	schema   string
+	validate *validator.Validate
+}
+
+// NewAssetRepo creates the asset repository, the references of an asset are
+// removed along with it.
+func NewAssetRepo(pdb *Database) repository.AssetRepository {
+	return &assetrepository{
+		dbh:      pdb.dbh,
+		schema:   pdb.schema,
+		validate: validator.New(),
+	}
+}
+
+func (arp *assetrepository) AddAsset(
+	ctx context.Context,
+	ast *model.AssetDoc,
+) (*model.AssetDoc, error) {
+	if err := arp.validate.Struct(ast); err != nil {
+		return &model.AssetDoc{}, fmt.Errorf(
+			"error in validating asset %s",
+			err,
+		)
+	}
+	doc, err := scanAsset(arp.dbh.QueryRowContext(
+		ctx, AssetInsert, ast.Name, ast.MimeType, ast.Size,
+		ast.Checksum, ast.BlobKey, ast.CreatedBy,
+	))
+	if err != nil {
+		return &model.AssetDoc{}, fmt.Errorf("error in adding asset %s", err)
+	}
+
+	return doc, nil
+}
+
+func (arp *assetrepository) GetAsset(
+	ctx context.Context,
+	aid int64,
+) (*model.AssetDoc, error) {
+	var refs []int64
+	doc, err := scanAsset(
+		arp.dbh.QueryRowContext(ctx, AssetFind, aid),
+		pq.Array(&refs),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.AssetDoc{NotFound: true}, nil
+		}
+
+		return doc, fmt.Errorf("error in finding asset %d %s", aid, err)
+	}
+	doc.References = append(doc.References, refs...)
+
+	return doc, nil
+}
+
+func (arp *assetrepository) RemoveAsset(ctx context.Context, aid int64) error {
+	res, err := arp.dbh.ExecContext(ctx, AssetRemove, aid)
+	if err != nil {
+		return fmt.Errorf("error in removing asset %d %s", aid, err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error in removing asset %d %s", aid, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("error in removing asset %d, not found", aid)
+	}
+
+	return nil
+}
+
+func (arp *assetrepository) SetReferences(
+	ctx context.Context,
+	cid int64,
+	aids []int64,
+) error {
+	_, err := arp.dbh.ExecContext(ctx, AssetReference, cid, pq.Array(aids))
+	if err != nil {
+		return fmt.Errorf(
+			"error in setting asset references of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Drop removes the schema along with all of its tables.
+func (arp *assetrepository) Drop() error {
+	return dropSchema(arp.dbh, arp.schema)
+}
+
+// Ping checks that the database is reachable.
+func (arp *assetrepository) Ping(ctx context.Context) error {
+	if err := arp.dbh.PingContext(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close closes the pool of database connections.
+func (arp *assetrepository) Close() error {
+	if err := arp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of rows in the asset table.
+func (arp *assetrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	return countRows(ctx, arp.dbh, AssetCount)
+}
+
+// scanAsset reads the asset columns followed by any extra ones.
+func scanAsset(row scanner, extra ...interface{}) (*model.AssetDoc, error) {
+	var aid int64
+	ast := &model.AssetDoc{}
+	dest := []interface{}{
+		&aid, &ast.Name, &ast.MimeType, &ast.Size, &ast.Checksum,
+		&ast.BlobKey, &ast.CreatedBy, &ast.CreatedOn,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return &model.AssetDoc{}, err
+	}
+	ast.Key = strconv.FormatInt(aid, 10)
+	ast.CreatedOn = ast.CreatedOn.UTC()
+	ast.References = make([]int64, 0)
+
+	return ast, nil
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+type auditrepository struct {
+	dbh      *sql.DB
+	schema   string
+	validate *validator.Validate
+}
+
+// NewAuditRepo creates the audit repository, the audit table is guarded by
+// rules that turn any update or delete into a no-op.
+func NewAuditRepo(pdb *Database) repository.AuditRepository {
+	return &auditrepository{
+		dbh:      pdb.dbh,
+		schema:   pdb.schema,
+		validate: validator.New(),
+	}
+}
+
+func (aud *auditrepository) AddEntry(
+	ctx context.Context,
+	ent *model.AuditEntry,
+) (*model.AuditEntry, error) {
+	if err := aud.validate.Struct(ent); err != nil {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in adding audit entry %s",
+			err,
+		)
+	}
+	client, err := json.Marshal(ent.Client)
+	if err != nil {
+		return &model.AuditEntry{}, fmt.Errorf(
+			"error in encoding client metadata %s",
+			err,
+		)
+	}
+	entModel, err := scanEntry(aud.dbh.QueryRowContext(
+		ctx, AuditInsert,
+		ent.Actor, ent.Operation, ent.ContentID, ent.Slug, ent.Namespace,
+		ent.FromSlug, ent.FromNamespace, ent.BeforeHash, ent.AfterHash,
+		string(client),
+	))
+	if err != nil {
+		return entModel, fmt.Errorf("error in adding audit entry %s", err)
+	}
+
+	return entModel, nil
+}
+
+func (aud *auditrepository) ListEntries(
+	ctx context.Context,
+	flt *model.AuditFilter,
+) ([]*model.AuditEntry, error) {
+	entModels := make([]*model.AuditEntry, 0)
+	rows, err := aud.dbh.QueryContext(
+		ctx, AuditList,
+		flt.Actor, flt.Namespace,
+		sql.NullTime{Time: flt.From, Valid: !flt.From.IsZero()},
+		sql.NullTime{Time: flt.To, Valid: !flt.To.IsZero()},
+		flt.Limit,
+	)
+	if err != nil {
+		return entModels, fmt.Errorf("error in listing audit entries %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		entModel, err := scanEntry(rows)
+		if err != nil {
+			return entModels, fmt.Errorf("error in reading row %s", err)
+		}
+		entModels = append(entModels, entModel)
+	}
+	if err := rows.Err(); err != nil {
+		return entModels, fmt.Errorf("error in iterating rows %s", err)
+	}
+
+	return entModels, nil
+}
+
+// Drop removes the schema along with all of its tables.
+func (aud *auditrepository) Drop() error {
+	return dropSchema(aud.dbh, aud.schema)
+}
+
+// Ping checks that the database is reachable.
+func (aud *auditrepository) Ping(ctx context.Context) error {
+	if err := aud.dbh.PingContext(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close closes the pool of database connections.
+func (aud *auditrepository) Close() error {
+	if err := aud.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of rows in the audit table.
+func (aud *auditrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	return countRows(ctx, aud.dbh, AuditCount)
+}
+
+func scanEntry(row scanner) (*model.AuditEntry, error) {
+	entModel := &model.AuditEntry{}
+	var (
+		aid    int64
+		client string
+	)
+	err := row.Scan(
+		&aid, &entModel.Actor, &entModel.Operation, &entModel.ContentID,
+		&entModel.Slug, &entModel.Namespace, &entModel.FromSlug,
+		&entModel.FromNamespace, &entModel.BeforeHash, &entModel.AfterHash,
+		&client, &entModel.CreatedOn,
+	)
+	if err != nil {
+		return entModel, err
+	}
+	if err := json.Unmarshal([]byte(client), &entModel.Client); err != nil {
+		return entModel, fmt.Errorf("error in decoding client metadata %s", err)
+	}
+	entModel.Key = strconv.FormatInt(aid, 10)
+
+	return entModel, nil
+}
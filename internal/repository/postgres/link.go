@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dictyBase/modware-content/internal/links"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/lib/pq"
+)
+
+type linkrepository struct {
+	dbh    *sql.DB
+	schema string
+}
+
+// NewLinkRepo creates the link repository, the targets are kept as slugs
+// without a reference to the content table.
+func NewLinkRepo(pdb *Database) repository.LinkRepository {
+	return &linkrepository{dbh: pdb.dbh, schema: pdb.schema}
+}
+
+func (lrp *linkrepository) SetLinks(
+	ctx context.Context,
+	src *model.LinkDoc,
+	targets []string,
+) error {
+	lnks := links.NewLinks(src, targets)
+	unique := make([]string, 0, len(lnks))
+	for _, lnk := range lnks {
+		unique = append(unique, lnk.Target)
+	}
+	_, err := lrp.dbh.ExecContext(
+		ctx, LinkReplace,
+		src.ContentID, src.Slug, src.Namespace, pq.Array(unique),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error in setting links of content %d %s",
+			src.ContentID,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (lrp *linkrepository) OutgoingLinks(
+	ctx context.Context,
+	cid int64,
+) ([]*model.LinkDoc, error) {
+	return lrp.queryLinks(ctx, LinkOutgoing, cid)
+}
+
+func (lrp *linkrepository) Backlinks(
+	ctx context.Context,
+	slug string,
+) ([]*model.LinkDoc, error) {
+	return lrp.queryLinks(ctx, LinkBacklinks, slug)
+}
+
+func (lrp *linkrepository) ListLinks(
+	ctx context.Context,
+	namespace string,
+) ([]*model.LinkDoc, error) {
+	return lrp.queryLinks(ctx, LinkList, namespace)
+}
+
+func (lrp *linkrepository) queryLinks(
+	ctx context.Context,
+	query string,
+	arg interface{},
+) ([]*model.LinkDoc, error) {
+	lnks := make([]*model.LinkDoc, 0)
+	rows, err := lrp.dbh.QueryContext(ctx, query, arg)
+	if err != nil {
+		return lnks, fmt.Errorf("error in listing links %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		lnk := &model.LinkDoc{}
+		err := rows.Scan(&lnk.ContentID, &lnk.Slug, &lnk.Namespace, &lnk.Target)
+		if err != nil {
+			return lnks, fmt.Errorf("error in reading row %s", err)
+		}
+		lnks = append(lnks, lnk)
+	}
+	if err := rows.Err(); err != nil {
+		return lnks, fmt.Errorf("error in iterating rows %s", err)
+	}
+
+	return lnks, nil
+}
+
+// Drop removes the schema along with all of its tables.
+func (lrp *linkrepository) Drop() error {
+	return dropSchema(lrp.dbh, lrp.schema)
+}
+
+// Ping checks that the database is reachable.
+func (lrp *linkrepository) Ping(ctx context.Context) error {
+	if err := lrp.dbh.PingContext(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close closes the pool of database connections.
+func (lrp *linkrepository) Close() error {
+	if err := lrp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of rows in the link table.
+func (lrp *linkrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	return countRows(ctx, lrp.dbh, LinkCount)
+}
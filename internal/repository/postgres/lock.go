@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+)
+
+type lockrepository struct {
+	dbh    *sql.DB
+	schema string
+}
+
+// NewLockRepo creates the lock repository, a lapsed lease stays in the table
+// until it is taken over or released.
+func NewLockRepo(pdb *Database) repository.LockRepository {
+	return &lockrepository{dbh: pdb.dbh, schema: pdb.schema}
+}
+
+func (lrp *lockrepository) AcquireLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lck, err := scanLock(lrp.dbh.QueryRowContext(
+		ctx, LockAcquire, cid, holder, ttl.Milliseconds(),
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return lck, fmt.Errorf(
+				"error in acquiring lock of content %d %w",
+				cid,
+				repository.ErrLockHeld,
+			)
+		}
+
+		return lck, fmt.Errorf(
+			"error in acquiring lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return lck, nil
+}
+
+func (lrp *lockrepository) RenewLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+	ttl time.Duration,
+) (*model.LockDoc, error) {
+	lck, err := scanLock(lrp.dbh.QueryRowContext(
+		ctx, LockRenew, cid, holder, ttl.Milliseconds(),
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return lck, fmt.Errorf(
+				"error in renewing lock of content %d %w",
+				cid,
+				repository.ErrLockNotHeld,
+			)
+		}
+
+		return lck, fmt.Errorf(
+			"error in renewing lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return lck, nil
+}
+
+func (lrp *lockrepository) ReleaseLock(
+	ctx context.Context,
+	cid int64,
+	holder string,
+) error {
+	res, err := lrp.dbh.ExecContext(ctx, LockRelease, cid, holder)
+	if err != nil {
+		return fmt.Errorf("error in releasing lock of content %d %s", cid, err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error in releasing lock of content %d %s", cid, err)
+	}
+	if count == 0 {
+		return fmt.Errorf(
+			"error in releasing lock of content %d %w",
+			cid,
+			repository.ErrLockNotHeld,
+		)
+	}
+
+	return nil
+}
+
+func (lrp *lockrepository) BreakLock(
+	ctx context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	return lrp.findLock(ctx, LockBreak, cid)
+}
+
+func (lrp *lockrepository) GetLock(
+	ctx context.Context,
+	cid int64,
+) (*model.LockDoc, error) {
+	return lrp.findLock(ctx, LockFind, cid)
+}
+
+func (lrp *lockrepository) findLock(
+	ctx context.Context,
+	query string,
+	cid int64,
+) (*model.LockDoc, error) {
+	lck, err := scanLock(lrp.dbh.QueryRowContext(ctx, query, cid))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.LockDoc{NotFound: true}, nil
+		}
+
+		return lck, fmt.Errorf(
+			"error in finding lock of content %d %s",
+			cid,
+			err,
+		)
+	}
+
+	return lck, nil
+}
+
+// Drop removes the schema along with all of its tables.
+func (lrp *lockrepository) Drop() error {
+	return dropSchema(lrp.dbh, lrp.schema)
+}
+
+// Ping checks that the database is reachable.
+func (lrp *lockrepository) Ping(ctx context.Context) error {
+	if err := lrp.dbh.PingContext(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close closes the pool of database connections.
+func (lrp *lockrepository) Close() error {
+	if err := lrp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of rows in the lock table.
+func (lrp *lockrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	return countRows(ctx, lrp.dbh, LockCount)
+}
+
+func scanLock(row scanner) (*model.LockDoc, error) {
+	lck := &model.LockDoc{}
+	err := row.Scan(&lck.ContentID, &lck.Holder, &lck.AcquiredOn, &lck.ExpiresOn)
+	if err != nil {
+		return &model.LockDoc{}, err
+	}
+	lck.Key = strconv.FormatInt(lck.ContentID, 10)
+
+	return lck, nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// advisory lock key that serializes concurrent migration runners.
+const migrationLock = 4817
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+var schemaReg = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// ConnectParams are the attributes for connecting to a postgres database.
+// All tables are created in Schema.
+type ConnectParams struct {
+	User     string
+	Pass     string
+	Database string
+	Host     string
+	Port     int
+	SSLMode  string
+	Schema   string
+}
+
+// DSN is the connection string of the parameters with every value quoted,
+// the search path is left out when there is no schema.
+func (cnp *ConnectParams) DSN() string {
+	sslMode := cnp.SSLMode
+	if len(sslMode) == 0 {
+		sslMode = "disable"
+	}
+	dsn := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		quote(cnp.Host), cnp.Port, quote(cnp.User), quote(cnp.Pass),
+		quote(cnp.Database), quote(sslMode),
+	)
+	if len(cnp.Schema) == 0 {
+		return dsn
+	}
+
+	return fmt.Sprintf("%s search_path=%s", dsn, cnp.Schema)
+}
+
+func quote(val string) string {
+	return fmt.Sprintf(
+		"'%s'",
+		strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(val),
+	)
+}
+
+// Database is an opened postgres database with an up to date schema, all the
+// repositories of the schema share its pool of connections.
+type Database struct {
+	dbh    *sql.DB
+	schema string
+}
+
+// Open connects to the database and runs the pending migrations once.
+func Open(cnp *ConnectParams) (*Database, error) {
+	if !schemaReg.MatchString(cnp.Schema) {
+		return nil, fmt.Errorf("invalid schema name %s", cnp.Schema)
+	}
+	dbh, err := sql.Open("postgres", cnp.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("error in opening database %s", err)
+	}
+	if err := Migrate(context.Background(), dbh, cnp.Schema); err != nil {
+		dbh.Close()
+
+		return nil, err
+	}
+
+	return &Database{dbh: dbh, schema: cnp.Schema}, nil
+}
+
+// Close closes the shared pool of connections.
+func (pdb *Database) Close() error {
+	if err := pdb.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Migrate applies the embedded migrations that are not yet recorded in the
+// schema_migrations table. Every migration runs in its own transaction.
+func Migrate(ctx context.Context, dbh *sql.DB, schema string) error {
+	_, err := dbh.ExecContext(
+		ctx,
+		fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", pq.QuoteIdentifier(schema)),
+	)
+	if err != nil {
+		return fmt.Errorf("error in creating schema %s", err)
+	}
+	_, err = dbh.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_on TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("error in creating migrations table %s", err)
+	}
+	files, err := migrationFS.ReadDir("migrations")
+	if err != nil {
+		return fmt.Errorf("error in reading migrations %s", err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	for _, file := range files {
+		if err := applyMigration(ctx, dbh, file.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, dbh *sql.DB, name string) error {
+	version := strings.TrimSuffix(name, ".sql")
+	stmt, err := migrationFS.ReadFile(path.Join("migrations", name))
+	if err != nil {
+		return fmt.Errorf("error in reading migration %s %s", name, err)
+	}
+	txn, err := dbh.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error in starting transaction %s", err)
+	}
+	//nolint:errcheck
+	defer txn.Rollback()
+	if _, err := txn.ExecContext(
+		ctx, "SELECT pg_advisory_xact_lock($1)", migrationLock,
+	); err != nil {
+		return fmt.Errorf("error in acquiring migration lock %s", err)
+	}
+	var applied bool
+	err = txn.QueryRowContext(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)",
+		version,
+	).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("error in checking migration %s %s", version, err)
+	}
+	if applied {
+		return nil
+	}
+	if _, err := txn.ExecContext(ctx, string(stmt)); err != nil {
+		return fmt.Errorf("error in applying migration %s %s", version, err)
+	}
+	if _, err := txn.ExecContext(
+		ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version,
+	); err != nil {
+		return fmt.Errorf("error in recording migration %s %s", version, err)
+	}
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("error in committing migration %s %s", version, err)
+	}
+
+	return nil
+}
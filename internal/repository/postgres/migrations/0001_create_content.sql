@@ -0,0 +1,23 @@
+CREATE TABLE content (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    updated_by TEXT NOT NULL,
+    body TEXT NOT NULL,
+    content JSONB,
+    hash TEXT NOT NULL DEFAULT '',
+    created_on TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN content.body IS 'the content as it was sent';
+
+COMMENT ON COLUMN content.content IS 'the body as json, null when it is not json';
+
+CREATE UNIQUE INDEX content_slug_idx ON content (slug);
+
+CREATE INDEX content_namespace_idx ON content (namespace);
+
+CREATE INDEX content_hash_idx ON content (hash);
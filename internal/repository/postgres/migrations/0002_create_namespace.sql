@@ -0,0 +1,13 @@
+CREATE TABLE namespace (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    display_name TEXT NOT NULL,
+    editors_group TEXT NOT NULL DEFAULT '',
+    content_schema TEXT NOT NULL DEFAULT '',
+    archived BOOLEAN NOT NULL DEFAULT false,
+    created_by TEXT NOT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX namespace_name_idx ON namespace (name);
@@ -0,0 +1,26 @@
+CREATE TABLE audit (
+    id BIGSERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    content_id BIGINT NOT NULL,
+    slug TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    from_slug TEXT NOT NULL DEFAULT '',
+    from_namespace TEXT NOT NULL DEFAULT '',
+    before_hash TEXT NOT NULL DEFAULT '',
+    after_hash TEXT NOT NULL DEFAULT '',
+    client JSONB NOT NULL DEFAULT '{}',
+    created_on TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_actor_idx ON audit (actor, created_on);
+
+CREATE INDEX audit_namespace_idx ON audit (namespace, created_on);
+
+CREATE INDEX audit_from_namespace_idx ON audit (from_namespace, created_on);
+
+CREATE INDEX audit_created_on_idx ON audit (created_on);
+
+CREATE RULE audit_no_update AS ON UPDATE TO audit DO INSTEAD NOTHING;
+
+CREATE RULE audit_no_delete AS ON DELETE TO audit DO INSTEAD NOTHING;
@@ -0,0 +1,6 @@
+CREATE TABLE content_lock (
+    content_id BIGINT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    acquired_on TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_on TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,18 @@
+CREATE TABLE asset (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    mime_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    checksum TEXT NOT NULL,
+    blob_key TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE asset_reference (
+    asset_id BIGINT NOT NULL REFERENCES asset (id) ON DELETE CASCADE,
+    content_id BIGINT NOT NULL,
+    PRIMARY KEY (asset_id, content_id)
+);
+
+CREATE INDEX asset_reference_content_idx ON asset_reference (content_id);
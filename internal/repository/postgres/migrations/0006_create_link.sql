@@ -0,0 +1,11 @@
+CREATE TABLE content_link (
+    content_id BIGINT NOT NULL,
+    slug TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    target TEXT NOT NULL,
+    PRIMARY KEY (content_id, target)
+);
+
+CREATE INDEX content_link_target_idx ON content_link (target);
+
+CREATE INDEX content_link_namespace_idx ON content_link (namespace);
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+type namespacerepository struct {
+	dbh      *sql.DB
+	schema   string
+	validate *validator.Validate
+}
+
+func NewNamespaceRepo(pdb *Database) repository.NamespaceRepository {
+	return &namespacerepository{
+		dbh:      pdb.dbh,
+		schema:   pdb.schema,
+		validate: validator.New(),
+	}
+}
+
+func (nrp *namespacerepository) AddNamespace(
+	ctx context.Context,
+	nsp *model.NamespaceDoc,
+) (*model.NamespaceDoc, error) {
+	if err := nrp.validate.Struct(nsp); err != nil {
+		return &model.NamespaceDoc{}, fmt.Errorf(
+			"error in creating new namespace %s",
+			err,
+		)
+	}
+	nspModel, err := scanNamespace(nrp.dbh.QueryRowContext(
+		ctx, NamespaceInsert,
+		nsp.Name, nsp.DisplayName, nsp.EditorsGroup,
+		nsp.ContentSchema, nsp.CreatedBy,
+	))
+	if err != nil {
+		return nspModel, fmt.Errorf("error in creating new namespace %s", err)
+	}
+
+	return nspModel, nil
+}
+
+func (nrp *namespacerepository) GetNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nspModel, err := scanNamespace(
+		nrp.dbh.QueryRowContext(ctx, NamespaceFind, name),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.NamespaceDoc{NotFound: true}, nil
+		}
+
+		return nspModel, fmt.Errorf("error in getting namespace %s", err)
+	}
+
+	return nspModel, nil
+}
+
+func (nrp *namespacerepository) ListNamespaces(
+	ctx context.Context,
+) ([]*model.NamespaceDoc, error) {
+	nspModels := make([]*model.NamespaceDoc, 0)
+	rows, err := nrp.dbh.QueryContext(ctx, NamespaceList)
+	if err != nil {
+		return nspModels, fmt.Errorf("error in listing namespaces %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		nspModel, err := scanNamespace(rows)
+		if err != nil {
+			return nspModels, fmt.Errorf("error in reading row %s", err)
+		}
+		nspModels = append(nspModels, nspModel)
+	}
+	if err := rows.Err(); err != nil {
+		return nspModels, fmt.Errorf("error in iterating rows %s", err)
+	}
+
+	return nspModels, nil
+}
+
+func (nrp *namespacerepository) ArchiveNamespace(
+	ctx context.Context,
+	name string,
+) (*model.NamespaceDoc, error) {
+	nspModel, err := scanNamespace(
+		nrp.dbh.QueryRowContext(ctx, NamespaceArchive, name),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.NamespaceDoc{NotFound: true}, nil
+		}
+
+		return nspModel, fmt.Errorf("error in archiving namespace %s", err)
+	}
+
+	return nspModel, nil
+}
+
+// Drop removes the schema along with all of its tables.
+func (nrp *namespacerepository) Drop() error {
+	return dropSchema(nrp.dbh, nrp.schema)
+}
+
+// Ping checks that the database is reachable.
+func (nrp *namespacerepository) Ping(
+	ctx context.Context,
+) error {
+	if err := nrp.dbh.PingContext(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close closes the pool of database connections.
+func (nrp *namespacerepository) Close() error {
+	if err := nrp.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of rows in the namespace table.
+func (nrp *namespacerepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	return countRows(ctx, nrp.dbh, NamespaceCount)
+}
+
+func scanNamespace(row scanner) (*model.NamespaceDoc, error) {
+	nspModel := &model.NamespaceDoc{}
+	var nid int64
+	err := row.Scan(
+		&nid, &nspModel.Name, &nspModel.DisplayName, &nspModel.EditorsGroup,
+		&nspModel.ContentSchema, &nspModel.Archived, &nspModel.CreatedBy,
+		&nspModel.CreatedOn, &nspModel.UpdatedOn,
+	)
+	if err != nil {
+		return nspModel, err
+	}
+	nspModel.Key = strconv.FormatInt(nid, 10)
+
+	return nspModel, nil
+}
+
+func dropSchema(dbh *sql.DB, schema string) error {
+	_, err := dbh.ExecContext(
+		context.Background(),
+		fmt.Sprintf(
+			"DROP SCHEMA IF EXISTS %s CASCADE",
+			pq.QuoteIdentifier(schema),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("error in dropping schema %s", err)
+	}
+
+	return nil
+}
+
+func countRows(
+	ctx context.Context,
+	dbh *sql.DB,
+	query string,
+) (*repository.Stats, error) {
+	var count int64
+	if err := dbh.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return nil, fmt.Errorf("error in counting rows %s", err)
+	}
+
+	return &repository.Stats{Backend: "postgres", Records: count}, nil
+}
@@ -0,0 +1,486 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+// queryer runs queries either directly on the database or within a
+// transaction.
+type queryer interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type pgrepository struct {
+	dbh      *sql.DB
+	schema   string
+	validate *validator.Validate
+}
+
+func NewContentRepo(pdb *Database) repository.ContentRepository {
+	return &pgrepository{
+		dbh:      pdb.dbh,
+		schema:   pdb.schema,
+		validate: validator.New(),
+	}
+}
+
+func (pgr *pgrepository) GetContentBySlug(
+	ctx context.Context,
+	slug string,
+) (*model.ContentDoc, error) {
+	cntModel, err := scanContent(
+		pgr.dbh.QueryRowContext(ctx, ContentFindBySlug, slug),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.ContentDoc{NotFound: true}, nil
+		}
+
+		return cntModel, fmt.Errorf(
+			"error in getting content by slug name %s",
+			err,
+		)
+	}
+
+	return cntModel, nil
+}
+
+func (pgr *pgrepository) GetContent(
+	ctx context.Context,
+	cid int64,
+) (*model.ContentDoc, error) {
+	cntModel, err := scanContent(
+		pgr.dbh.QueryRowContext(ctx, ContentFindByID, cid),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.ContentDoc{NotFound: true}, nil
+		}
+
+		return cntModel, fmt.Errorf("error in reading document %s", err)
+	}
+
+	return cntModel, nil
+}
+
+func (pgr *pgrepository) AddContent(
+	ctx context.Context,
+	cattr *content.NewContentAttributes,
+) (*model.ContentDoc, error) {
+	return pgr.add(ctx, pgr.dbh, cattr)
+}
+
+func (pgr *pgrepository) EditContent(
+	ctx context.Context,
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	return pgr.edit(ctx, pgr.dbh, cid, cattr)
+}
+
+func (pgr *pgrepository) DeleteContent(
+	ctx context.Context,
+	cid int64,
+) error {
+	_, err := pgr.remove(ctx, pgr.dbh, cid)
+
+	return err
+}
+
+func (pgr *pgrepository) ListContents(
+	ctx context.Context,
+	namespaces []string,
+) ([]*model.ContentDoc, error) {
+	if namespaces == nil {
+		namespaces = make([]string, 0)
+	}
+
+	return queryContents(
+		ctx,
+		pgr.dbh,
+		ContentList,
+		pq.Array(namespaces),
+	)
+}
+
+func (pgr *pgrepository) ImportContent(
+	ctx context.Context,
+	cnt *model.ContentDoc,
+	overwrite bool,
+) (*model.ContentDoc, bool, error) {
+	cid, err := strconv.ParseInt(cnt.Key, 10, 64)
+	if err != nil {
+		return cnt, false, fmt.Errorf("error in parsing key %s", err)
+	}
+	if !overwrite {
+		exist, err := pgr.GetContent(ctx, cid)
+		if err != nil {
+			return cnt, false, err
+		}
+		if !exist.NotFound {
+			return exist, false, nil
+		}
+	}
+	if err := pgr.validate.Struct(cnt); err != nil {
+		return cnt, false, fmt.Errorf("error in validating content %s", err)
+	}
+	cntModel, err := scanContent(pgr.dbh.QueryRowContext(
+		ctx, ContentUpsert,
+		cid, cnt.Name, cnt.Slug, cnt.Namespace, cnt.CreatedBy,
+		cnt.UpdatedBy, cnt.Content, jsonDocument(cnt.Content),
+		model.HashContent(cnt.Content), cnt.CreatedOn, cnt.UpdatedOn,
+	))
+	if err != nil {
+		return cnt, false, fmt.Errorf("error in importing content %s", err)
+	}
+	if _, err := pgr.dbh.ExecContext(ctx, ContentSyncSequence); err != nil {
+		return cntModel, true, fmt.Errorf(
+			"error in updating id sequence %s",
+			err,
+		)
+	}
+
+	return cntModel, true, nil
+}
+
+// CountByNamespace returns the number of contents in every namespace.
+func (pgr *pgrepository) CountByNamespace(
+	ctx context.Context,
+) (map[string]int64, error) {
+	rows, err := pgr.dbh.QueryContext(ctx, ContentCountByNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("error in counting contents %s", err)
+	}
+	defer rows.Close()
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var namespace string
+		var total int64
+		if err := rows.Scan(&namespace, &total); err != nil {
+			return nil, fmt.Errorf("error in reading row %s", err)
+		}
+		counts[namespace] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in iterating rows %s", err)
+	}
+
+	return counts, nil
+}
+
+// TransferContents copies or moves the contents of a namespace within a
+// single transaction, either all of them are transferred or none.
+func (pgr *pgrepository) TransferContents(
+	ctx context.Context,
+	trn *model.ContentTransfer,
+) ([]*model.ContentDoc, error) {
+	rewrite, err := model.SlugRewriter(trn.SlugPattern, trn.SlugReplacement)
+	if err != nil {
+		return nil, err
+	}
+	slugs := trn.Slugs
+	if slugs == nil {
+		slugs = make([]string, 0)
+	}
+	var cntModels []*model.ContentDoc
+	err = pgr.inTransaction(ctx, func(ctx context.Context, txn *sql.Tx) error {
+		existing, err := queryContents(
+			ctx, txn, ContentListByNamespace, trn.From, pq.Array(slugs),
+		)
+		if err != nil {
+			return err
+		}
+		cntModels = make([]*model.ContentDoc, 0, len(existing))
+		for _, cnt := range existing {
+			var row *sql.Row
+			if trn.Move {
+				row = txn.QueryRowContext(
+					ctx, ContentMove,
+					cnt.Key, rewrite(cnt.Slug), trn.To, trn.UpdatedBy,
+				)
+			} else {
+				row = txn.QueryRowContext(
+					ctx, ContentCopy,
+					cnt.Name, rewrite(cnt.Slug), trn.To,
+					cnt.CreatedBy, trn.UpdatedBy, cnt.Content,
+					jsonDocument(cnt.Content), model.HashContent(cnt.Content),
+				)
+			}
+			cntModel, err := scanContent(row)
+			if err != nil {
+				return fmt.Errorf(
+					"error in transferring content %s %s",
+					cnt.Slug,
+					err,
+				)
+			}
+			cntModels = append(cntModels, cntModel)
+		}
+
+		return nil
+	})
+
+	return cntModels, err
+}
+
+// BatchContents runs a list of create, update and delete operations. In
+// atomic mode all of them run in a single transaction and the first failure
+// rolls back the entire batch. Otherwise every operation is run on its own
+// and its failure is recorded in the corresponding result.
+func (pgr *pgrepository) BatchContents(
+	ctx context.Context,
+	ops []*model.BatchOperation,
+	atomic bool,
+) ([]*model.BatchResult, error) {
+	if !atomic {
+		results := make([]*model.BatchResult, 0, len(ops))
+		for _, bop := range ops {
+			cntModel, err := pgr.runOperation(ctx, pgr.dbh, bop)
+			results = append(results, &model.BatchResult{
+				Action:  bop.Action,
+				Content: cntModel,
+				Err:     err,
+			})
+		}
+
+		return results, nil
+	}
+	var results []*model.BatchResult
+	err := pgr.inTransaction(ctx, func(ctx context.Context, txn *sql.Tx) error {
+		results = make([]*model.BatchResult, 0, len(ops))
+		for idx, bop := range ops {
+			cntModel, err := pgr.runOperation(ctx, txn, bop)
+			if err != nil {
+				return fmt.Errorf("error in batch operation %d %s", idx, err)
+			}
+			results = append(results, &model.BatchResult{
+				Action:  bop.Action,
+				Content: cntModel,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Drop removes the schema along with all of its tables.
+func (pgr *pgrepository) Drop() error {
+	return dropSchema(pgr.dbh, pgr.schema)
+}
+
+// Ping checks that the database is reachable.
+func (pgr *pgrepository) Ping(
+	ctx context.Context,
+) error {
+	if err := pgr.dbh.PingContext(ctx); err != nil {
+		return fmt.Errorf("error in reaching database %s", err)
+	}
+
+	return nil
+}
+
+// Close closes the pool of database connections.
+func (pgr *pgrepository) Close() error {
+	if err := pgr.dbh.Close(); err != nil {
+		return fmt.Errorf("error in closing database %s", err)
+	}
+
+	return nil
+}
+
+// Stats reports the number of rows in the content table.
+func (pgr *pgrepository) Stats(
+	ctx context.Context,
+) (*repository.Stats, error) {
+	return countRows(ctx, pgr.dbh, ContentCount)
+}
+
+func (pgr *pgrepository) inTransaction(
+	ctx context.Context,
+	fn func(context.Context, *sql.Tx) error,
+) error {
+	txn, err := pgr.dbh.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error in starting transaction %s", err)
+	}
+	if err := fn(ctx, txn); err != nil {
+		//nolint:errcheck
+		txn.Rollback()
+
+		return err
+	}
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("error in committing transaction %s", err)
+	}
+
+	return nil
+}
+
+func (pgr *pgrepository) runOperation(
+	ctx context.Context,
+	qry queryer,
+	bop *model.BatchOperation,
+) (*model.ContentDoc, error) {
+	switch bop.Action {
+	case model.BatchCreate:
+		return pgr.add(ctx, qry, bop.Create)
+	case model.BatchUpdate:
+		return pgr.edit(ctx, qry, bop.ID, bop.Update)
+	case model.BatchDelete:
+		return pgr.remove(ctx, qry, bop.ID)
+	}
+
+	return nil, fmt.Errorf("unknown batch action %s", bop.Action)
+}
+
+func (pgr *pgrepository) add(
+	ctx context.Context,
+	qry queryer,
+	cattr *content.NewContentAttributes,
+) (*model.ContentDoc, error) {
+	doc := &model.ContentDoc{
+		Name:      cattr.Name,
+		Slug:      cattr.Slug,
+		Namespace: cattr.Namespace,
+		CreatedBy: cattr.CreatedBy,
+		UpdatedBy: cattr.CreatedBy,
+		Content:   cattr.Content,
+	}
+	if err := pgr.validate.Struct(doc); err != nil {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in creating new content %s",
+			err,
+		)
+	}
+	cntModel, err := scanContent(qry.QueryRowContext(
+		ctx, ContentInsert,
+		cattr.Name, cattr.Slug, cattr.Namespace, cattr.CreatedBy, cattr.Content,
+		jsonDocument(cattr.Content), model.HashContent(cattr.Content),
+	))
+	if err != nil {
+		return cntModel, fmt.Errorf("error in creating new content %s", err)
+	}
+
+	return cntModel, nil
+}
+
+func (pgr *pgrepository) edit(
+	ctx context.Context,
+	qry queryer,
+	cid int64,
+	cattr *content.ExistingContentAttributes,
+) (*model.ContentDoc, error) {
+	if err := pgr.validate.Var(cattr.UpdatedBy, "required,email"); err != nil {
+		return &model.ContentDoc{}, fmt.Errorf(
+			"error in updating content %s",
+			err,
+		)
+	}
+	cntModel, err := scanContent(qry.QueryRowContext(
+		ctx, ContentUpdate,
+		cid, cattr.UpdatedBy, cattr.Content, jsonDocument(cattr.Content),
+		model.HashContent(cattr.Content),
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cntModel, fmt.Errorf(
+				"error in updating content, document with ID %d not found",
+				cid,
+			)
+		}
+
+		return cntModel, fmt.Errorf("error in updating content %s", err)
+	}
+
+	return cntModel, nil
+}
+
+func (pgr *pgrepository) remove(
+	ctx context.Context,
+	qry queryer,
+	cid int64,
+) (*model.ContentDoc, error) {
+	cntModel, err := scanContent(qry.QueryRowContext(ctx, ContentDelete, cid))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cntModel, fmt.Errorf("document with ID %d not found", cid)
+		}
+
+		return cntModel, fmt.Errorf("error in removing document %s", err)
+	}
+
+	return cntModel, nil
+}
+
+// jsonDocument is the value of the jsonb content column, the body itself when
+// it is json and null otherwise, the body column keeps it verbatim.
+func jsonDocument(body string) interface{} {
+	if !json.Valid([]byte(body)) {
+		return nil
+	}
+
+	return body
+}
+
+func queryContents(
+	ctx context.Context,
+	qry queryer,
+	query string,
+	args ...interface{},
+) ([]*model.ContentDoc, error) {
+	rows, err := qry.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error in running query %s", err)
+	}
+	defer rows.Close()
+	cntModels := make([]*model.ContentDoc, 0)
+	for rows.Next() {
+		cntModel, err := scanContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error in reading row %s", err)
+		}
+		cntModels = append(cntModels, cntModel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in iterating rows %s", err)
+	}
+
+	return cntModels, nil
+}
+
+func scanContent(row scanner) (*model.ContentDoc, error) {
+	cntModel := &model.ContentDoc{}
+	var cid int64
+	err := row.Scan(
+		&cid, &cntModel.Name, &cntModel.Slug, &cntModel.Namespace,
+		&cntModel.CreatedBy, &cntModel.UpdatedBy, &cntModel.Content,
+		&cntModel.Hash, &cntModel.CreatedOn, &cntModel.UpdatedOn,
+	)
+	if err != nil {
+		return cntModel, err
+	}
+	cntModel.Key = strconv.FormatInt(cid, 10)
+
+	return cntModel, nil
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	manager "github.com/dictyBase/arangomanager"
+	"github.com/dictyBase/modware-content/internal/model"
+	"github.com/dictyBase/modware-content/internal/repository/conformance"
+	"github.com/stretchr/testify/require"
+)
+
+// connParamsFromEnv reads the connection attributes from the POSTGRES_*
+// environment variables, every test gets its own schema.
+func connParamsFromEnv() (*ConnectParams, error) {
+	cnp := &ConnectParams{
+		SSLMode: "disable",
+		Schema:  "test_" + strings.ToLower(manager.RandomString(10, 12)),
+	}
+	for env, val := range map[string]*string{
+		"POSTGRES_USER":     &cnp.User,
+		"POSTGRES_PASS":     &cnp.Pass,
+		"POSTGRES_DATABASE": &cnp.Database,
+		"POSTGRES_HOST":     &cnp.Host,
+	} {
+		if len(os.Getenv(env)) == 0 {
+			return cnp, fmt.Errorf("env %s is not set", env)
+		}
+		*val = os.Getenv(env)
+	}
+	port, err := strconv.Atoi(os.Getenv("POSTGRES_PORT"))
+	if err != nil {
+		return cnp, fmt.Errorf("error in reading env POSTGRES_PORT %s", err)
+	}
+	cnp.Port = port
+
+	return cnp, nil
+}
+
+// openDatabase connects to a new schema that is dropped along with the test.
+func openDatabase(t *testing.T) *Database {
+	t.Helper()
+	cnp, err := connParamsFromEnv()
+	if err != nil {
+		t.Fatalf("unable to read postgres connection parameters %s", err)
+	}
+	pdb, err := Open(cnp)
+	require.NoErrorf(t, err, "expect no error connecting to database %s", err)
+	t.Cleanup(func() {
+		_ = dropSchema(pdb.dbh, pdb.schema)
+		_ = pdb.Close()
+	})
+
+	return pdb
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	conformance.RunAll(t, func(t *testing.T) *conformance.Repositories {
+		t.Helper()
+		pdb := openDatabase(t)
+
+		return &conformance.Repositories{
+			Content: NewContentRepo(pdb),
+			Audit:   NewAuditRepo(pdb),
+			Locks:   NewLockRepo(pdb),
+			Assets:  NewAssetRepo(pdb),
+			Links:   NewLinkRepo(pdb),
+		}
+	})
+}
+
+func TestNamespace(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := require.New(t)
+	repo := NewNamespaceRepo(openDatabase(t))
+	nsp, err := repo.AddNamespace(ctx, &model.NamespaceDoc{
+		Name:        "dsc",
+		DisplayName: "Dicty Stock Center",
+		CreatedBy:   "content@content.org",
+	})
+	assert.NoErrorf(err, "expect no error from creating namespace %s", err)
+	assert.Equal(nsp.Name, "dsc", "name should match")
+	assert.False(nsp.Archived, "should not be archived")
+	_, err = repo.AddNamespace(ctx, &model.NamespaceDoc{
+		Name:        "dsc",
+		DisplayName: "Duplicate",
+		CreatedBy:   "content@content.org",
+	})
+	assert.Error(err, "expect error from duplicate namespace")
+	anp, err := repo.ArchiveNamespace(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from archiving namespace %s", err)
+	assert.True(anp.Archived, "should be archived")
+	gnp, err := repo.GetNamespace(ctx, "dsc")
+	assert.NoErrorf(err, "expect no error from getting namespace %s", err)
+	assert.True(gnp.Archived, "should be archived")
+	mnp, err := repo.GetNamespace(ctx, "dictybase")
+	assert.NoErrorf(err, "expect no error from missing namespace %s", err)
+	assert.True(mnp.NotFound, "should not be found")
+	nsps, err := repo.ListNamespaces(ctx)
+	assert.NoErrorf(err, "expect no error from listing namespaces %s", err)
+	assert.Len(nsps, 1, "should have one namespace")
+}
+
+func TestDSN(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	cnp := &ConnectParams{
+		Host:     "localhost",
+		Port:     5432,
+		User:     "content",
+		Pass:     `s3cret pass' sslmode=require \`,
+		Database: "dictycontent",
+	}
+	assert.Equal(
+		`host='localhost' port=5432 user='content' `+
+			`password='s3cret pass\' sslmode=require \\' `+
+			`dbname='dictycontent' sslmode='disable'`,
+		cnp.DSN(),
+		"expect every value to be quoted",
+	)
+	cnp.Schema = "content"
+	assert.True(
+		strings.HasSuffix(cnp.DSN(), " search_path=content"),
+		"expect the schema as search path",
+	)
+}
@@ -0,0 +1,243 @@
+package postgres
+
+const (
+	contentColumns = `
+		id, name, slug, namespace, created_by, updated_by,
+		body, hash, created_on, updated_on
+	`
+
+	ContentFindBySlug = `SELECT ` + contentColumns + `
+		FROM content WHERE slug = $1
+	`
+
+	ContentFindByID = `SELECT ` + contentColumns + `
+		FROM content WHERE id = $1
+	`
+
+	ContentInsert = `
+		INSERT INTO content (
+			name, slug, namespace, created_by, updated_by, body, content, hash
+		) VALUES ($1, $2, $3, $4, $4, $5, $6, $7)
+		RETURNING ` + contentColumns
+
+	ContentCopy = `
+		INSERT INTO content (
+			name, slug, namespace, created_by, updated_by, body, content, hash
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + contentColumns
+
+	ContentUpdate = `
+		UPDATE content SET
+			updated_by = $2, body = $3, content = $4, hash = $5,
+			updated_on = now()
+		WHERE id = $1
+		RETURNING ` + contentColumns
+
+	ContentMove = `
+		UPDATE content SET
+			slug = $2, namespace = $3, updated_by = $4, updated_on = now()
+		WHERE id = $1
+		RETURNING ` + contentColumns
+
+	ContentDelete = `
+		DELETE FROM content WHERE id = $1
+		RETURNING ` + contentColumns
+
+	ContentList = `SELECT ` + contentColumns + `
+		FROM content
+		WHERE cardinality($1::text[]) = 0 OR namespace = ANY($1)
+		ORDER BY namespace, slug
+	`
+
+	ContentListByNamespace = `SELECT ` + contentColumns + `
+		FROM content
+		WHERE namespace = $1
+		AND (cardinality($2::text[]) = 0 OR slug = ANY($2))
+		ORDER BY slug
+		FOR UPDATE
+	`
+
+	ContentCount = `SELECT COUNT(*) FROM content`
+
+	ContentCountByNamespace = `
+		SELECT namespace, COUNT(*) FROM content GROUP BY namespace
+	`
+
+	ContentUpsert = `
+		INSERT INTO content (
+			id, name, slug, namespace, created_by, updated_by, body, content,
+			hash, created_on, updated_on
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			slug = EXCLUDED.slug,
+			namespace = EXCLUDED.namespace,
+			created_by = EXCLUDED.created_by,
+			updated_by = EXCLUDED.updated_by,
+			body = EXCLUDED.body,
+			content = EXCLUDED.content,
+			hash = EXCLUDED.hash,
+			created_on = EXCLUDED.created_on,
+			updated_on = EXCLUDED.updated_on
+		RETURNING ` + contentColumns
+
+	// keeps the id sequence ahead of the ids of imported contents.
+	ContentSyncSequence = `
+		SELECT setval(
+			pg_get_serial_sequence('content', 'id'),
+			GREATEST((SELECT MAX(id) FROM content), 1)
+		)
+	`
+
+	namespaceColumns = `
+		id, name, display_name, editors_group, content_schema, archived,
+		created_by, created_on, updated_on
+	`
+
+	NamespaceFind = `SELECT ` + namespaceColumns + `
+		FROM namespace WHERE name = $1
+	`
+
+	NamespaceList = `SELECT ` + namespaceColumns + `
+		FROM namespace ORDER BY name
+	`
+
+	NamespaceCount = `SELECT COUNT(*) FROM namespace`
+
+	NamespaceInsert = `
+		INSERT INTO namespace (
+			name, display_name, editors_group, content_schema, created_by
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + namespaceColumns
+
+	NamespaceArchive = `
+		UPDATE namespace SET archived = true, updated_on = now()
+		WHERE name = $1
+		RETURNING ` + namespaceColumns
+
+	auditColumns = `
+		id, actor, operation, content_id, slug, namespace, from_slug,
+		from_namespace, before_hash, after_hash, client::text, created_on
+	`
+
+	AuditInsert = `
+		INSERT INTO audit (
+			actor, operation, content_id, slug, namespace, from_slug,
+			from_namespace, before_hash, after_hash, client
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::jsonb)
+		RETURNING ` + auditColumns
+
+	// LIMIT NULL returns all the rows
+	AuditList = `SELECT ` + auditColumns + `
+		FROM audit
+		WHERE ($1 = '' OR actor = $1)
+		AND ($2 = '' OR namespace = $2 OR from_namespace = $2)
+		AND ($3::timestamptz IS NULL OR created_on >= $3)
+		AND ($4::timestamptz IS NULL OR created_on <= $4)
+		ORDER BY id
+		LIMIT NULLIF($5::integer, 0)
+	`
+
+	AuditCount = `SELECT COUNT(*) FROM audit`
+
+	lockColumns = `content_id, holder, acquired_on, expires_on`
+
+	// the lease is taken over when it has lapsed and extended when it is
+	// held by the same holder, any other holder returns no row
+	LockAcquire = `
+		INSERT INTO content_lock (content_id, holder, acquired_on, expires_on)
+		VALUES ($1, $2, now(), now() + $3 * interval '1 millisecond')
+		ON CONFLICT (content_id) DO UPDATE SET
+			holder = EXCLUDED.holder,
+			acquired_on = CASE
+				WHEN content_lock.expires_on <= now() THEN now()
+				ELSE content_lock.acquired_on
+			END,
+			expires_on = EXCLUDED.expires_on
+		WHERE content_lock.holder = EXCLUDED.holder
+		OR content_lock.expires_on <= now()
+		RETURNING ` + lockColumns
+
+	LockRenew = `
+		UPDATE content_lock
+		SET expires_on = now() + $3 * interval '1 millisecond'
+		WHERE content_id = $1 AND holder = $2 AND expires_on > now()
+		RETURNING ` + lockColumns
+
+	LockRelease = `
+		DELETE FROM content_lock WHERE content_id = $1 AND holder = $2
+	`
+
+	LockBreak = `
+		DELETE FROM content_lock WHERE content_id = $1
+		RETURNING ` + lockColumns
+
+	LockFind = `SELECT ` + lockColumns + `
+		FROM content_lock WHERE content_id = $1 AND expires_on > now()
+	`
+
+	LockCount = `SELECT COUNT(*) FROM content_lock`
+
+	assetColumns = `id, name, mime_type, size, checksum, blob_key,
+		created_by, created_on`
+
+	AssetInsert = `
+		INSERT INTO asset (name, mime_type, size, checksum, blob_key, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + assetColumns
+
+	AssetFind = `SELECT ` + assetColumns + `,
+		ARRAY(
+			SELECT content_id FROM asset_reference
+			WHERE asset_id = asset.id ORDER BY content_id
+		)
+		FROM asset WHERE id = $1
+	`
+
+	AssetRemove = `DELETE FROM asset WHERE id = $1`
+
+	// the references of the content that are kept are left alone and the
+	// ids of missing assets never match a row
+	AssetReference = `
+		WITH removed AS (
+			DELETE FROM asset_reference
+			WHERE content_id = $1 AND NOT (asset_id = ANY($2))
+		)
+		INSERT INTO asset_reference (asset_id, content_id)
+		SELECT id, $1 FROM asset WHERE id = ANY($2)
+		ON CONFLICT DO NOTHING
+	`
+
+	AssetCount = `SELECT COUNT(*) FROM asset`
+
+	linkColumns = `content_id, slug, namespace, target`
+
+	// the kept links get the current slug and namespace of the content
+	LinkReplace = `
+		WITH removed AS (
+			DELETE FROM content_link
+			WHERE content_id = $1 AND NOT (target = ANY($4))
+		)
+		INSERT INTO content_link (` + linkColumns + `)
+		SELECT $1, $2, $3, UNNEST($4::text[])
+		ON CONFLICT (content_id, target) DO UPDATE SET
+			slug = EXCLUDED.slug,
+			namespace = EXCLUDED.namespace
+	`
+
+	LinkOutgoing = `SELECT ` + linkColumns + `
+		FROM content_link WHERE content_id = $1 ORDER BY target
+	`
+
+	LinkBacklinks = `SELECT ` + linkColumns + `
+		FROM content_link WHERE target = $1 ORDER BY content_id
+	`
+
+	LinkList = `SELECT ` + linkColumns + `
+		FROM content_link
+		WHERE $1 = '' OR namespace = $1
+		ORDER BY content_id, target
+	`
+
+	LinkCount = `SELECT COUNT(*) FROM content_link`
+)
@@ -1,19 +1,168 @@
 package repository
 
 import (
-	manager "github.com/dictyBase/arangomanager"
+	"context"
+	"errors"
+	"time"
+
 	"github.com/dictyBase/go-genproto/dictybaseapis/content"
 	"github.com/dictyBase/modware-content/internal/model"
 )
 
+// ContentRepository stores the contents, every method honors the
+// cancellation and deadline of the given context.
 type ContentRepository interface {
-	GetContentBySlug(slug string) (*model.ContentDoc, error)
-	GetContent(cid int64) (*model.ContentDoc, error)
-	AddContent(cnt *content.NewContentAttributes) (*model.ContentDoc, error)
+	GetContentBySlug(ctx context.Context, slug string) (*model.ContentDoc, error)
+	GetContent(ctx context.Context, cid int64) (*model.ContentDoc, error)
+	AddContent(
+		ctx context.Context,
+		cnt *content.NewContentAttributes,
+	) (*model.ContentDoc, error)
 	EditContent(
+		ctx context.Context,
 		cid int64,
 		cnt *content.ExistingContentAttributes,
 	) (*model.ContentDoc, error)
-	DeleteContent(cid int64) error
-	Dbh() *manager.Database
+	DeleteContent(ctx context.Context, cid int64) error
+	TransferContents(
+		ctx context.Context,
+		trn *model.ContentTransfer,
+	) ([]*model.ContentDoc, error)
+	ListContents(
+		ctx context.Context,
+		namespaces []string,
+	) ([]*model.ContentDoc, error)
+	ImportContent(
+		ctx context.Context,
+		cnt *model.ContentDoc,
+		overwrite bool,
+	) (*model.ContentDoc, bool, error)
+	BatchContents(
+		ctx context.Context,
+		ops []*model.BatchOperation,
+		atomic bool,
+	) ([]*model.BatchResult, error)
+	// CountByNamespace returns the number of contents in every namespace
+	CountByNamespace(ctx context.Context) (map[string]int64, error)
+	Lifecycle
+}
+
+// NamespaceRepository stores the registry of namespaces, every method honors
+// the cancellation and deadline of the given context.
+type NamespaceRepository interface {
+	AddNamespace(
+		ctx context.Context,
+		nsp *model.NamespaceDoc,
+	) (*model.NamespaceDoc, error)
+	GetNamespace(ctx context.Context, name string) (*model.NamespaceDoc, error)
+	ListNamespaces(ctx context.Context) ([]*model.NamespaceDoc, error)
+	ArchiveNamespace(
+		ctx context.Context,
+		name string,
+	) (*model.NamespaceDoc, error)
+	Lifecycle
+}
+
+// AuditRepository is an append only log of the content mutations, there is
+// no way to change or remove an entry once it is added.
+type AuditRepository interface {
+	AddEntry(
+		ctx context.Context,
+		ent *model.AuditEntry,
+	) (*model.AuditEntry, error)
+	// ListEntries returns the entries selected by the filter, oldest first
+	ListEntries(
+		ctx context.Context,
+		flt *model.AuditFilter,
+	) ([]*model.AuditEntry, error)
+	Lifecycle
+}
+
+var (
+	// ErrLockHeld is returned when the lock of a content is held by another
+	// user.
+	ErrLockHeld = errors.New("lock is held by another user")
+	// ErrLockNotHeld is returned when the user does not hold the lock.
+	ErrLockNotHeld = errors.New("lock is not held by the user")
+)
+
+// LockRepository keeps the edit leases of the contents, a content has at
+// most one lease and a lapsed lease counts as no lease.
+type LockRepository interface {
+	// AcquireLock takes the lease for the holder, a lease already held by
+	// the holder is extended. ErrLockHeld is returned when another user
+	// holds the lease.
+	AcquireLock(
+		ctx context.Context,
+		cid int64,
+		holder string,
+		ttl time.Duration,
+	) (*model.LockDoc, error)
+	// RenewLock extends the lease, ErrLockNotHeld is returned when the
+	// holder has no live lease.
+	RenewLock(
+		ctx context.Context,
+		cid int64,
+		holder string,
+		ttl time.Duration,
+	) (*model.LockDoc, error)
+	// ReleaseLock gives up the lease, ErrLockNotHeld is returned when the
+	// holder has no lease.
+	ReleaseLock(ctx context.Context, cid int64, holder string) error
+	// BreakLock removes the lease whoever holds it and returns it
+	BreakLock(ctx context.Context, cid int64) (*model.LockDoc, error)
+	// GetLock returns the live lease, NotFound is set when there is none
+	GetLock(ctx context.Context, cid int64) (*model.LockDoc, error)
+	Lifecycle
+}
+
+// AssetRepository stores the metadata of the uploaded files along with the
+// contents that embed them.
+type AssetRepository interface {
+	AddAsset(ctx context.Context, ast *model.AssetDoc) (*model.AssetDoc, error)
+	// GetAsset returns the asset with its references, NotFound is set when
+	// there is none
+	GetAsset(ctx context.Context, aid int64) (*model.AssetDoc, error)
+	// RemoveAsset deletes the asset and its references
+	RemoveAsset(ctx context.Context, aid int64) error
+	// SetReferences replaces the assets embedded by the content, the ids
+	// of missing assets are ignored. An empty list clears the references.
+	SetReferences(ctx context.Context, cid int64, aids []int64) error
+	Lifecycle
+}
+
+// LinkRepository keeps the internal links between the contents, a link
+// names its target by slug and stays when the target is missing.
+type LinkRepository interface {
+	// SetLinks replaces the links of the source content with links to the
+	// targets, no targets clears them
+	SetLinks(ctx context.Context, src *model.LinkDoc, targets []string) error
+	// OutgoingLinks returns the links of the content ordered by target
+	OutgoingLinks(ctx context.Context, cid int64) ([]*model.LinkDoc, error)
+	// Backlinks returns the links to the slug ordered by the linking content
+	Backlinks(ctx context.Context, slug string) ([]*model.LinkDoc, error)
+	// ListLinks returns the links of the contents in the namespace, all of
+	// them when empty, ordered by the linking content and target
+	ListLinks(ctx context.Context, namespace string) ([]*model.LinkDoc, error)
+	Lifecycle
+}
+
+// Lifecycle manages the storage behind a repository independent of the
+// backend.
+type Lifecycle interface {
+	// Ping checks that the storage is reachable
+	Ping(ctx context.Context) error
+	// Close releases the connections and file handles of the storage
+	Close() error
+	// Drop removes all the stored data along with the underlying storage,
+	// meant for cleaning up after tests
+	Drop() error
+	// Stats summarizes the stored data
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+// Stats is a summary of the data stored in a repository.
+type Stats struct {
+	Backend string `json:"backend"`
+	Records int64  `json:"records"`
 }
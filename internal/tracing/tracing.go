@@ -0,0 +1,146 @@
+// Package tracing sets up the opentelemetry tracer provider of the service
+// and carries the trace context across the published messages.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterNone turns off tracing.
+	ExporterNone = "none"
+	// ExporterOTLP sends the spans to an otlp collector over grpc.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the spans as json to stdout or a file.
+	ExporterStdout = "stdout"
+	// Name of the tracer used by the service.
+	Name = "github.com/dictyBase/modware-content"
+)
+
+// Params are the attributes for setting up tracing.
+type Params struct {
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	File        string
+	SampleRatio float64
+	ServiceName string
+}
+
+// ShutdownFunc flushes the pending spans and stops the exporter.
+type ShutdownFunc func(context.Context) error
+
+// Setup registers the global tracer provider and the w3c trace context
+// propagator. With ExporterNone the spans are not recorded.
+func Setup(ctx context.Context, prm *Params) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	exp, closer, err := exporter(ctx, prm)
+	if err != nil {
+		return nil, err
+	}
+	if exp == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(prm.ServiceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error in creating trace resource %s", err)
+	}
+	tpr := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(
+			sdktrace.ParentBased(sdktrace.TraceIDRatioBased(prm.SampleRatio)),
+		),
+	)
+	otel.SetTracerProvider(tpr)
+
+	return func(ctx context.Context) error {
+		if err := tpr.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error in shutting down tracer %s", err)
+		}
+
+		return closer.Close()
+	}, nil
+}
+
+// Tracer returns the tracer of the service from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(Name)
+}
+
+func exporter(
+	ctx context.Context,
+	prm *Params,
+) (sdktrace.SpanExporter, io.Closer, error) {
+	switch prm.Exporter {
+	case ExporterNone, "":
+		return nil, nil, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{}
+		if len(prm.Endpoint) > 0 {
+			opts = append(opts, otlptracegrpc.WithEndpoint(prm.Endpoint))
+		}
+		if prm.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exp, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error in creating otlp exporter %s", err)
+		}
+
+		return exp, nopWriteCloser{io.Discard}, nil
+	case ExporterStdout:
+		var out io.WriteCloser = nopWriteCloser{os.Stdout}
+		if len(prm.File) > 0 {
+			fh, err := os.OpenFile(
+				prm.File,
+				os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+				0o644,
+			)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error in opening trace file %s", err)
+			}
+			out = fh
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(out))
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"error in creating stdout exporter %s",
+				err,
+			)
+		}
+
+		return exp, out, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported trace exporter %s", prm.Exporter)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupStdoutFile(t *testing.T) {
+	assert := require.New(t)
+	file := filepath.Join(t.TempDir(), "trace.json")
+	shutdown, err := Setup(context.Background(), &Params{
+		Exporter:    ExporterStdout,
+		File:        file,
+		SampleRatio: 1,
+		ServiceName: "modware-content",
+	})
+	assert.NoErrorf(err, "expect no error from tracing setup %s", err)
+	_, span := Tracer().Start(context.Background(), "ContentFindBySlug")
+	span.End()
+	assert.NoError(shutdown(context.Background()), "expect no error on shutdown")
+	data, err := os.ReadFile(file)
+	assert.NoErrorf(err, "expect no error from reading trace file %s", err)
+	assert.True(
+		strings.Contains(string(data), `"Name":"ContentFindBySlug"`),
+		"should write the span to the file",
+	)
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), &Params{Exporter: "jaeger"})
+	require.Error(t, err, "expect error from unsupported exporter")
+}
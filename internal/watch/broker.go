@@ -0,0 +1,222 @@
+// Package watch fans out the content events received from the messaging
+// server to in-process watchers, so the changes made through any replica are
+// watched. Every event gets a sequence number and the latest events are
+// retained, so a watcher that got disconnected could resume from the last
+// sequence it has seen.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/dictyBase/modware-content/internal/message"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+
+	// DefaultRetain is the number of the latest events kept for resuming.
+	DefaultRetain = 1000
+	// buffered live events of a watcher, a watcher that falls further
+	// behind is dropped.
+	watchBuffer = 64
+)
+
+var (
+	// ErrExpired is returned for a resume from a sequence that is no longer
+	// retained, the watcher should read the contents afresh.
+	ErrExpired = errors.New("sequence is no longer retained")
+	// ErrLagged is the error of a subscription that was dropped for falling
+	// behind, it could be resumed from the last sequence seen.
+	ErrLagged = errors.New("watcher fell behind")
+)
+
+// Event is a change of a content, for a delete the content is the removed
+// one.
+type Event struct {
+	Sequence uint64           `json:"sequence"`
+	Action   string           `json:"action"`
+	Content  *content.Content `json:"content"`
+	Time     time.Time        `json:"time"`
+}
+
+// Filter selects the events of a namespace and or the slugs with a prefix,
+// an empty field matches all.
+type Filter struct {
+	Namespace  string
+	SlugPrefix string
+}
+
+// Match reports whether the event passes the filter.
+func (flt *Filter) Match(evt *Event) bool {
+	if flt == nil {
+		return true
+	}
+	attr := evt.Content.GetData().GetAttributes()
+	if len(flt.Namespace) > 0 && attr.GetNamespace() != flt.Namespace {
+		return false
+	}
+
+	return strings.HasPrefix(attr.GetSlug(), flt.SlugPrefix)
+}
+
+// Broker feeds the content events of its handlers to the watchers.
+type Broker struct {
+	mutex    sync.Mutex
+	actions  map[string]string
+	retain   int
+	events   []*Event
+	sequence uint64
+	watchers map[*Subscription]bool
+}
+
+// NewBroker creates a broker, actions maps the subjects of the content
+// events to their action. A retain of zero or less keeps DefaultRetain
+// events.
+func NewBroker(actions map[string]string, retain int) *Broker {
+	if retain <= 0 {
+		retain = DefaultRetain
+	}
+
+	return &Broker{
+		actions:  actions,
+		retain:   retain,
+		events:   make([]*Event, 0, retain),
+		watchers: make(map[*Subscription]bool),
+	}
+}
+
+// Handlers returns the handlers that hand the events of the content
+// subjects to the watchers, keyed by their subject. They are meant to be
+// subscribed to the messaging server.
+func (brk *Broker) Handlers() map[string]message.EventHandler {
+	hdls := make(map[string]message.EventHandler)
+	for subject, action := range brk.actions {
+		action := action
+		hdls[subject] = func(_ context.Context, cont *content.Content) {
+			brk.dispatch(action, cont)
+		}
+	}
+
+	return hdls
+}
+
+// Sequence returns the sequence of the latest event.
+func (brk *Broker) Sequence() uint64 {
+	brk.mutex.Lock()
+	defer brk.mutex.Unlock()
+
+	return brk.sequence
+}
+
+// Subscribe starts watching the events that pass the filter. The retained
+// events after the given sequence are delivered first, a zero sequence
+// watches only the events to come.
+func (brk *Broker) Subscribe(flt *Filter, after uint64) (*Subscription, error) {
+	brk.mutex.Lock()
+	defer brk.mutex.Unlock()
+	if after > brk.sequence {
+		return nil, fmt.Errorf(
+			"sequence %d is beyond the latest %d",
+			after,
+			brk.sequence,
+		)
+	}
+	backlog := make([]*Event, 0)
+	if after > 0 {
+		if len(brk.events) > 0 && after+1 < brk.events[0].Sequence {
+			return nil, fmt.Errorf(
+				"error in resuming from %d %w",
+				after,
+				ErrExpired,
+			)
+		}
+		for _, evt := range brk.events {
+			if evt.Sequence > after && flt.Match(evt) {
+				backlog = append(backlog, evt)
+			}
+		}
+	}
+	sub := &Subscription{
+		broker: brk,
+		filter: flt,
+		events: make(chan *Event, len(backlog)+watchBuffer),
+	}
+	for _, evt := range backlog {
+		sub.events <- evt
+	}
+	brk.watchers[sub] = true
+
+	return sub, nil
+}
+
+func (brk *Broker) dispatch(action string, cont *content.Content) {
+	brk.mutex.Lock()
+	defer brk.mutex.Unlock()
+	brk.sequence++
+	evt := &Event{
+		Sequence: brk.sequence,
+		Action:   action,
+		Content:  cont,
+		Time:     time.Now().UTC(),
+	}
+	if len(brk.events) == brk.retain {
+		brk.events = append(brk.events[:0], brk.events[1:]...)
+	}
+	brk.events = append(brk.events, evt)
+	for sub := range brk.watchers {
+		if !sub.filter.Match(evt) {
+			continue
+		}
+		select {
+		case sub.events <- evt:
+		default:
+			brk.drop(sub, ErrLagged)
+		}
+	}
+}
+
+// drop ends the subscription, the lock of the broker should be held.
+func (brk *Broker) drop(sub *Subscription, err error) {
+	delete(brk.watchers, sub)
+	sub.err = err
+	close(sub.events)
+}
+
+// Subscription delivers the watched events in the order of their sequence.
+type Subscription struct {
+	broker *Broker
+	filter *Filter
+	events chan *Event
+	err    error
+}
+
+// Events is closed once the subscription ends.
+func (sub *Subscription) Events() <-chan *Event {
+	return sub.events
+}
+
+// Err returns ErrLagged if the subscription was dropped for falling behind,
+// it should be read after Events is closed.
+func (sub *Subscription) Err() error {
+	sub.broker.mutex.Lock()
+	defer sub.broker.mutex.Unlock()
+
+	return sub.err
+}
+
+// Close stops watching, closing more than once is harmless.
+func (sub *Subscription) Close() {
+	sub.broker.mutex.Lock()
+	defer sub.broker.mutex.Unlock()
+	if sub.broker.watchers[sub] {
+		sub.broker.drop(sub, nil)
+	}
+}
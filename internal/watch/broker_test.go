@@ -0,0 +1,115 @@
+package watch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictyBase/go-genproto/dictybaseapis/content"
+	"github.com/stretchr/testify/require"
+)
+
+var actions = map[string]string{
+	"ContentService.Create": ActionCreate,
+	"ContentService.Update": ActionUpdate,
+	"ContentService.Delete": ActionDelete,
+}
+
+func newContent(cid int64, slug, namespace string) *content.Content {
+	return &content.Content{
+		Data: &content.ContentData{
+			Id: cid,
+			Attributes: &content.ContentAttributes{
+				Slug:      slug,
+				Namespace: namespace,
+			},
+		},
+	}
+}
+
+func publishAll(brk *Broker, subject string, ctnts ...*content.Content) {
+	hdl, ok := brk.Handlers()[subject]
+	if !ok {
+		return
+	}
+	for _, ctnt := range ctnts {
+		hdl(context.Background(), ctnt)
+	}
+}
+
+func sequences(sub *Subscription, count int) []uint64 {
+	seqs := make([]uint64, 0, count)
+	for idx := 0; idx < count; idx++ {
+		seqs = append(seqs, (<-sub.Events()).Sequence)
+	}
+
+	return seqs
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	brk := NewBroker(actions, 10)
+	all, err := brk.Subscribe(nil, 0)
+	assert.NoErrorf(err, "expect no error from subscribing %s", err)
+	dsc, err := brk.Subscribe(&Filter{Namespace: "dsc"}, 0)
+	assert.NoErrorf(err, "expect no error from subscribing %s", err)
+	faq, err := brk.Subscribe(&Filter{SlugPrefix: "faq-"}, 0)
+	assert.NoErrorf(err, "expect no error from subscribing %s", err)
+	publishAll(brk, "ContentService.Create", newContent(1, "faq-order", "dsc"))
+	publishAll(brk, "ContentService.Update", newContent(2, "intro", "dsc"))
+	publishAll(brk, "ContentService.Delete", newContent(3, "faq-help", "dfp"))
+	publishAll(brk, "ContentService.Lock", newContent(4, "faq-lock", "dsc"))
+	assert.Equal(brk.Sequence(), uint64(3), "should only count content events")
+	assert.Equal(sequences(all, 3), []uint64{1, 2, 3}, "should get all")
+	assert.Equal(sequences(dsc, 2), []uint64{1, 2}, "should match namespace")
+	evts := []*Event{<-faq.Events(), <-faq.Events()}
+	assert.Equal(evts[0].Action, ActionCreate, "should map subject to action")
+	assert.Equal(evts[1].Action, ActionDelete, "should map subject to action")
+	assert.Equal(evts[1].Content.Data.Id, int64(3), "should match the content")
+	faq.Close()
+	faq.Close()
+	_, ok := <-faq.Events()
+	assert.False(ok, "expect events to be closed")
+	assert.NoError(faq.Err(), "expect no error for closed subscription")
+}
+
+func TestResume(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	brk := NewBroker(actions, 3)
+	for cid := int64(1); cid <= 5; cid++ {
+		publishAll(brk, "ContentService.Update", newContent(cid, "intro", "dsc"))
+	}
+	sub, err := brk.Subscribe(nil, 3)
+	assert.NoErrorf(err, "expect no error from resuming %s", err)
+	publishAll(brk, "ContentService.Update", newContent(6, "intro", "dsc"))
+	assert.Equal(sequences(sub, 3), []uint64{4, 5, 6}, "should replay backlog")
+	_, err = brk.Subscribe(nil, 1)
+	assert.ErrorIs(err, ErrExpired, "expect error for dropped sequence")
+	_, err = brk.Subscribe(nil, 9)
+	assert.Error(err, "expect error for sequence beyond the latest")
+}
+
+func TestLagged(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+	brk := NewBroker(actions, 0)
+	sub, err := brk.Subscribe(nil, 0)
+	assert.NoErrorf(err, "expect no error from subscribing %s", err)
+	for cid := int64(0); cid <= watchBuffer; cid++ {
+		publishAll(brk, "ContentService.Update", newContent(cid, "intro", "dsc"))
+	}
+	count := 0
+	for range sub.Events() {
+		count++
+	}
+	assert.Equal(count, watchBuffer, "should deliver up to the buffer")
+	assert.ErrorIs(sub.Err(), ErrLagged, "expect lagged error")
+	resumed, err := brk.Subscribe(nil, uint64(count))
+	assert.NoErrorf(err, "expect no error from resuming %s", err)
+	assert.Equal(
+		(<-resumed.Events()).Sequence,
+		uint64(watchBuffer+1),
+		"should resume after the last seen",
+	)
+}